@@ -2,14 +2,23 @@
 package haustorium
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"strings"
+
+	"github.com/farcloser/primordium/fault"
 
 	"github.com/farcloser/haustorium/internal/audit/bitdepth"
+	"github.com/farcloser/haustorium/internal/audit/byteorder"
 	"github.com/farcloser/haustorium/internal/audit/clipping"
 	"github.com/farcloser/haustorium/internal/audit/dcoffset"
 	"github.com/farcloser/haustorium/internal/audit/dropout"
 	"github.com/farcloser/haustorium/internal/audit/loudness"
+	"github.com/farcloser/haustorium/internal/audit/repeat"
+	"github.com/farcloser/haustorium/internal/audit/shared"
 	"github.com/farcloser/haustorium/internal/audit/silence"
 	"github.com/farcloser/haustorium/internal/audit/spectral"
 	"github.com/farcloser/haustorium/internal/audit/stereo"
@@ -41,6 +50,11 @@ result, err := haustorium.Analyze(factory, format, opts)
 opts := haustorium.OptionsForSource(haustorium.SourceVinyl)
 result, err := haustorium.Analyze(factory, format, opts)
 
+// Genre-aware dynamic range (classical expects more headroom than EDM)
+opts := haustorium.DefaultOptions()
+opts.Genre = haustorium.GenreClassical
+result, err := haustorium.Analyze(factory, format, opts)
+
 // Iterate issues
 for _, issue := range result.Issues {
     if issue.Detected {
@@ -55,6 +69,52 @@ if result.Stereo != nil {
 
 */
 
+// plateauMinEvents is how many plateaus at a single level it takes to call it clip-then-normalize.
+const plateauMinEvents = 10
+
+// Clipping polarity: the share of a channel's events at one polarity for it to count as one-sided, and how many
+// events it takes to judge.
+const (
+	clippingAsymmetryShare     = 0.9
+	clippingAsymmetryMinEvents = 10
+)
+
+// clippingHarmonicsDb is the odd harmonic level (relative to a dominant tone) above which flat tops are confirmed
+// as distortion. A clean 16-bit full-scale sine stays around -100 dB; 5% clipping already reaches -40 dB.
+const clippingHarmonicsDb = -60.0
+
+// De-essing: the fraction of sibilants that must show a 5-9 kHz duck, and how many sibilants it takes to judge.
+const (
+	deEssPumpingThreshold = 0.5
+	deEssMinEvents        = 30
+)
+
+// A channel imbalance whose per-window spread stays under imbalanceSteadyDb is a steady gain offset; one spreading
+// over imbalanceErraticDb comes and goes, like a channel cutting in and out.
+const (
+	imbalanceSteadyDb  = 0.75
+	imbalanceErraticDb = 2.0
+)
+
+// loudnessLowCoverage is the gated coverage below which the integrated loudness is flagged as unreliable.
+const loudnessLowCoverage = 0.5
+
+// ispRegionConcentrated is the share of the ISP spectra's energy, in a single region, above which ISPs are reported
+// as concentrated there. Overshoots from a hot, broadband master spread out (around 0.05).
+const ispRegionConcentrated = 0.3
+
+// oversPervasiveNotable is the share of blocks with inter-sample overshoots above which the ISP summary notes
+// that the whole track was limited. Unlimited material stays near zero; limited masters reach 0.7 and more.
+const oversPervasiveNotable = 0.5
+
+// transientsClippedNotable is the share of transients clipped flat above which the dynamic range summary notes it.
+// Unclipped material stays near zero, whatever its limiting.
+const transientsClippedNotable = 0.25
+
+// SpectralTiltTypicalDbPerOct is the tilt of a typical commercial master, in dB/octave relative to pink noise:
+// a little duller than equal energy per octave. The spectral tilt check grades the distance from it.
+const SpectralTiltTypicalDbPerOct = -2.0
+
 // Check represents a high-level audio quality check.
 type Check int
 
@@ -76,17 +136,41 @@ const (
 	CheckLoudness
 	CheckDynamicRange
 	CheckDropouts
+	CheckUndithered
+	CheckDeEssing
+	CheckBassRolloff
+	CheckVinylCutSafety
+	CheckRepeats
+
+	// CheckPitchOffset is optional, in no preset: tuning offsets are mostly artistic choices, and are only judged
+	// on analog sources, where they betray a speed error.
+	CheckPitchOffset
+
+	// CheckWidthChanges is informational: mono and stereo sections within one track are reported, not graded.
+	CheckWidthChanges
+
+	// CheckTrimmedLeadIn reads the truncation head window and the leading silence: a track starting abruptly,
+	// mid-note, after a silence trimmer cut into its first onset.
+	CheckTrimmedLeadIn
+
+	// CheckSpectralTilt grades the tonal balance of the whole track: an average spectrum far brighter (a harsh EQ)
+	// or far duller (a muffled master) than commercial masters typically are.
+	CheckSpectralTilt
 
 	// Presets.
 	ChecksDefects = CheckClipping | CheckTruncation | CheckFakeBitDepth |
 		CheckFakeSampleRate | CheckLossyTranscode | CheckDCOffset |
 		CheckFakeStereo | CheckPhaseIssues | CheckInvertedPhase |
 		CheckChannelImbalance | CheckSilencePadding | CheckHum |
-		CheckNoiseFloor | CheckInterSamplePeaks | CheckDropouts
+		CheckNoiseFloor | CheckInterSamplePeaks | CheckDropouts |
+		CheckUndithered | CheckRepeats | CheckWidthChanges | CheckTrimmedLeadIn
 
 	ChecksLoudness = CheckLoudness | CheckDynamicRange | CheckInterSamplePeaks
 
-	ChecksAll = ChecksDefects | ChecksLoudness
+	// ChecksMix are mixing/mastering choices rather than defects of the file.
+	ChecksMix = CheckDeEssing | CheckBassRolloff | CheckVinylCutSafety | CheckSpectralTilt
+
+	ChecksAll = ChecksDefects | ChecksLoudness | ChecksMix
 )
 
 func (c Check) String() string {
@@ -125,6 +209,24 @@ func (c Check) String() string {
 		return "dynamic-range"
 	case CheckDropouts:
 		return "dropouts"
+	case CheckUndithered:
+		return "undithered"
+	case CheckDeEssing:
+		return "de-essing"
+	case CheckBassRolloff:
+		return "bass-rolloff"
+	case CheckVinylCutSafety:
+		return "vinyl-cut-safety"
+	case CheckRepeats:
+		return "repeats"
+	case CheckPitchOffset:
+		return "pitch-offset"
+	case CheckWidthChanges:
+		return "width-changes"
+	case CheckTrimmedLeadIn:
+		return "trimmed-lead-in"
+	case CheckSpectralTilt:
+		return "spectral-tilt"
 	}
 
 	return "unknown"
@@ -155,6 +257,22 @@ func (s Severity) String() string {
 	return "unknown"
 }
 
+// ParseSeverity converts a severity name to a Severity value.
+func ParseSeverity(severity string) (Severity, error) {
+	switch severity {
+	case "none", "":
+		return SeverityNone, nil
+	case "mild":
+		return SeverityMild, nil
+	case "moderate":
+		return SeverityModerate, nil
+	case "severe":
+		return SeveritySevere, nil
+	default:
+		return 0, fmt.Errorf("unknown severity %q (valid: none, mild, moderate, severe)", severity)
+	}
+}
+
 // Issue represents a detected problem.
 type Issue struct {
 	Check      Check
@@ -162,6 +280,13 @@ type Issue struct {
 	Severity   Severity
 	Summary    string  // human-readable summary
 	Confidence float64 // 0.0-1.0
+
+	// NotApplicable is set when the check cannot say anything about this input (e.g. bit depth of a lossy source).
+	NotApplicable bool
+
+	// Per-channel breakdown of a detected clipping, DC offset or dropouts issue, e.g. "L: 5, R: 2".
+	// Empty for other checks, undetected issues and mono input.
+	AffectedChannels string
 }
 
 // Bands defines severity thresholds for a check. Direction is implicit:
@@ -211,6 +336,15 @@ func (b Bands) Match(value float64) (Severity, bool) {
 type Options struct {
 	Checks Check // which checks to run (default: ChecksAll)
 
+	// Source is the source type the options were made for. Pitch offsets are only judged on analog sources.
+	Source Source
+
+	// Genre, when set, replaces the DynamicRange bands with genre-typical expectations.
+	Genre Genre
+
+	// Profile is the workflow the options were made for (OptionsForProfile), if any. Informational.
+	Profile Profile
+
 	// Severity bands per check (zero value = use defaults).
 	Clipping         Bands
 	Truncation       Bands
@@ -223,11 +357,97 @@ type Options struct {
 	ISP              Bands
 	DynamicRange     Bands
 	Dropouts         Bands
+	Repeats          Bands
+	PitchOffset      Bands // absolute offset in cents
+	BassRolloff      Bands
+	SpectralTilt     Bands // distance of the tilt from SpectralTiltTypicalDbPerOct, in dB/octave
+	Loudness         Bands // excess over the loudness target or true peak ceiling, in dB; see LoudnessTargetLUFS
 
 	// Analyzer thresholds (not severity bands).
 	TranscodeSharpnessDb  float64 // default 30
 	UpsampleSharpnessDb   float64 // default 40
 	DropoutDeltaThreshold float64 // default 0.5
+	DropoutDCWindowMs     float64 // DC tracking window for DC jumps; default 50
+
+	// Loudness measurement windows: the BS.1770 momentary (default 400 ms) and short-term (default 3000 ms)
+	// windows, their hop (default 100 ms), and the DR blocks (default 3000 ms). See loudness.Options: any other
+	// value makes the loudness, loudness range and DR results non-standard.
+	LoudnessMomentaryMs float64
+	LoudnessShortTermMs float64
+	LoudnessHopMs       float64
+	LoudnessDRBlockMs   float64
+
+	// Spectral reference band (0 = 1-10 kHz). See spectral.Options.
+	SpectralReferenceLowHz  float64
+	SpectralReferenceHighHz float64
+
+	// PitchReferenceHz is the tuning reference (A4) for pitch offsets. Default 440.
+	PitchReferenceHz float64
+
+	// Noise floor measurement: the share of the quietest spectral windows it is measured on (default 0.2), and
+	// the RMS those windows must exceed to be used instead of the full track (default -50 dBFS).
+	// See spectral.Options.
+	NoiseQuietFraction float64
+	NoiseQuietGateDbFS float64
+
+	// LoudnessTargetLUFS, when set, grades the loudness check against a delivery target instead of reporting it
+	// as informational: the largest excess of the integrated loudness over the target, of the true peak over
+	// TruePeakCeilingDb, or of the short-term max over the target plus ShortTermAllowanceLU, is matched against the
+	// Loudness bands, and counts toward the worst severity. Default 0 (informational).
+	LoudnessTargetLUFS   float64
+	TruePeakCeilingDb    float64 // ceiling for the graded loudness check, in dBTP; default -1
+	ShortTermAllowanceLU float64 // how far the short-term max may exceed the target; default 5 (EBU R128 s1)
+
+	// EdgeNoiseFloor also measures the noise floor of the leading and trailing silence on its own
+	// (Spectral.LeadInNoiseFloorDb): with no music to mask it, the cleanest read on a transfer chain's noise.
+	// On by default for vinyl. Needs the silence analysis, which then runs even without CheckSilencePadding.
+	EdgeNoiseFloor bool
+
+	// SpectralThorough runs the spectral analysis on every window of the track instead of 100 windows spread
+	// across it. The sampled default is deterministic, but a short defect can fall between the windows, and in
+	// or out of them as the track length changes. Thorough coverage costs CPU and memory linear in the length:
+	// on a 5-minute track at 44.1 kHz, 32 times the windows, a spectral pass about 5 times slower, and some 100 MB
+	// of per-window spectra held at once.
+	SpectralThorough bool
+
+	// BitDepthSections also measures the effective bit depth of every second of the track, to find mixed-resolution
+	// files: genuine sections next to zero-padded ones (a hi-res release patched with a CD source), which read as
+	// genuine as a whole. It reads the whole track instead of stopping at the first genuine sample.
+	BitDepthSections bool
+
+	// SpectralWorkers bounds the goroutines computing the spectral windows: 0 = GOMAXPROCS, 1 = sequential.
+	// Results are identical whatever the count. Tools analyzing several files at once may prefer 1.
+	SpectralWorkers int
+
+	// MinConfidence is the confidence floor: detections below it are reported as not detected. Default 0 (off).
+	MinConfidence float64
+
+	// StartOffsetFrames is the position of the first analyzed frame in the original file. Event and segment
+	// positions (dropout events, silence segments, ISP density peak) are shifted by it, so that analyzing a
+	// segment reports positions on the original file's timeline.
+	StartOffsetFrames uint64
+
+	// TrimEdgesSec leaves the first and last seconds of the track out of every analyzer but truncation and silence
+	// padding, which are about the edges, and loudness, which measures the whole track: the leader noise, test
+	// tones and needle-drop thumps of a transfer otherwise trigger clipping, dropout or DC offset findings.
+	// Positions stay on the whole track's timeline.
+	// Tracks not longer than twice the trim are analyzed whole. Default 0 (off).
+	TrimEdgesSec float64
+
+	// StreamMaxBytes caps the PCM AnalyzeStream keeps in memory for its final Result. Past it, the block findings
+	// keep coming, but the final event fails with ErrStreamTooLong. Default 0: 2 GiB, about 3 hours of 16-bit
+	// stereo at 44.1 kHz, 45 minutes of 32-bit stereo at 96 kHz.
+	StreamMaxBytes int64
+
+	// SourceCodec is the codec the PCM was decoded from (ffprobe codec_name, e.g. "flac", "mp3"). Empty = unknown.
+	// For lossy codecs, fake-bit-depth and fake-sample-rate are reported as not applicable: a decoder outputs
+	// whatever depth and rate it is asked for, and there is no original to be genuine to.
+	SourceCodec string
+
+	// PostProcess, when set, is called with the result at the end of Analyze, after the built-in interpretation:
+	// the raw results are populated and the issues graded, so that it can add issues, adjust severities, or compute
+	// a domain-specific score, without decoding again. It is kept when Checks is 0 and the defaults are used.
+	PostProcess func(*Result)
 }
 
 // DefaultOptions returns DefaultDigitalOptions.
@@ -250,18 +470,34 @@ func DefaultDigitalOptions() Options {
 		ISP:              Bands{Mild: 1, Moderate: 100, Severe: 1000},
 		DynamicRange:     Bands{Mild: 8, Moderate: 6, Severe: 4},
 		Dropouts:         Bands{Mild: 1, Moderate: 5, Severe: 20},
+		Repeats:          Bands{Mild: 1, Moderate: 3, Severe: 10},
+		PitchOffset:      Bands{Mild: 10, Moderate: 20, Severe: 35},
+		BassRolloff:      Bands{Mild: 40, Moderate: 60, Severe: 80},
+		SpectralTilt:     Bands{Mild: 2.5, Moderate: 3.5, Severe: 5},
+		Loudness:         Bands{Mild: 0.5, Moderate: 2, Severe: 4},
 
 		TranscodeSharpnessDb:  30,
 		UpsampleSharpnessDb:   40,
 		DropoutDeltaThreshold: 0.5,
+		DropoutDCWindowMs:     50,
+		LoudnessMomentaryMs:   400,
+		LoudnessShortTermMs:   3000,
+		LoudnessHopMs:         100,
+		LoudnessDRBlockMs:     3000,
+		NoiseQuietFraction:    0.2,
+		NoiseQuietGateDbFS:    -50,
+		TruePeakCeilingDb:     -1,
+		ShortTermAllowanceLU:  5,
 	}
 }
 
 // DefaultVinylOptions returns options for vinyl rips.
 // Higher tolerance for noise, hum, DC offset, silence padding, dropouts,
-// and channel imbalance (early stereo mixes used hard panning).
+// channel imbalance (early stereo mixes used hard panning), and spectral tilt (the RIAA chain and the stylus
+// dull the top end).
 func DefaultVinylOptions() Options {
 	opts := DefaultDigitalOptions()
+	opts.Source = SourceVinyl
 	opts.Truncation = Bands{Mild: -30, Moderate: -20, Severe: -10}
 	opts.DCOffset = Bands{Mild: -26, Moderate: -13, Severe: 0}
 	opts.ChannelImbalance = Bands{Mild: 3, Moderate: 6, Severe: 10}
@@ -269,7 +505,9 @@ func DefaultVinylOptions() Options {
 	opts.Hum = Bands{Mild: 20, Moderate: 30, Severe: 40}
 	opts.NoiseFloor = Bands{Mild: -20, Moderate: -10, Severe: 0}
 	opts.Dropouts = Bands{Mild: 5, Moderate: 15, Severe: 40}
+	opts.SpectralTilt = Bands{Mild: 3.5, Moderate: 4.5, Severe: 6}
 	opts.DropoutDeltaThreshold = 0.7
+	opts.EdgeNoiseFloor = true
 
 	return opts
 }
@@ -278,6 +516,7 @@ func DefaultVinylOptions() Options {
 // Higher tolerance for ambient noise, PA hum, silence padding, and DC offset.
 func DefaultLiveOptions() Options {
 	opts := DefaultDigitalOptions()
+	opts.Source = SourceLive
 	opts.Truncation = Bands{Mild: -30, Moderate: -20, Severe: -10}
 	opts.DCOffset = Bands{Mild: -30, Moderate: -20, Severe: -10}
 	opts.SilencePadding = Bands{Mild: 5, Moderate: 10, Severe: 20}
@@ -336,37 +575,130 @@ func OptionsForSource(source Source) Options {
 	}
 }
 
+// Genre adjusts dynamic range expectations. What counts as "compressed" for a symphony
+// is perfectly normal for a club track.
+type Genre int
+
+const (
+	GenreUnspecified Genre = iota // Use the DynamicRange bands as configured (default).
+	GenreClassical                // Orchestral, chamber, choral. Expects DR14+.
+	GenreJazz                     // Acoustic jazz, blues, folk. Expects DR12+.
+	GenreRock                     // Rock, metal, indie.
+	GenrePop                      // Modern pop, hip-hop, R&B. DR6-8 is typical.
+	GenreElectronic               // EDM, dance. Dense by design.
+)
+
+func (g Genre) String() string {
+	switch g {
+	case GenreUnspecified:
+		return ""
+	case GenreClassical:
+		return "classical"
+	case GenreJazz:
+		return "jazz"
+	case GenreRock:
+		return "rock"
+	case GenrePop:
+		return "pop"
+	case GenreElectronic:
+		return "electronic"
+	}
+
+	return "unknown"
+}
+
+// ParseGenre converts a string to a Genre value.
+func ParseGenre(genre string) (Genre, error) {
+	switch genre {
+	case "":
+		return GenreUnspecified, nil
+	case "classical":
+		return GenreClassical, nil
+	case "jazz":
+		return GenreJazz, nil
+	case "rock":
+		return GenreRock, nil
+	case "pop":
+		return GenrePop, nil
+	case "electronic":
+		return GenreElectronic, nil
+	default:
+		return 0, fmt.Errorf("unknown genre %q (valid: classical, jazz, rock, pop, electronic)", genre)
+	}
+}
+
+// DynamicRangeForGenre returns the DynamicRange bands for the given genre.
+// GenreUnspecified returns the default bands.
+func DynamicRangeForGenre(genre Genre) Bands {
+	switch genre {
+	case GenreClassical:
+		return Bands{Mild: 13, Moderate: 10, Severe: 8}
+	case GenreJazz:
+		return Bands{Mild: 11, Moderate: 9, Severe: 7}
+	case GenreRock:
+		return Bands{Mild: 8, Moderate: 6, Severe: 4}
+	case GenrePop:
+		return Bands{Mild: 6, Moderate: 5, Severe: 4}
+	case GenreElectronic:
+		return Bands{Mild: 5, Moderate: 4, Severe: 3}
+	default:
+		return DefaultOptions().DynamicRange
+	}
+}
+
 // Result contains all analysis results.
 type Result struct {
 	// High-level issues (what the user asked for)
 	Issues []Issue
 
 	// Quick access booleans
-	HasClipping         bool
-	HasTruncation       bool
-	HasFakeBitDepth     bool
-	HasFakeSampleRate   bool
-	HasLossyTranscode   bool
-	HasDCOffset         bool
-	HasFakeStereo       bool
-	HasPhaseIssues      bool
-	HasInvertedPhase    bool
-	HasChannelImbalance bool
-	HasSilencePadding   bool
-	HasHum              bool
-	HasHighNoiseFloor   bool
-	HasInterSamplePeaks bool
-	HasDropouts         bool
-	IsBrickwalled       bool
+	HasClipping          bool
+	HasTruncation        bool
+	HasTrimmedLeadIn     bool
+	HasFakeBitDepth      bool
+	HasFakeSampleRate    bool
+	HasLossyTranscode    bool
+	HasDCOffset          bool
+	HasFakeStereo        bool
+	HasPhaseIssues       bool
+	HasInvertedPhase     bool
+	HasChannelImbalance  bool
+	HasSilencePadding    bool
+	HasHum               bool
+	HasHighNoiseFloor    bool
+	HasInterSamplePeaks  bool
+	HasDropouts          bool
+	HasRepeats           bool
+	HasUndithered        bool
+	HasDeEssPumping      bool
+	HasBassRolloff       bool
+	HasSpectralTilt      bool
+	HasVinylCutRisk      bool
+	HasPitchOffset       bool
+	IsBrickwalled        bool
+	IsOverLoudnessTarget bool // only with Options.LoudnessTargetLUFS
 
 	// Summary
 	IssueCount    int
 	WorstSeverity Severity
 
+	// EffectiveOptions are the options the verdicts were reached with, defaults applied: the source, the bands
+	// and the analyzer thresholds, so that a stored result is self-describing.
+	EffectiveOptions Options
+
+	// ByteSwapSuspected is set when the samples read as white noise, but as audio once byte-swapped: the input's
+	// byte order likely does not match the format's, and the verdicts describe noise rather than the recording.
+	ByteSwapSuspected bool
+
+	// Warnings name the requested checks that could not run, and why ("stereo checks skipped: 6-channel input"):
+	// their verdicts are missing from Issues, not passed.
+	Warnings []string
+
 	// Raw analysis results (for inspection, nil if not requested)
 	Clipping   *types.ClippingDetection
 	Truncation *types.TruncationDetection
 	BitDepth   *types.BitDepthAuthenticity
+	Dither     *types.DitherResult
 	Spectral   *types.SpectralResult
 	DCOffset   *types.DCOffsetResult
 	Stereo     *types.StereoResult
@@ -374,32 +706,135 @@ type Result struct {
 	TruePeak   *types.TruePeakResult
 	Loudness   *types.LoudnessResult
 	Dropout    *types.DropoutResult
+	Repeat     *types.RepeatResult
+}
+
+// SeverityVector returns the severity of every check, one entry per Check in bit order (clipping first, spectral
+// tilt last): SeverityNone for checks that passed, were not run, or did not apply.
+func (r *Result) SeverityVector() []Severity {
+	var vector []Severity
+
+	for check := CheckClipping; check <= CheckSpectralTilt; check <<= 1 {
+		severity := SeverityNone
+
+		for _, issue := range r.Issues {
+			if issue.Check == check && issue.Detected && !issue.NotApplicable {
+				severity = issue.Severity
+
+				break
+			}
+		}
+
+		vector = append(vector, severity)
+	}
+
+	return vector
+}
+
+// SeverityCodes returns the SeverityVector as one digit per check (0 none to 3 severe), such as "2300...":
+// a fixed-width string to index and filter results without parsing their issues.
+func (r *Result) SeverityCodes() string {
+	var codes strings.Builder
+
+	for _, severity := range r.SeverityVector() {
+		codes.WriteByte(byte('0' + severity))
+	}
+
+	return codes.String()
 }
 
 // ReaderFactory provides fresh readers for multiple passes.
 type ReaderFactory func() (io.Reader, error)
 
+// AnalyzeContext is Analyze, stopped once ctx is canceled: the readers of factory then fail, and the analysis
+// returns ctx's error.
+func AnalyzeContext(ctx context.Context, factory ReaderFactory, format types.PCMFormat, opts Options) (
+	*Result, error,
+) {
+	result, err := Analyze(canceledBy(ctx, factory), format, opts)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	return result, err
+}
+
 // Analyze performs comprehensive audio analysis.
 func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Result, error) {
-	if opts.Checks == 0 {
-		opts = DefaultOptions()
-	}
+	opts = withDefaultChecks(opts)
 
 	applyDefaults(&opts)
 
-	result := &Result{}
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	format = format.WithLayout()
+
+	// Big-endian input is swapped once, here: every analyzer reads little-endian samples.
+	if format.ByteOrder == types.BigEndian {
+		factory = byteSwapped(factory, format)
+		format.ByteOrder = types.LittleEndian
+	}
+
+	// 24-in-32 samples are read without their padding byte: bits there are not the recording's.
+	var paddingUsed bool
+
+	if format.Layout == types.Layout24In32 {
+		factory = paddingCleared(factory, &paddingUsed)
+	}
+
+	if err := requireFrame(factory, format); err != nil {
+		return nil, err
+	}
+
+	// Sanity check: PCM read in the wrong byte order looks like full-scale white noise to every analyzer.
+	r, err := factory()
+	if err != nil {
+		return nil, err
+	}
+
+	swapped, err := byteorder.Swapped(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{EffectiveOptions: opts, ByteSwapSuspected: swapped}
+
+	// Truncation, silence padding and loudness read the whole track; every other analyzer skips the trimmed edges.
+	edges := factory
+
+	var trimmedFrames uint64
+
+	if opts.TrimEdgesSec > 0 {
+		factory, trimmedFrames, err = trimEdges(factory, format, opts.TrimEdgesSec)
+		if err != nil {
+			return nil, err
+		}
+
+		if trimmedFrames == 0 {
+			result.EffectiveOptions.TrimEdgesSec = 0
+		}
+	}
 
 	// Determine which low-level analyzers we need
 	needClipping := opts.Checks&CheckClipping != 0
-	needTruncation := opts.Checks&CheckTruncation != 0
+	needTruncation := opts.Checks&(CheckTruncation|CheckTrimmedLeadIn) != 0
 	needBitDepth := opts.Checks&CheckFakeBitDepth != 0
-	needSpectral := opts.Checks&(CheckFakeSampleRate|CheckLossyTranscode|CheckHum|CheckNoiseFloor) != 0
+	needDither := opts.Checks&CheckUndithered != 0
+	needSpectral := opts.Checks&(CheckFakeSampleRate|CheckLossyTranscode|CheckHum|CheckNoiseFloor|CheckDeEssing|
+		CheckBassRolloff|CheckPitchOffset|CheckSpectralTilt) != 0
 	needDCOffset := opts.Checks&CheckDCOffset != 0
-	needStereo := opts.Checks&(CheckFakeStereo|CheckPhaseIssues|CheckInvertedPhase|CheckChannelImbalance) != 0
-	needSilence := opts.Checks&CheckSilencePadding != 0
-	needTruePeak := opts.Checks&CheckInterSamplePeaks != 0
+	needStereo := opts.Checks&(CheckFakeStereo|CheckPhaseIssues|CheckInvertedPhase|CheckChannelImbalance|
+		CheckVinylCutSafety|CheckWidthChanges) != 0
+	needEdgeNoise := opts.EdgeNoiseFloor && opts.Checks&CheckNoiseFloor != 0
+	needSilence := opts.Checks&(CheckSilencePadding|CheckTrimmedLeadIn) != 0 || needEdgeNoise
+	// The graded loudness check compares the true peak to the ceiling.
+	needTruePeak := opts.Checks&CheckInterSamplePeaks != 0 ||
+		(opts.Checks&CheckLoudness != 0 && opts.LoudnessTargetLUFS != 0)
 	needLoudness := opts.Checks&(CheckLoudness|CheckDynamicRange) != 0
 	needDropout := opts.Checks&CheckDropouts != 0
+	needRepeat := opts.Checks&CheckRepeats != 0
 
 	// Run analyzers
 	if needClipping {
@@ -408,14 +843,14 @@ func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Resu
 			return nil, err
 		}
 
-		result.Clipping, err = clipping.Detect(r, format)
+		result.Clipping, err = clipping.Detect(r, format, clipping.Options{Plateaus: true})
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if needTruncation {
-		r, err := factory()
+		r, err := edges()
 		if err != nil {
 			return nil, err
 		}
@@ -425,6 +860,8 @@ func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Resu
 			if err != nil {
 				return nil, err
 			}
+		} else {
+			result.Warnings = append(result.Warnings, "truncation skipped: input not seekable")
 		}
 	}
 
@@ -434,58 +871,111 @@ func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Resu
 			return nil, err
 		}
 
-		result.BitDepth, err = bitdepth.Authenticity(r, format)
+		result.BitDepth, err = bitdepth.Authenticity(r, format, bitdepth.Options{Windowed: opts.BitDepthSections})
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if needSpectral {
+	if needDither {
 		r, err := factory()
 		if err != nil {
 			return nil, err
 		}
 
-		result.Spectral, err = spectral.AnalyzeV2(r, format, spectral.DefaultOptions())
+		result.Dither, err = bitdepth.Dither(r, format)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if needDCOffset {
+	// Silence runs first: the edge noise floor is measured on the silent edges it finds.
+	if needSilence {
+		r, err := edges()
+		if err != nil {
+			return nil, err
+		}
+
+		result.Silence, err = silence.Detect(r, format, silence.DefaultOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if needSpectral {
 		r, err := factory()
 		if err != nil {
 			return nil, err
 		}
 
-		result.DCOffset, err = dcoffset.Detect(r, format)
+		spectralOpts := spectral.DefaultOptions()
+		if opts.SpectralReferenceLowHz > 0 || opts.SpectralReferenceHighHz > 0 {
+			spectralOpts.ReferenceLowHz = opts.SpectralReferenceLowHz
+			spectralOpts.ReferenceHighHz = opts.SpectralReferenceHighHz
+		}
+
+		if opts.PitchReferenceHz > 0 {
+			spectralOpts.PitchReferenceHz = opts.PitchReferenceHz
+		}
+
+		if opts.NoiseQuietFraction > 0 {
+			spectralOpts.QuietWindowsFraction = opts.NoiseQuietFraction
+		}
+
+		if opts.NoiseQuietGateDbFS != 0 {
+			spectralOpts.QuietGateDbFS = opts.NoiseQuietGateDbFS
+		}
+
+		if opts.SpectralThorough {
+			spectralOpts.WindowsMax = spectral.WindowsAll
+		}
+
+		spectralOpts.Workers = opts.SpectralWorkers
+
+		if needEdgeNoise && result.Silence != nil {
+			rate := float64(format.SampleRate)
+			trimmed := int(trimmedFrames) //nolint:gosec // a fraction of the track length
+			spectralOpts.LeadInFrames = max(int(result.Silence.LeadingSec*rate)-trimmed, 0)
+			spectralOpts.LeadOutFrames = max(int(result.Silence.TrailingSec*rate)-trimmed, 0)
+		}
+
+		result.Spectral, err = spectral.AnalyzeV2(r, format, spectralOpts)
 		if err != nil {
 			return nil, err
 		}
+
+		if result.Spectral.Frames < uint64(spectralOpts.FFTSize) { //nolint:gosec // positive constant
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"spectral checks skipped: %d frames, shorter than one %d-frame FFT window",
+				result.Spectral.Frames, spectralOpts.FFTSize))
+		}
 	}
 
-	if needStereo && format.Channels == 2 {
+	if needDCOffset {
 		r, err := factory()
 		if err != nil {
 			return nil, err
 		}
 
-		result.Stereo, err = stereo.Analyze(r, format)
+		result.DCOffset, err = dcoffset.Detect(r, format)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if needSilence {
+	if needStereo && format.Channels == 2 {
 		r, err := factory()
 		if err != nil {
 			return nil, err
 		}
 
-		result.Silence, err = silence.Detect(r, format, silence.DefaultOptions())
+		result.Stereo, err = stereo.Analyze(r, format)
 		if err != nil {
 			return nil, err
 		}
+	} else if needStereo {
+		result.Warnings = append(result.Warnings, fmt.Sprintf(
+			"stereo checks skipped: %d-channel input", format.Channels))
 	}
 
 	if needTruePeak {
@@ -501,12 +991,17 @@ func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Resu
 	}
 
 	if needLoudness {
-		r, err := factory()
+		r, err := edges()
 		if err != nil {
 			return nil, err
 		}
 
-		result.Loudness, err = loudness.Analyze(r, format)
+		result.Loudness, err = loudness.Analyze(r, format, loudness.Options{
+			MomentaryMs: opts.LoudnessMomentaryMs,
+			ShortTermMs: opts.LoudnessShortTermMs,
+			DRBlockMs:   opts.LoudnessDRBlockMs,
+			HopMs:       opts.LoudnessHopMs,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -520,18 +1015,243 @@ func Analyze(factory ReaderFactory, format types.PCMFormat, opts Options) (*Resu
 
 		result.Dropout, err = dropout.DetectV2(r, format, dropout.Options{
 			DeltaThreshold: opts.DropoutDeltaThreshold,
+			DCWindowMs:     opts.DropoutDCWindowMs,
 		})
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if needRepeat {
+		r, err := factory()
+		if err != nil {
+			return nil, err
+		}
+
+		result.Repeat, err = repeat.Detect(r, format, repeat.DefaultOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.StartOffsetFrames > 0 || trimmedFrames > 0 {
+		offsetTimeline(result, opts.StartOffsetFrames, trimmedFrames, format.SampleRate)
+	}
+
+	if paddingUsed {
+		result.Warnings = append(result.Warnings,
+			"24-in-32 layout: the padding byte holds data, the samples are likely full 32-bit")
+	}
+
 	// Interpret results
 	interpretResults(result, opts)
+	localizeChannels(result, format.Channels)
+
+	if opts.PostProcess != nil {
+		opts.PostProcess(result)
+	}
 
 	return result, nil
 }
 
+// offsetTimeline moves every reported position by offset frames. The positions of the analyzers that skipped
+// the trimmed edges move by trimmed frames more: silence segments and the quietest passage count from the first
+// frame.
+func offsetTimeline(result *Result, offset, trimmed uint64, sampleRate int) {
+	wholeOffset, wholeOffsetSec := offset, float64(offset)/float64(sampleRate)
+
+	offset += trimmed
+	offsetSec := float64(offset) / float64(sampleRate)
+
+	if result.Dropout != nil {
+		for i := range result.Dropout.Events {
+			result.Dropout.Events[i].Frame += offset
+			result.Dropout.Events[i].TimeSec += offsetSec
+		}
+	}
+
+	if result.Stereo != nil {
+		for i := range result.Stereo.WidthTransitions {
+			result.Stereo.WidthTransitions[i].Frame += offset
+			result.Stereo.WidthTransitions[i].TimeSec += offsetSec
+		}
+	}
+
+	if result.BitDepth != nil && !result.BitDepth.ConsistentAcrossTrack {
+		result.BitDepth.FirstPaddedSec += offsetSec
+	}
+
+	if result.Repeat != nil {
+		for i := range result.Repeat.Events {
+			result.Repeat.Events[i].Frame += offset
+			result.Repeat.Events[i].TimeSec += offsetSec
+		}
+	}
+
+	if result.Silence != nil {
+		for i := range result.Silence.Segments {
+			segment := &result.Silence.Segments[i]
+			segment.StartSample += wholeOffset
+			segment.EndSample += wholeOffset
+			segment.StartSec += wholeOffsetSec
+			segment.EndSec += wholeOffsetSec
+		}
+	}
+
+	if result.TruePeak != nil && result.TruePeak.ISPCount > 0 {
+		result.TruePeak.WorstDensitySec += offsetSec
+	}
+
+	if result.Loudness != nil && result.Loudness.QuietestPassageDbFS > -120 {
+		result.Loudness.QuietestPassageSec += wholeOffsetSec
+	}
+}
+
+// byteSwapped returns a factory whose readers swap the bytes of every sample.
+func byteSwapped(factory ReaderFactory, format types.PCMFormat) ReaderFactory {
+	return func() (io.Reader, error) {
+		r, err := factory()
+		if err != nil {
+			return nil, err
+		}
+
+		return shared.NewByteSwapReader(r, int(format.BitDepth/8)), nil //nolint:gosec // validated small value
+	}
+}
+
+// paddingCleared returns a factory whose readers clear the padding byte of 24-in-32 samples, setting *used when
+// one was set.
+func paddingCleared(factory ReaderFactory, used *bool) ReaderFactory {
+	return func() (io.Reader, error) {
+		r, err := factory()
+		if err != nil {
+			return nil, err
+		}
+
+		return shared.NewPaddingReader(r, used), nil
+	}
+}
+
+// canceledBy returns a factory whose readers fail with ctx's error once ctx is canceled. Readers that seek still do
+// (truncation needs it).
+func canceledBy(ctx context.Context, factory ReaderFactory) ReaderFactory {
+	return func() (io.Reader, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		r, err := factory()
+		if err != nil {
+			return nil, err
+		}
+
+		reader := contextReader{ctx: ctx, reader: r}
+		if _, ok := r.(io.ReadSeeker); ok {
+			return &contextSeeker{contextReader: reader}, nil
+		}
+
+		return &reader, nil
+	}
+}
+
+type contextReader struct {
+	ctx    context.Context //nolint:containedctx // the reader stops with it
+	reader io.Reader
+}
+
+type contextSeeker struct {
+	contextReader
+}
+
+func (r *contextReader) Read(buf []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.reader.Read(buf)
+}
+
+func (r *contextSeeker) Seek(offset int64, whence int) (int64, error) {
+	return r.reader.(io.ReadSeeker).Seek(offset, whence) //nolint:forcetypeassert // checked by canceledBy
+}
+
+// trimEdges returns a factory whose readers skip the first and last sec seconds of the track, and the frames
+// skipped at the start. It returns factory and 0 when the track is not longer than twice the trim.
+func trimEdges(factory ReaderFactory, format types.PCMFormat, sec float64) (ReaderFactory, uint64, error) {
+	r, err := factory()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	size, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+	}
+
+	frameSize := int64(format.BitDepth/8) * int64(format.Channels) //nolint:gosec // validated small values
+	frames := size / frameSize
+	trimmed := int64(sec * float64(format.SampleRate))
+
+	if frames <= 2*trimmed {
+		return factory, 0, nil
+	}
+
+	offset, length := trimmed*frameSize, (frames-2*trimmed)*frameSize
+
+	return func() (io.Reader, error) {
+		r, err := factory()
+		if err != nil {
+			return nil, err
+		}
+
+		if at, ok := r.(io.ReaderAt); ok {
+			return io.NewSectionReader(at, offset, length), nil
+		}
+
+		if _, err := io.CopyN(io.Discard, r, offset); err != nil {
+			return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+
+		return io.LimitReader(r, length), nil
+	}, uint64(trimmed), nil
+}
+
+// requireFrame fails with ErrInsufficientData if the input does not hold at least one complete frame.
+func requireFrame(factory ReaderFactory, format types.PCMFormat) error {
+	r, err := factory()
+	if err != nil {
+		return err
+	}
+
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	frame := make([]byte, int(format.BitDepth/8)*int(format.Channels)) //nolint:gosec // validated small values
+
+	if _, err := io.ReadFull(r, frame); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return fmt.Errorf("%w: no complete frame", ErrInsufficientData)
+		}
+
+		return fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+	}
+
+	return nil
+}
+
+// withDefaultChecks returns the default options when no check is selected, keeping the caller's PostProcess hook.
+func withDefaultChecks(opts Options) Options {
+	if opts.Checks != 0 {
+		return opts
+	}
+
+	defaults := DefaultOptions()
+	defaults.PostProcess = opts.PostProcess
+
+	return defaults
+}
+
 func applyDefaults(opts *Options) {
 	defaults := DefaultOptions()
 	zeroBands := Bands{}
@@ -572,6 +1292,10 @@ func applyDefaults(opts *Options) {
 		opts.ISP = defaults.ISP
 	}
 
+	if opts.Genre != GenreUnspecified {
+		opts.DynamicRange = DynamicRangeForGenre(opts.Genre)
+	}
+
 	if opts.DynamicRange == zeroBands {
 		opts.DynamicRange = defaults.DynamicRange
 	}
@@ -580,7 +1304,47 @@ func applyDefaults(opts *Options) {
 		opts.Dropouts = defaults.Dropouts
 	}
 
-	if opts.TranscodeSharpnessDb == 0 {
+	if opts.Repeats == zeroBands {
+		opts.Repeats = defaults.Repeats
+	}
+
+	if opts.PitchOffset == zeroBands {
+		opts.PitchOffset = defaults.PitchOffset
+	}
+
+	if opts.BassRolloff == zeroBands {
+		opts.BassRolloff = defaults.BassRolloff
+	}
+
+	if opts.SpectralTilt == zeroBands {
+		opts.SpectralTilt = defaults.SpectralTilt
+	}
+
+	if opts.Loudness == zeroBands {
+		opts.Loudness = defaults.Loudness
+	}
+
+	if opts.TruePeakCeilingDb == 0 {
+		opts.TruePeakCeilingDb = defaults.TruePeakCeilingDb
+	}
+
+	if opts.ShortTermAllowanceLU == 0 {
+		opts.ShortTermAllowanceLU = defaults.ShortTermAllowanceLU
+	}
+
+	if opts.PitchReferenceHz <= 0 {
+		opts.PitchReferenceHz = spectral.PitchReferenceHz
+	}
+
+	if opts.NoiseQuietFraction <= 0 || opts.NoiseQuietFraction > 1 {
+		opts.NoiseQuietFraction = defaults.NoiseQuietFraction
+	}
+
+	if opts.NoiseQuietGateDbFS == 0 {
+		opts.NoiseQuietGateDbFS = defaults.NoiseQuietGateDbFS
+	}
+
+	if opts.TranscodeSharpnessDb == 0 {
 		opts.TranscodeSharpnessDb = defaults.TranscodeSharpnessDb
 	}
 
@@ -591,6 +1355,26 @@ func applyDefaults(opts *Options) {
 	if opts.DropoutDeltaThreshold == 0 {
 		opts.DropoutDeltaThreshold = defaults.DropoutDeltaThreshold
 	}
+
+	if opts.DropoutDCWindowMs == 0 {
+		opts.DropoutDCWindowMs = defaults.DropoutDCWindowMs
+	}
+
+	if opts.LoudnessMomentaryMs <= 0 {
+		opts.LoudnessMomentaryMs = defaults.LoudnessMomentaryMs
+	}
+
+	if opts.LoudnessShortTermMs <= 0 {
+		opts.LoudnessShortTermMs = defaults.LoudnessShortTermMs
+	}
+
+	if opts.LoudnessHopMs <= 0 {
+		opts.LoudnessHopMs = defaults.LoudnessHopMs
+	}
+
+	if opts.LoudnessDRBlockMs <= 0 {
+		opts.LoudnessDRBlockMs = defaults.LoudnessDRBlockMs
+	}
 }
 
 func interpretResults(result *Result, opts Options) {
@@ -615,13 +1399,34 @@ func interpretResults(result *Result, opts Options) {
 		default:
 		}
 
+		// Clipped, then normalized: no sample reaches full scale, but the flat tops survive at one consistent level.
+		// Plateaus scattered across levels are natural (slow waveforms at 16-bit), so only the dominant level counts.
+		if !detected && result.Clipping.PlateauLevelEvents >= plateauMinEvents {
+			severity, detected = opts.Clipping.Match(float64(result.Clipping.PlateauLevelEvents))
+			summary = fmt.Sprintf(
+				"%d flat-topped plateaus at %.1f dBFS (clipped, then gain-reduced)",
+				result.Clipping.PlateauLevelEvents,
+				result.Clipping.PlateauLevelDb,
+			)
+		}
+
+		confidence := 1.0
+
+		if detected {
+			var note string
+
+			confidence, note = corroborateClipping(result.Spectral)
+			summary += note + asymmetricClipping(result.Clipping) + clippingBand(result.Clipping) +
+				clippingRecoverability(result.Clipping)
+		}
+
 		result.HasClipping = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckClipping,
 			Detected:   detected,
 			Severity:   severity,
 			Summary:    summary,
-			Confidence: 1.0,
+			Confidence: confidence,
 		})
 	}
 
@@ -653,6 +1458,11 @@ func interpretResults(result *Result, opts Options) {
 		})
 	}
 
+	// Trimmed lead-in (no bands: how abrupt the start is)
+	if result.Truncation != nil && result.Silence != nil && opts.Checks&CheckTrimmedLeadIn != 0 {
+		interpretTrimmedLeadIn(result)
+	}
+
 	// Fake Bit Depth (binary detection, no bands)
 	if result.BitDepth != nil && opts.Checks&CheckFakeBitDepth != 0 {
 		detected := int(
@@ -660,20 +1470,34 @@ func interpretResults(result *Result, opts Options) {
 		) < int(
 			result.BitDepth.Claimed,
 		)
+		mixed := !detected && !result.BitDepth.ConsistentAcrossTrack
 
 		var (
 			severity Severity
 			summary  string
 		)
 
-		if detected {
+		switch {
+		case detected:
 			severity = SeveritySevere
 			summary = fmt.Sprintf(
 				"Fake %d-bit: actually %d-bit (zero-padded)",
 				result.BitDepth.Claimed,
 				result.BitDepth.Effective,
 			)
-		} else {
+		case mixed:
+			// Part of the track is genuine: the padded sections were patched in, from a lower-resolution source.
+			detected = true
+			severity = SeverityModerate
+			summary = fmt.Sprintf(
+				"Mixed %d-bit: %.0f%% of the track is %d-bit zero-padded, from %.1fs "+
+					"(sections from a lower-resolution source)",
+				result.BitDepth.Claimed,
+				100*float64(result.BitDepth.PaddedWindows)/float64(result.BitDepth.Windows),
+				result.BitDepth.PaddedEffective,
+				result.BitDepth.FirstPaddedSec,
+			)
+		default:
 			severity = SeverityNone
 			summary = fmt.Sprintf("Genuine %d-bit", result.BitDepth.Claimed)
 		}
@@ -688,6 +1512,61 @@ func interpretResults(result *Result, opts Options) {
 		})
 	}
 
+	// Undithered (binary detection, no bands)
+	if result.Dither != nil && opts.Checks&CheckUndithered != 0 {
+		// The fade-out on its own, unless the truncation check found the track cut: then there is no fade.
+		fadeout := result.Dither.FadeoutQuantizationLikely &&
+			(result.Truncation == nil || !result.Truncation.IsTruncated)
+		detected := result.Dither.UnditheredLikely || fadeout
+
+		var (
+			severity Severity
+			summary  string
+		)
+
+		switch {
+		case result.Dither.UnditheredLikely:
+			severity = SeverityMild
+			summary = fmt.Sprintf(
+				"Undithered bit-depth reduction: quantization tracks the signal in quiet passages (hold ratio %.2f)",
+				result.Dither.HoldRatio,
+			)
+
+			if fadeout {
+				summary += fmt.Sprintf(", fade-out included (%.2f)", result.Dither.FadeoutHoldRatio)
+			}
+		case fadeout:
+			severity = SeverityMild
+			summary = fmt.Sprintf(
+				"Undithered fade-out: quantization tracks the signal over the last %.1fs of the fade "+
+					"(hold ratio %.2f), audible as zipper noise",
+				result.Dither.FadeoutSec,
+				result.Dither.FadeoutHoldRatio,
+			)
+		case result.Dither.QuietWindows == 0:
+			severity = SeverityNone
+			summary = "No quiet passages to assess dither"
+		default:
+			severity = SeverityNone
+			summary = "Quiet passages properly dithered"
+		}
+
+		// Little quiet material means little evidence either way.
+		confidence := 0.8
+		if result.Dither.QuietSec < 5 {
+			confidence = 0.5
+		}
+
+		result.HasUndithered = detected
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckUndithered,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: confidence,
+		})
+	}
+
 	// Fake Sample Rate (binary detection, no bands)
 	if result.Spectral != nil && opts.Checks&CheckFakeSampleRate != 0 {
 		detected := result.Spectral.IsUpsampled
@@ -709,8 +1588,8 @@ func interpretResults(result *Result, opts Options) {
 			summary = fmt.Sprintf("Genuine %d Hz", result.Spectral.ClaimedRate)
 		}
 
-		// Base sample rates (44100, 48000) have no standard lower rate to upsample from,
-		// so the check is not applicable and we report 100% confidence in "genuine".
+		// Base sample rates (44100, 48000) are only checked against low source rates (8-22.05 kHz), whose walls
+		// are unmistakable: we report 100% confidence in "genuine".
 		confidence := boolToConfidence(result.Spectral.UpsampleSharpness > opts.UpsampleSharpnessDb)
 		if !detected && result.Spectral.ClaimedRate <= 48000 {
 			confidence = 1.0
@@ -743,6 +1622,17 @@ func interpretResults(result *Result, opts Options) {
 				result.Spectral.LikelyCodec,
 				result.Spectral.TranscodeCutoff,
 			)
+			if result.Spectral.VbrLossySignature {
+				summary += "; cutoff follows loudness (VBR encoder)"
+			}
+
+			if result.Spectral.HasBirdies {
+				summary += "; unstable high-frequency tones (codec birdies)"
+			}
+
+			if result.Spectral.HfRegenerationLikely {
+				summary += "; regenerated high band (noise fill or band replication)"
+			}
 			// Use the V2 confidence if available, otherwise fall back to sharpness-based.
 			if result.Spectral.TranscodeConfidence > 0 {
 				confidence = result.Spectral.TranscodeConfidence
@@ -753,6 +1643,32 @@ func interpretResults(result *Result, opts Options) {
 			severity = SeverityNone
 			summary = "No lossy transcode detected"
 			confidence = 1.0 // high confidence it's NOT a transcode
+
+			// Describe the high end anyway: a bandwidth-limited source is not a transcode, but it is not full-range.
+			switch result.Spectral.HfRolloffCharacter {
+			case spectral.HfGentle:
+				summary += fmt.Sprintf(
+					" (natural gentle rolloff from ~%.1f kHz: bandwidth-limited source)",
+					result.Spectral.HfRolloffHz/1000,
+				)
+			case spectral.HfBrickWall:
+				summary += fmt.Sprintf(
+					" (brick wall at ~%.1f kHz: lowpass or anti-alias filter)",
+					result.Spectral.HfRolloffHz/1000,
+				)
+			default:
+			}
+
+			if result.Spectral.HasBirdies {
+				summary += fmt.Sprintf("; unstable high-frequency tones like codec birdies in %.0f%% of the windows",
+					result.Spectral.BirdieWindowShare*100)
+			}
+
+			if result.Spectral.HfRegenerationLikely {
+				summary += fmt.Sprintf("; high band looks regenerated: flat noise unrelated to the music under it "+
+					"(level correlation %.2f), as noise fill or band replication over a lossy cutoff leave",
+					result.Spectral.HfEnvelopeCorrelation)
+			}
 		}
 
 		result.HasLossyTranscode = detected
@@ -795,19 +1711,50 @@ func interpretResults(result *Result, opts Options) {
 
 	// Stereo checks
 	if result.Stereo != nil {
+		// Mono duplicated into both channels, with one of them polarity-flipped. Either way the file carries no
+		// independent content, the L-R (resp. L+R) residue being the giveaway.
+		duplicated := result.Stereo.Correlation > 0.98 && result.Stereo.DifferenceDb < -60
+		invertedDuplicate := isInvertedDuplicate(result.Stereo)
+
 		// Fake Stereo (binary detection, no bands)
 		if opts.Checks&CheckFakeStereo != 0 {
-			detected := result.Stereo.Correlation > 0.98 && result.Stereo.DifferenceDb < -60
+			detected := duplicated || invertedDuplicate || result.Stereo.SyntheticWidthLikely ||
+				result.Stereo.DecorrelatedLikely
+			confidence := 1.0
 
 			var (
 				severity Severity
 				summary  string
 			)
 
-			if detected {
+			switch {
+			case duplicated:
 				severity = SeverityModerate
 				summary = fmt.Sprintf("Fake stereo: channels identical (correlation %.3f)", result.Stereo.Correlation)
-			} else {
+			case invertedDuplicate:
+				severity = SeverityModerate
+				summary = fmt.Sprintf(
+					"Pseudo-stereo from mono: one channel is the other inverted (correlation %.3f)",
+					result.Stereo.Correlation,
+				)
+			case result.Stereo.SyntheticWidthLikely:
+				// Spaced microphones and discrete reflections comb-filter too, if less regularly.
+				severity = SeverityMild
+				confidence = 0.7
+				summary = fmt.Sprintf(
+					"Synthetic stereo width: comb pattern every %.0f Hz across the stereo field (%.1f ms delay)",
+					result.Stereo.CombPeriodHz, 1000/result.Stereo.CombPeriodHz,
+				)
+			case result.Stereo.DecorrelatedLikely:
+				// Uncorrelated test noise, and some very diffuse ambient recordings, look the same.
+				severity = SeverityMild
+				confidence = 0.6
+				summary = fmt.Sprintf(
+					"Artificially decorrelated stereo: correlation %.3f, with matching L/R spectra (%.2f dB spread "+
+						"across octaves): a widener on a mono source",
+					result.Stereo.Correlation, result.Stereo.SpectralMismatchDb,
+				)
+			default:
 				severity = SeverityNone
 				summary = "Real stereo content"
 			}
@@ -818,7 +1765,7 @@ func interpretResults(result *Result, opts Options) {
 				Detected:   detected,
 				Severity:   severity,
 				Summary:    summary,
-				Confidence: 1.0,
+				Confidence: confidence,
 			})
 		}
 
@@ -840,6 +1787,11 @@ func interpretResults(result *Result, opts Options) {
 			default:
 			}
 
+			// What the listener hears of it: the loudness lost in mono, K-weighted and gated.
+			if detected && result.Loudness != nil && result.Loudness.MonoLoudnessPenaltyLU > 0 {
+				summary += fmt.Sprintf("; %.1f LU quieter in mono", result.Loudness.MonoLoudnessPenaltyLU)
+			}
+
 			result.HasPhaseIssues = detected
 			result.Issues = append(result.Issues, Issue{
 				Check:      CheckPhaseIssues,
@@ -859,13 +1811,20 @@ func interpretResults(result *Result, opts Options) {
 				summary  string
 			)
 
-			if detected {
+			switch {
+			case invertedDuplicate:
+				severity = SeveritySevere
+				summary = fmt.Sprintf(
+					"Inverted phase: mono duplicated with one channel polarity flipped (correlation %.3f)",
+					result.Stereo.Correlation,
+				)
+			case detected:
 				severity = SeveritySevere
 				summary = fmt.Sprintf(
 					"Inverted phase: one channel polarity flipped (correlation %.3f)",
 					result.Stereo.Correlation,
 				)
-			} else {
+			default:
 				severity = SeverityNone
 				summary = "Phase polarity OK"
 			}
@@ -904,6 +1863,23 @@ func interpretResults(result *Result, opts Options) {
 			default:
 			}
 
+			// Steady or erratic: a gain error to correct, or a physical fault to re-transfer.
+			spread := result.Stereo.ImbalanceVarianceDb
+
+			switch {
+			case !detected || spread == 0:
+			case spread < imbalanceSteadyDb:
+				summary += fmt.Sprintf(
+					"; steady across the track (±%.1f dB): a gain offset, fixable with a balance correction", spread,
+				)
+			case spread > imbalanceErraticDb:
+				summary += fmt.Sprintf(
+					"; erratic (±%.1f dB across the track): intermittent cable or connector fault, re-transfer",
+					spread,
+				)
+			default:
+			}
+
 			result.HasChannelImbalance = detected
 			result.Issues = append(result.Issues, Issue{
 				Check:      CheckChannelImbalance,
@@ -931,9 +1907,11 @@ func interpretResults(result *Result, opts Options) {
 			summary = "No excessive silence padding"
 		default:
 			summary = fmt.Sprintf(
-				"Silence padding: %.1fs leading, %.1fs trailing",
+				"Silence padding: %.1fs leading%s, %.1fs trailing%s",
 				result.Silence.LeadingSec,
+				silenceContent(result.Silence, true),
 				result.Silence.TrailingSec,
+				silenceContent(result.Silence, false),
 			)
 		}
 
@@ -982,6 +1960,16 @@ func interpretResults(result *Result, opts Options) {
 			summary = "No mains hum detected"
 		}
 
+		// Steady tones elsewhere are reported, not graded: a held drone or a pilot tone can be one on purpose.
+		if tones := result.Spectral.ConstantTones; len(tones) > 0 {
+			described := make([]string, 0, len(tones))
+			for _, tone := range tones {
+				described = append(described, fmt.Sprintf("%.0f Hz (%+.1f dB)", tone.Hz, tone.Db))
+			}
+
+			summary += "; constant tones at " + strings.Join(described, ", ")
+		}
+
 		result.HasHum = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckHum,
@@ -1010,6 +1998,13 @@ func interpretResults(result *Result, opts Options) {
 		default:
 		}
 
+		if result.Spectral.LeadInNoiseSec > 0 {
+			summary += fmt.Sprintf(
+				"; %.1f dB in the silent edges (%.1fs)",
+				result.Spectral.LeadInNoiseFloorDb, result.Spectral.LeadInNoiseSec,
+			)
+		}
+
 		result.HasHighNoiseFloor = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckNoiseFloor,
@@ -1020,6 +2015,164 @@ func interpretResults(result *Result, opts Options) {
 		})
 	}
 
+	// De-essing (binary detection, no bands; informational mix QA)
+	if result.Spectral != nil && opts.Checks&CheckDeEssing != 0 {
+		enough := result.Spectral.SibilantEvents >= deEssMinEvents
+		detected := enough && result.Spectral.DeEssPumpingIndex >= deEssPumpingThreshold
+
+		var (
+			severity   Severity
+			summary    string
+			confidence float64
+		)
+
+		switch {
+		case detected:
+			severity = SeverityMild
+			summary = fmt.Sprintf(
+				"Aggressive de-essing: 5-9 kHz ducks on %.0f%% of sibilants",
+				result.Spectral.DeEssPumpingIndex*100,
+			)
+			confidence = 0.6
+		case !enough:
+			severity = SeverityNone
+			summary = "Not enough sibilant content to assess de-essing"
+			confidence = 0.5
+		default:
+			severity = SeverityNone
+			summary = "No de-esser pumping"
+			confidence = 0.8
+		}
+
+		result.HasDeEssPumping = detected
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckDeEssing,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: confidence,
+		})
+	}
+
+	// Bass rolloff (binary detection from spectral flags, bands on the cutoff frequency for severity)
+	if result.Spectral != nil && opts.Checks&CheckBassRolloff != 0 {
+		detected := result.Spectral.HasBassRolloff
+
+		var (
+			severity Severity
+			summary  string
+		)
+
+		if detected {
+			severity, _ = opts.BassRolloff.Match(result.Spectral.BassCutoffHz)
+			if severity == SeverityNone {
+				// Detected but below band thresholds: default to mild.
+				severity = SeverityMild
+			}
+
+			summary = fmt.Sprintf(
+				"Low end cut at %.0f Hz (%.0f dB/octave): sub-bass filtered out",
+				result.Spectral.BassCutoffHz,
+				result.Spectral.BassRolloffDbPerOct,
+			)
+		} else {
+			severity = SeverityNone
+			summary = "No low-end brick wall"
+		}
+
+		result.HasBassRolloff = detected
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckBassRolloff,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: 0.7,
+		})
+	}
+
+	// Spectral tilt (bands on the distance from the typical tilt)
+	if result.Spectral != nil && opts.Checks&CheckSpectralTilt != 0 {
+		result.Issues = append(result.Issues, interpretSpectralTilt(result, opts))
+	}
+
+	// Vinyl cut safety (binary verdict from the stereo low-band measurements)
+	if result.Stereo != nil && opts.Checks&CheckVinylCutSafety != 0 {
+		detected := result.Stereo.VinylCutUnsafe
+
+		var (
+			severity Severity
+			summary  string
+		)
+
+		if detected {
+			severity = SeverityModerate
+
+			var problems []string
+
+			for _, band := range result.Stereo.LowBands {
+				if !band.Unsafe {
+					continue
+				}
+
+				if band.Correlation < 0 {
+					// Anti-phase bass is vertical groove modulation: the stylus leaves the groove.
+					severity = SeveritySevere
+
+					problems = append(problems, fmt.Sprintf("%.0f-%.0f Hz out of phase (correlation %.2f)",
+						band.LowHz, band.HighHz, band.Correlation))
+				} else {
+					problems = append(problems, fmt.Sprintf("%.0f-%.0f Hz too wide (correlation %.2f)",
+						band.LowHz, band.HighHz, band.Correlation))
+				}
+			}
+
+			if result.Stereo.SubsonicExcess {
+				problems = append(problems, fmt.Sprintf("subsonic content at %.1f dB", result.Stereo.SubsonicDb))
+			}
+
+			summary = "Not safe for vinyl cutting: " + strings.Join(problems, ", ")
+		} else {
+			severity = SeverityNone
+			summary = "Safe for vinyl cutting: bass is mono-compatible"
+		}
+
+		result.HasVinylCutRisk = detected
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckVinylCutSafety,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: 0.7,
+		})
+	}
+
+	// Width changes (informational: where the track switches between mono and stereo)
+	if result.Stereo != nil && opts.Checks&CheckWidthChanges != 0 {
+		summary := "No mono/stereo transitions"
+
+		if transitions := result.Stereo.WidthTransitions; len(transitions) > 0 {
+			changes := make([]string, 0, len(transitions))
+			for _, transition := range transitions {
+				to := "stereo"
+				if transition.ToMono {
+					to = "mono"
+				}
+
+				changes = append(changes, fmt.Sprintf("to %s at %.1fs", to, transition.TimeSec))
+			}
+
+			summary = "Stereo width changes: " + strings.Join(changes, ", ") +
+				" (sections from different sources, or an inconsistent master)"
+		}
+
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckWidthChanges,
+			Severity:   SeverityNone,
+			Summary:    summary,
+			Confidence: 0.7,
+		})
+	}
+
 	// Inter-Sample Peaks
 	if result.TruePeak != nil && opts.Checks&CheckInterSamplePeaks != 0 {
 		ispCount := float64(result.TruePeak.ISPCount)
@@ -1041,28 +2194,93 @@ func interpretResults(result *Result, opts Options) {
 		default:
 		}
 
+		// ISPs driven by one frequency region point at an EQ boost, which can be tamed where it is.
+		if detected && result.TruePeak.ISPRegionShare >= ispRegionConcentrated {
+			summary += fmt.Sprintf(
+				" (concentrated around %.1f kHz, in the %s: look for an EQ resonance)",
+				result.TruePeak.ISPRegionHz/1000,
+				types.BandOf(result.TruePeak.ISPRegionHz),
+			)
+		}
+
+		// Overshoots in most of the track, quiet sections included, mean it was pushed into a limiter throughout,
+		// whatever its absolute level.
+		if result.TruePeak.OversPervasiveFraction >= oversPervasiveNotable {
+			summary += fmt.Sprintf(
+				" (true peak over sample peak in %.0f%% of the track: limited throughout)",
+				result.TruePeak.OversPervasiveFraction*100,
+			)
+		}
+
+		confidence := 1.0
+
+		// A lossy decoder reconstructs a band-limited waveform that routinely overshoots 0 dBTP,
+		// on top of anything the master did. In a transcode, ISPs are not authoritative evidence of a hot master:
+		// report them, but step severity down so the file is not penalized twice for the same lossy origin.
+		if detected && result.TruePeak.TruePeakDb > 0 && result.Spectral != nil && result.Spectral.IsTranscode {
+			if severity > SeverityMild {
+				severity--
+			}
+
+			summary += " (likely decoder-introduced: lossy source)"
+			confidence = 0.5
+		}
+
 		result.HasInterSamplePeaks = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckInterSamplePeaks,
 			Detected:   detected,
 			Severity:   severity,
 			Summary:    summary,
-			Confidence: 1.0,
+			Confidence: confidence,
 		})
 	}
 
-	// Loudness (informational, no bands)
+	// Loudness (informational, unless graded against a delivery target)
 	if result.Loudness != nil && opts.Checks&CheckLoudness != 0 {
+		summary := fmt.Sprintf(
+			"Loudness: %.1f LUFS, range %.1f LU",
+			result.Loudness.IntegratedLUFS,
+			result.Loudness.LoudnessRange,
+		)
+
+		// How quiet it gets: the plain-level counterpart of the spectral noise floor.
+		if result.Loudness.QuietestPassageDbFS > -120 {
+			summary += fmt.Sprintf(", quietest passage %.1f dBFS (at %.1fs)", result.Loudness.QuietestPassageDbFS,
+				result.Loudness.QuietestPassageSec)
+		}
+
+		// Provenance: where a previous hard limiter was set.
+		if result.Loudness.LimiterCeilingDb != 0 {
+			summary += fmt.Sprintf(", limiter ceiling at %.2f dBFS", result.Loudness.LimiterCeilingDb)
+		}
+
+		// Surround: the LFE position holds a full-range channel, which the measurement left out.
+		if result.Loudness.LFEFullRange {
+			summary += fmt.Sprintf("; the LFE channel is full-range (%.0f%% of its energy above 200 Hz): "+
+				"the channel layout is likely wrong, and the loudness with it", 100*result.Loudness.LFEHighBandShare)
+		}
+
+		confidence := 1.0
+		severity, detected := SeverityNone, false
+
+		if opts.LoudnessTargetLUFS != 0 {
+			severity, detected, summary = loudnessCompliance(result, opts, summary)
+		}
+
+		// Mostly-silent or very sparse material: the integrated figure only reflects a small part of the track.
+		if result.Loudness.GatedCoverage < loudnessLowCoverage {
+			summary += fmt.Sprintf(" (based on %.0f%% of the track)", result.Loudness.GatedCoverage*100)
+			confidence = 0.5
+		}
+
+		result.IsOverLoudnessTarget = detected
 		result.Issues = append(result.Issues, Issue{
-			Check:    CheckLoudness,
-			Detected: false, // informational
-			Severity: SeverityNone,
-			Summary: fmt.Sprintf(
-				"Loudness: %.1f LUFS, range %.1f LU",
-				result.Loudness.IntegratedLUFS,
-				result.Loudness.LoudnessRange,
-			),
-			Confidence: 1.0,
+			Check:      CheckLoudness,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: confidence,
 		})
 	}
 
@@ -1089,6 +2307,26 @@ func interpretResults(result *Result, opts Options) {
 		default:
 		}
 
+		if opts.Genre != GenreUnspecified {
+			summary += " for " + opts.Genre.String()
+		}
+
+		// Corroborate with the transient shapes: a uniformly limited master flattens most of its attacks.
+		if detected && result.Loudness.TransientFlatteningIndex >= 0.5 {
+			summary += fmt.Sprintf(
+				"; %.0f%% of transients flattened by limiting",
+				result.Loudness.TransientFlatteningIndex*100,
+			)
+		}
+
+		// Transients cut flat at the sample level, audible as crackle on every hit, whatever the crest factor.
+		if result.Loudness.TransientsClippedFraction >= transientsClippedNotable {
+			summary += fmt.Sprintf(
+				"; %.0f%% of transients clipped flat",
+				result.Loudness.TransientsClippedFraction*100,
+			)
+		}
+
 		result.IsBrickwalled = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckDynamicRange,
@@ -1101,7 +2339,10 @@ func interpretResults(result *Result, opts Options) {
 
 	// Dropouts
 	if result.Dropout != nil && opts.Checks&CheckDropouts != 0 {
-		total := float64(result.Dropout.DeltaCount + result.Dropout.ZeroRunCount + result.Dropout.DCJumpCount)
+		total := float64(
+			result.Dropout.DeltaCount + result.Dropout.ZeroRunCount + result.Dropout.DCJumpCount +
+				result.Dropout.ImpulseCount + result.Dropout.StuckCount,
+		)
 		severity, detected := opts.Dropouts.Match(total)
 
 		var summary string
@@ -1111,40 +2352,60 @@ func interpretResults(result *Result, opts Options) {
 			summary = "No dropouts or glitches"
 		case SeverityMild:
 			summary = fmt.Sprintf(
-				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts; worst: %.1f dB)",
+				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts, %d impulses, %d stuck runs; worst: %.1f dB)",
 				int(
 					total,
 				),
 				result.Dropout.DeltaCount,
 				result.Dropout.ZeroRunCount,
 				result.Dropout.DCJumpCount,
+				result.Dropout.ImpulseCount,
+				result.Dropout.StuckCount,
 				result.Dropout.WorstDb,
 			)
 		case SeverityModerate:
 			summary = fmt.Sprintf(
-				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts; worst: %.1f dB)",
+				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts, %d impulses, %d stuck runs; worst: %.1f dB)",
 				int(
 					total,
 				),
 				result.Dropout.DeltaCount,
 				result.Dropout.ZeroRunCount,
 				result.Dropout.DCJumpCount,
+				result.Dropout.ImpulseCount,
+				result.Dropout.StuckCount,
 				result.Dropout.WorstDb,
 			)
 		case SeveritySevere:
 			summary = fmt.Sprintf(
-				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts; worst: %.1f dB)",
+				"%d discontinuities (%d jumps, %d zero runs, %d DC shifts, %d impulses, %d stuck runs; worst: %.1f dB)",
 				int(
 					total,
 				),
 				result.Dropout.DeltaCount,
 				result.Dropout.ZeroRunCount,
 				result.Dropout.DCJumpCount,
+				result.Dropout.ImpulseCount,
+				result.Dropout.StuckCount,
 				result.Dropout.WorstDb,
 			)
 		default:
 		}
 
+		if detected && result.Dropout.PeriodicSamples > 0 {
+			summary += fmt.Sprintf(
+				"; recurring every %d samples (systematic encoding artifact)",
+				result.Dropout.PeriodicSamples,
+			)
+		}
+
+		if detected && result.Dropout.BufferBoundarySamples > 0 {
+			summary += fmt.Sprintf(
+				"; buffer-boundary artifact on %d-sample boundaries (software buffer bug, not media damage)",
+				result.Dropout.BufferBoundarySamples,
+			)
+		}
+
 		result.HasDropouts = detected
 		result.Issues = append(result.Issues, Issue{
 			Check:      CheckDropouts,
@@ -1155,6 +2416,45 @@ func interpretResults(result *Result, opts Options) {
 		})
 	}
 
+	// Pitch offset (analog sources only: elsewhere, tuning is a choice)
+	if result.Spectral != nil && opts.Checks&CheckPitchOffset != 0 {
+		interpretPitchOffset(result, opts)
+	}
+
+	// Repeats
+	if result.Repeat != nil && opts.Checks&CheckRepeats != 0 {
+		severity, detected := opts.Repeats.Match(float64(result.Repeat.Count))
+
+		summary := "No repeated blocks"
+		if detected {
+			first := result.Repeat.Events[0]
+			summary = fmt.Sprintf(
+				"%d repeated blocks (drive re-read stutter; longest %.1f ms, first at %.2fs)",
+				result.Repeat.Count, result.Repeat.LongestMs, first.TimeSec,
+			)
+		}
+
+		result.HasRepeats = detected
+		result.Issues = append(result.Issues, Issue{
+			Check:      CheckRepeats,
+			Detected:   detected,
+			Severity:   severity,
+			Summary:    summary,
+			Confidence: 0.8,
+		})
+	}
+
+	explainDCClipping(result)
+	explainPseudoStereo(result)
+
+	if IsLossyCodec(opts.SourceCodec) {
+		markLossyNotApplicable(result, opts.SourceCodec)
+	}
+
+	if opts.MinConfidence > 0 {
+		suppressLowConfidence(result, opts.MinConfidence)
+	}
+
 	// Calculate summary stats
 	for _, issue := range result.Issues {
 		if issue.Detected {
@@ -1167,6 +2467,543 @@ func interpretResults(result *Result, opts Options) {
 	}
 }
 
+//nolint:gochecknoglobals
+var lossyCodecs = map[string]bool{
+	"mp2": true, "mp3": true, "aac": true, "opus": true, "vorbis": true, "wmav1": true, "wmav2": true,
+	"wmapro": true, "ac3": true, "eac3": true, "atrac3": true, "atrac3p": true, "amr_nb": true, "amr_wb": true,
+	"musepack7": true, "musepack8": true,
+}
+
+// IsLossyCodec reports whether an ffprobe codec name is a lossy codec.
+func IsLossyCodec(codec string) bool {
+	return lossyCodecs[codec]
+}
+
+const (
+	trimmedFirstFrameDb = -40.0 // first frame at least this loud: the track does not start from silence
+	trimmedAbruptDb     = -20.0 // louder still: a step, heard as a click
+	trimmedLeadInDb     = 20.0  // lead-in RMS within this much of the head peak: no onset building up
+	trimmedMaxSilence   = 0.001 // seconds of leading silence under which the track starts right away
+)
+
+// interpretTrimmedLeadIn flags a track trimmed into its first note: no leading silence, the very first frame
+// already loud, and the first 5 ms already close to the level of the head window, with no onset rising to it.
+func interpretTrimmedLeadIn(result *Result) {
+	head := result.Truncation
+	detected := result.Silence.LeadingSec < trimmedMaxSilence &&
+		head.FirstFrameDb >= trimmedFirstFrameDb &&
+		head.LeadInRmsDb >= head.HeadPeakDb-trimmedLeadInDb
+
+	severity := SeverityNone
+	summary := "Natural start"
+
+	if detected {
+		severity = SeverityMild
+		if head.FirstFrameDb >= trimmedAbruptDb {
+			severity = SeverityModerate
+		}
+
+		summary = fmt.Sprintf(
+			"Lead-in trimmed into the first note: starts at %.1f dBFS with no onset "+
+				"(first 5 ms at %.1f dB, head peak %.1f dB)",
+			head.FirstFrameDb,
+			head.LeadInRmsDb,
+			head.HeadPeakDb,
+		)
+	}
+
+	result.HasTrimmedLeadIn = detected
+	result.Issues = append(result.Issues, Issue{
+		Check:      CheckTrimmedLeadIn,
+		Detected:   detected,
+		Severity:   severity,
+		Summary:    summary,
+		Confidence: 0.6,
+	})
+}
+
+// markLossyNotApplicable replaces the fake-bit-depth and fake-sample-rate verdicts of a lossy source:
+// a "genuine" there would be vacuous.
+func markLossyNotApplicable(result *Result, codec string) {
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		if issue.Check != CheckFakeBitDepth && issue.Check != CheckFakeSampleRate {
+			continue
+		}
+
+		*issue = Issue{
+			Check:         issue.Check,
+			Severity:      SeverityNone,
+			Summary:       fmt.Sprintf("Not applicable: lossy source (%s)", codec),
+			NotApplicable: true,
+		}
+	}
+
+	result.HasFakeBitDepth = false
+	result.HasFakeSampleRate = false
+}
+
+// corroborateClipping weighs detected flat tops against the spectrum. On tone-dominated material, real clipping
+// comes with odd harmonics; flat tops without them are a clean full-scale tone (a test signal). Complex material,
+// or no spectral analysis, leaves the detection uncorroborated.
+func corroborateClipping(spectral *types.SpectralResult) (confidence float64, note string) {
+	switch {
+	case spectral == nil || !spectral.ToneDominated:
+		return 0.9, ""
+	case spectral.OddHarmonicsDb >= clippingHarmonicsDb:
+		return 1.0, fmt.Sprintf("; odd harmonics at %.0f dB confirm distortion", spectral.OddHarmonicsDb)
+	default:
+		return 0.4, fmt.Sprintf(
+			"; no distortion products on the %.0f Hz tone: likely a clean full-scale test signal",
+			spectral.DominantToneHz,
+		)
+	}
+}
+
+// asymmetricClipping notes the channels whose full-scale clipping hits a single polarity (90% of the events or
+// more, out of 10 or more). Gain alone clips both sides of the waveform; one side only means the waveform was
+// shifted first (a DC offset, a faulty channel), and the remedy is removing the offset, not the gain: see
+// explainDCClipping.
+func asymmetricClipping(clipping *types.ClippingDetection) string {
+	var parts []string
+
+	for channel, counts := range clipping.Channels {
+		sign := clippedPolarity(counts)
+		if sign == 0 {
+			continue
+		}
+
+		polarity := "positive"
+		if sign < 0 {
+			polarity = "negative"
+		}
+
+		if len(clipping.Channels) > 1 {
+			polarity = channelLabel(channel, len(clipping.Channels)) + " " + polarity
+		}
+
+		parts = append(parts, polarity)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("; one-sided (%s peaks only)", strings.Join(parts, ", "))
+}
+
+// clippedPolarity returns the polarity a channel's full-scale clipping hits alone (1 positive, -1 negative), or 0
+// when it hits both, or too rarely to tell.
+func clippedPolarity(counts types.ChannelClipping) int {
+	if counts.Events < clippingAsymmetryMinEvents {
+		return 0
+	}
+
+	switch {
+	case float64(counts.PositiveEvents) >= clippingAsymmetryShare*float64(counts.Events):
+		return 1
+	case float64(counts.NegativeEvents) >= clippingAsymmetryShare*float64(counts.Events):
+		return -1
+	default:
+		return 0
+	}
+}
+
+// explainDCClipping links one-sided clipping to the DC that causes it, once every verdict is in: a detected DC
+// offset of the clipped polarity on the clipped channel (a positive offset lifts the waveform into the positive
+// rail), or else DC jumps, which shift whole sections. The issues involved then name the root cause rather than
+// separate symptoms: the offset is to be fixed first, and the clipping may go with it. Without either, the
+// clipping issue only suggests checking for an offset.
+func explainDCClipping(result *Result) {
+	if result.Clipping == nil || !result.HasClipping {
+		return
+	}
+
+	var oneSided, offsetCaused bool
+
+	for channel, counts := range result.Clipping.Channels {
+		sign := clippedPolarity(counts)
+		if sign == 0 {
+			continue
+		}
+
+		oneSided = true
+
+		if result.HasDCOffset && result.DCOffset != nil && channel < len(result.DCOffset.Channels) &&
+			result.DCOffset.Channels[channel]*float64(sign) > 0 {
+			offsetCaused = true
+		}
+	}
+
+	jumps := 0
+	if result.Dropout != nil && result.HasDropouts {
+		jumps = result.Dropout.DCJumpCount
+	}
+
+	if !oneSided {
+		return
+	}
+
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		if !issue.Detected {
+			continue
+		}
+
+		switch {
+		case issue.Check == CheckClipping && offsetCaused:
+			issue.Summary += "; root cause: DC offset causing asymmetric clipping, fix the offset first"
+		case issue.Check == CheckClipping && jumps > 0:
+			issue.Summary += fmt.Sprintf(
+				"; root cause: %d DC jumps shifting sections into asymmetric clipping, fix the DC first", jumps,
+			)
+		case issue.Check == CheckClipping:
+			issue.Summary += "; check for a DC offset before reducing gain"
+		case issue.Check == CheckDCOffset && offsetCaused:
+			issue.Summary += "; it pushes the waveform into one-sided clipping: fix the offset first"
+		case issue.Check == CheckDropouts && !offsetCaused && jumps > 0:
+			issue.Summary += "; the DC jumps likely cause the one-sided clipping"
+		default:
+		}
+	}
+}
+
+// isInvertedDuplicate tells whether one channel is the other with its polarity flipped: mono, summing to silence.
+func isInvertedDuplicate(stereo *types.StereoResult) bool {
+	return stereo.Correlation < -0.98 && stereo.MonoSumDb < -60
+}
+
+// explainPseudoStereo folds the phase verdicts of polarity-flipped dual-mono into its fake-stereo verdict, once
+// every verdict is in: the flipped polarity and the cancellation in mono are how the pseudo-stereo was made, not
+// separate defects. The fake-stereo issue is the one verdict, at the worst of their severities; they are reported
+// as explained by it.
+func explainPseudoStereo(result *Result) {
+	if result.Stereo == nil || !result.HasFakeStereo || !isInvertedDuplicate(result.Stereo) {
+		return
+	}
+
+	var pseudo *Issue
+
+	explained := SeverityNone
+
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+
+		switch {
+		case issue.Check == CheckFakeStereo:
+			pseudo = issue
+		case issue.Detected && (issue.Check == CheckInvertedPhase || issue.Check == CheckPhaseIssues):
+			explained = max(explained, issue.Severity)
+			*issue = Issue{
+				Check:      issue.Check,
+				Severity:   SeverityNone,
+				Summary:    "Explained by pseudo-stereo from mono (see fake-stereo)",
+				Confidence: issue.Confidence,
+			}
+		default:
+		}
+	}
+
+	if pseudo == nil || explained == SeverityNone {
+		return
+	}
+
+	pseudo.Severity = max(pseudo.Severity, explained)
+	pseudo.Summary += "; the flipped channel cancels it to silence in mono"
+	result.HasInvertedPhase = false
+	result.HasPhaseIssues = false
+}
+
+// silenceContent tells whether the leading (or trailing) silence is digital black or low-level noise, such as a
+// transfer chain's, or nothing when there is no such silence.
+func silenceContent(silence *types.SilenceResult, leading bool) string {
+	if len(silence.Segments) == 0 {
+		return ""
+	}
+
+	segment := silence.Segments[0]
+	edge := segment.StartSample == 0
+
+	if !leading {
+		segment = silence.Segments[len(silence.Segments)-1]
+		edge = segment.EndSample == silence.Frames
+	}
+
+	switch {
+	case !edge:
+		return ""
+	case segment.IsDigitalZero:
+		return " (digital zero)"
+	default:
+		return fmt.Sprintf(" (noise at %.1f dB)", segment.RmsDb)
+	}
+}
+
+// clippingBand names the band the clipping concentrates in: what to tame, the kick and bass or the cymbals.
+func clippingBand(clipping *types.ClippingDetection) string {
+	switch clipping.Band {
+	case types.BandBass, types.BandMids, types.BandUpperMids, types.BandTreble:
+		return fmt.Sprintf("; concentrated in the %s (%.0f%% of the energy around the clips)",
+			clipping.Band, clipping.BandShare*100)
+	case types.BandBroadband:
+		return "; broadband"
+	case types.BandNone:
+	default:
+	}
+
+	return ""
+}
+
+// clippingRecoverability describes how far declipping can repair the full-scale clipping.
+func clippingRecoverability(clipping *types.ClippingDetection) string {
+	switch clipping.Recoverability {
+	case types.Restorable:
+		return "; short, isolated runs: restorable by declipping"
+	case types.PartiallyRestorable:
+		return "; partially restorable by declipping"
+	case types.Unrecoverable:
+		return fmt.Sprintf("; unrecoverable (%.2f%% of samples clipped, %.0f%% of them in runs over 1 ms)",
+			100*float64(clipping.ClippedSamples)/float64(clipping.Samples),
+			100*float64(clipping.LongRunSamples)/float64(clipping.ClippedSamples))
+	case types.RecoverabilityNone:
+	default:
+	}
+
+	return ""
+}
+
+// localizeChannels fills the affected channels of the detected issues that have per-channel data.
+func localizeChannels(result *Result, channels uint) {
+	if channels < 2 {
+		return
+	}
+
+	numChannels := int(channels) //nolint:gosec // channel counts are small
+
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		if !issue.Detected {
+			continue
+		}
+
+		var parts []string
+
+		switch issue.Check {
+		case CheckClipping:
+			if result.Clipping.Events == 0 {
+				// Plateau-based detection: no per-channel counts.
+				continue
+			}
+
+			for channel, clipping := range result.Clipping.Channels {
+				parts = append(parts, fmt.Sprintf("%s: %d", channelLabel(channel, numChannels), clipping.Events))
+			}
+		case CheckDCOffset:
+			for channel, offset := range result.DCOffset.Channels {
+				offsetDb := max(20*math.Log10(math.Abs(offset)), -120)
+				parts = append(parts, fmt.Sprintf("%s: %.1f dB", channelLabel(channel, numChannels), offsetDb))
+			}
+		case CheckDropouts:
+			counts := make([]int, numChannels)
+
+			for _, event := range result.Dropout.Events {
+				if event.Channel >= 0 && event.Channel < numChannels {
+					counts[event.Channel]++
+				}
+			}
+
+			for channel, count := range counts {
+				parts = append(parts, fmt.Sprintf("%s: %d", channelLabel(channel, numChannels), count))
+			}
+		default:
+		}
+
+		issue.AffectedChannels = strings.Join(parts, ", ")
+	}
+}
+
+// channelLabel names a channel: L and R for stereo, its index otherwise.
+func channelLabel(channel, numChannels int) string {
+	if numChannels == 2 {
+		return [2]string{"L", "R"}[channel]
+	}
+
+	return fmt.Sprintf("ch%d", channel)
+}
+
+// interpretPitchOffset reports the tuning offset of the tonal content. A consistent offset on a transfer is a
+// speed error: playback (or the capture clock) ran 2^(cents/1200) times too fast.
+func interpretPitchOffset(result *Result, opts Options) {
+	spectral := result.Spectral
+	reference := opts.PitchReferenceHz
+	if reference <= 0 {
+		reference = 440
+	}
+
+	issue := Issue{
+		Check:      CheckPitchOffset,
+		Summary:    "No sustained tonal content to measure pitch against",
+		Confidence: spectral.PitchCoherence,
+	}
+
+	if spectral.HasPitchReference {
+		speed := (math.Pow(2, spectral.PitchOffsetCents/1200) - 1) * 100
+
+		if opts.Source == SourceDigital {
+			issue.Summary = fmt.Sprintf("Tuned %+.1f cents from A=%g Hz (not judged on digital sources)",
+				spectral.PitchOffsetCents, reference)
+		} else {
+			issue.Severity, issue.Detected = opts.PitchOffset.Match(math.Abs(spectral.PitchOffsetCents))
+
+			issue.Summary = fmt.Sprintf("Tuned %+.1f cents from A=%g Hz", spectral.PitchOffsetCents, reference)
+			if issue.Detected {
+				issue.Summary = fmt.Sprintf("Pitch offset %+.1f cents from A=%g Hz: likely a %+.2f%% speed error",
+					spectral.PitchOffsetCents, reference, speed)
+			}
+		}
+	}
+
+	result.HasPitchOffset = issue.Detected
+	result.Issues = append(result.Issues, issue)
+}
+
+// loudnessCompliance grades the loudness against the delivery target and true peak ceiling: the largest excess
+// is matched against the Loudness bands, and appended to the summary. The loudest short-term window is graded
+// too, against the target plus an allowance: on dynamic material (classical, film), long quiet passages keep the
+// integrated loudness in check, while the tutti are what a normalizing platform, or a short-term limit, turns
+// down hard.
+func loudnessCompliance(result *Result, opts Options, summary string) (Severity, bool, string) {
+	loudnessExcess := result.Loudness.IntegratedLUFS - opts.LoudnessTargetLUFS
+	shortTermExcess := result.Loudness.ShortTermMax - opts.LoudnessTargetLUFS - opts.ShortTermAllowanceLU
+
+	// The sample peak is a lower bound of the true peak, when the true peak was not measured.
+	peakDb := result.Loudness.PeakDb
+	if result.TruePeak != nil {
+		peakDb = result.TruePeak.TruePeakDb
+	}
+
+	peakExcess := peakDb - opts.TruePeakCeilingDb
+
+	severity, detected := opts.Loudness.Match(max(loudnessExcess, peakExcess, shortTermExcess))
+
+	var overs []string
+
+	if loudnessExcess >= opts.Loudness.Mild {
+		overs = append(overs, fmt.Sprintf("%.1f LU over the %.1f LUFS target", loudnessExcess, opts.LoudnessTargetLUFS))
+	}
+
+	if peakExcess >= opts.Loudness.Mild {
+		overs = append(overs, fmt.Sprintf(
+			"true peak %+.1f dBTP, %.1f dB over the %.1f dBTP ceiling", peakDb, peakExcess, opts.TruePeakCeilingDb,
+		))
+	}
+
+	if shortTermExcess >= opts.Loudness.Mild {
+		overs = append(overs, fmt.Sprintf(
+			"loudest passage %.1f LUFS short-term, %.1f LU over the %.1f LUFS allowed (target %+.1f LU): "+
+				"it will be turned down hard",
+			result.Loudness.ShortTermMax, shortTermExcess,
+			opts.LoudnessTargetLUFS+opts.ShortTermAllowanceLU, opts.ShortTermAllowanceLU,
+		))
+	}
+
+	if len(overs) > 0 {
+		summary += "; " + strings.Join(overs, ", ")
+	} else {
+		summary += fmt.Sprintf(
+			"; within the %.1f LUFS target and %.1f dBTP ceiling", opts.LoudnessTargetLUFS, opts.TruePeakCeilingDb,
+		)
+	}
+
+	return severity, detected, summary
+}
+
+// interpretSpectralTilt grades the tilt of the average spectrum by its distance from SpectralTiltTypicalDbPerOct,
+// on either side: brighter is a harsh, over-equalized top end, duller a muffled master.
+func interpretSpectralTilt(result *Result, opts Options) Issue {
+	tilt := result.Spectral.SpectralTiltDbPerOct
+	if result.Spectral.SpectralTiltBands == 0 {
+		return Issue{
+			Check:      CheckSpectralTilt,
+			Summary:    "Tonal tilt not measured: too few octave bands hold music",
+			Confidence: 0.6,
+		}
+	}
+
+	severity, detected := opts.SpectralTilt.Match(math.Abs(tilt - SpectralTiltTypicalDbPerOct))
+
+	var summary string
+
+	switch {
+	case !detected:
+		summary = fmt.Sprintf("Balanced tonal tilt (%+.1f dB/octave against pink noise)", tilt)
+	case tilt > SpectralTiltTypicalDbPerOct:
+		summary = fmt.Sprintf("Bright, harsh balance: %+.1f dB/octave against pink noise (typical %+.1f)",
+			tilt, SpectralTiltTypicalDbPerOct)
+	default:
+		summary = fmt.Sprintf("Dull, muffled balance: %+.1f dB/octave against pink noise (typical %+.1f)",
+			tilt, SpectralTiltTypicalDbPerOct)
+	}
+
+	result.HasSpectralTilt = detected
+
+	return Issue{
+		Check:      CheckSpectralTilt,
+		Detected:   detected,
+		Severity:   severity,
+		Summary:    summary,
+		Confidence: 0.6,
+	}
+}
+
+// suppressLowConfidence turns detections below the confidence floor into non-detections,
+// clearing the matching quick access boolean.
+func suppressLowConfidence(result *Result, floor float64) {
+	flags := map[Check]*bool{
+		CheckClipping:         &result.HasClipping,
+		CheckTruncation:       &result.HasTruncation,
+		CheckTrimmedLeadIn:    &result.HasTrimmedLeadIn,
+		CheckFakeBitDepth:     &result.HasFakeBitDepth,
+		CheckUndithered:       &result.HasUndithered,
+		CheckFakeSampleRate:   &result.HasFakeSampleRate,
+		CheckLossyTranscode:   &result.HasLossyTranscode,
+		CheckDCOffset:         &result.HasDCOffset,
+		CheckFakeStereo:       &result.HasFakeStereo,
+		CheckPhaseIssues:      &result.HasPhaseIssues,
+		CheckInvertedPhase:    &result.HasInvertedPhase,
+		CheckChannelImbalance: &result.HasChannelImbalance,
+		CheckSilencePadding:   &result.HasSilencePadding,
+		CheckHum:              &result.HasHum,
+		CheckNoiseFloor:       &result.HasHighNoiseFloor,
+		CheckDeEssing:         &result.HasDeEssPumping,
+		CheckBassRolloff:      &result.HasBassRolloff,
+		CheckSpectralTilt:     &result.HasSpectralTilt,
+		CheckVinylCutSafety:   &result.HasVinylCutRisk,
+		CheckInterSamplePeaks: &result.HasInterSamplePeaks,
+		CheckDynamicRange:     &result.IsBrickwalled,
+		CheckDropouts:         &result.HasDropouts,
+		CheckRepeats:          &result.HasRepeats,
+		CheckPitchOffset:      &result.HasPitchOffset,
+		CheckLoudness:         &result.IsOverLoudnessTarget,
+	}
+
+	for i := range result.Issues {
+		issue := &result.Issues[i]
+		if !issue.Detected || issue.Confidence >= floor {
+			continue
+		}
+
+		issue.Detected = false
+		issue.Severity = SeverityNone
+		issue.Summary = fmt.Sprintf("%s (below confidence floor: %.0f%% < %.0f%%)",
+			issue.Summary, issue.Confidence*100, floor*100)
+
+		if flag, ok := flags[issue.Check]; ok {
+			*flag = false
+		}
+	}
+}
+
 func boolToConfidence(b bool) float64 {
 	if b {
 		return 0.95
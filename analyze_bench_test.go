@@ -0,0 +1,36 @@
+package haustorium_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+func BenchmarkAnalyze(b *testing.B) {
+	opts := haustorium.DefaultOptions()
+	opts.Checks = haustorium.ChecksAll
+
+	for _, signal := range []testutil.Signal{testutil.SignalSine, testutil.SignalNoise, testutil.SignalSilence} {
+		for _, format := range testutil.BenchFormats() {
+			data := testutil.Generate(signal, format, 2)
+			factory := func() (io.Reader, error) {
+				return bytes.NewReader(data), nil
+			}
+
+			b.Run(fmt.Sprintf("%s/%dHz/%dbit", signal, format.SampleRate, format.BitDepth), func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+
+				for b.Loop() {
+					if _, err := haustorium.Analyze(factory, format, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,153 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+// album is one directory worth of audio files, processed and written as a unit.
+type album struct {
+	dir   string
+	files []string
+}
+
+// groupByAlbum splits a sorted file list into albums, one per directory, in order.
+func groupByAlbum(files []string) []album {
+	var albums []album
+
+	for _, filePath := range files {
+		dir := filepath.Dir(filePath)
+		if len(albums) == 0 || albums[len(albums)-1].dir != dir {
+			albums = append(albums, album{dir: dir})
+		}
+
+		albums[len(albums)-1].files = append(albums[len(albums)-1].files, filePath)
+	}
+
+	return albums
+}
+
+// summarizeAlbum builds the aggregate record written after an album's tracks.
+func summarizeAlbum(dir string, records []report.Record) *report.AlbumRecord {
+	summary := &report.AlbumRecord{Dir: dir, Tracks: len(records)}
+
+	minLUFS, maxLUFS := math.Inf(1), math.Inf(-1)
+
+	for idx := range records {
+		record := &records[idx]
+		if record.Error != "" {
+			summary.Failed++
+
+			continue
+		}
+
+		if loudness, ok := record.Analysis["loudness"].(map[string]any); ok {
+			if lufs, ok := loudness["integrated_lufs"].(float64); ok && !math.IsInf(lufs, 0) {
+				minLUFS = min(minLUFS, lufs)
+				maxLUFS = max(maxLUFS, lufs)
+			}
+		}
+
+		var probe ffprobe.Result
+		if err := json.Unmarshal(record.Probe, &probe); err != nil {
+			continue
+		}
+
+		if stream, err := report.FindAudioStream(&probe, 0); err == nil {
+			format := fmt.Sprintf("%d/%s", report.ProbedBitDepth(stream), stream.SampleRate)
+			if !slices.Contains(summary.Formats, format) {
+				summary.Formats = append(summary.Formats, format)
+			}
+		}
+	}
+
+	if maxLUFS >= minLUFS {
+		summary.LoudnessSpreadLU = maxLUFS - minLUFS
+	}
+
+	summary.FormatConsistent = len(summary.Formats) <= 1
+
+	return summary
+}
+
+// resumeState holds what a previous report already covers: raw lines for its complete albums,
+// and the set of albums that can be skipped. An album is complete when every one of its current files has a
+// record without error, so interrupted albums and failed files are retried.
+type resumeState struct {
+	lines map[string][][]byte // album dir to its lines, in report order
+	done  map[string]bool
+}
+
+// loadResume reads a previous report. A missing report resumes nothing.
+func loadResume(path string, albums []album) (*resumeState, error) {
+	state := &resumeState{lines: map[string][][]byte{}, done: map[string]bool{}}
+
+	file, err := os.Open(path) //nolint:gosec // CLI tool reads its own previous report
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("opening previous report: %w", err)
+	}
+	defer file.Close()
+
+	succeeded := map[string]bool{}
+
+	scanner := bufio.NewScanner(file)
+
+	const maxLineSize = 1024 * 1024 // 1MB
+	scanner.Buffer(make([]byte, 0, maxLineSize), maxLineSize)
+
+	for scanner.Scan() {
+		var rec struct {
+			File  string              `json:"file"`
+			Error string              `json:"error"`
+			Album *report.AlbumRecord `json:"album"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+
+		dir := filepath.Dir(rec.File)
+		if rec.Album != nil {
+			dir = rec.Album.Dir
+		} else if rec.File == "" {
+			continue
+		}
+
+		state.lines[dir] = append(state.lines[dir], slices.Clone(scanner.Bytes()))
+
+		if rec.File != "" && rec.Error == "" {
+			succeeded[rec.File] = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading previous report: %w", err)
+	}
+
+	for _, alb := range albums {
+		complete := true
+
+		for _, filePath := range alb.files {
+			complete = complete && succeeded[filePath]
+		}
+
+		state.done[alb.dir] = complete
+	}
+
+	return state, nil
+}
@@ -0,0 +1,150 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium/internal/audit/silence"
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/report"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// cueFramesPerSecond is the CD frame rate CUE sheet timestamps (MM:SS:FF) are expressed in.
+const cueFramesPerSecond = 75
+
+func cueCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "cue",
+		Usage:     "Propose track boundaries at silences in a continuous transfer and write a CUE sheet",
+		ArgsUsage: "<file>",
+		Flags: append([]cli.Flag{
+			&cli.FloatFlag{
+				Name:  "min-gap",
+				Usage: "Minimum silence, in seconds, that separates two tracks",
+				Value: 2,
+			},
+			&cli.FloatFlag{
+				Name: "threshold",
+				Usage: "Level in dBFS below which audio counts as silence " +
+					"(vinyl surface noise usually sits around -50)",
+				Value: -50,
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Write the CUE sheet to this file instead of stdout",
+			},
+		}, binaries.Flags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 1 {
+				return errors.New("expected exactly one argument: path to audio file")
+			}
+
+			return runCue(
+				ctx,
+				binaries.FromCommand(cmd),
+				cmd.Args().First(),
+				cmd.Float("min-gap"),
+				cmd.Float("threshold"),
+				cmd.String("output"),
+			)
+		},
+	}
+}
+
+func runCue(
+	ctx context.Context,
+	bins binaries.Binaries,
+	filePath string,
+	minGapSec, thresholdDb float64,
+	outputPath string,
+) error {
+	pcmData, pcmFormat, _, err := report.Decode(ctx, filePath, nil, &report.Options{
+		FFprobe: bins.FFprobe,
+		FFmpeg:  bins.FFmpeg,
+	})
+	if err != nil {
+		return err
+	}
+
+	tracks, err := proposeTracks(pcmData, pcmFormat, minGapSec, thresholdDb)
+	if err != nil {
+		return err
+	}
+
+	if outputPath == "" {
+		return writeCue(os.Stdout, filepath.Base(filePath), tracks, pcmFormat.SampleRate)
+	}
+
+	out, err := os.Create(outputPath) //nolint:gosec // CLI tool writes user-specified output files
+	if err != nil {
+		return err
+	}
+
+	if err := writeCue(out, filepath.Base(filePath), tracks, pcmFormat.SampleRate); err != nil {
+		_ = out.Close()
+
+		return err
+	}
+
+	return out.Close()
+}
+
+// proposeTracks splits decoded PCM at its silences below thresholdDb lasting at least minGapSec.
+func proposeTracks(
+	pcmData []byte,
+	pcmFormat types.PCMFormat,
+	minGapSec, thresholdDb float64,
+) ([]types.TrackSplit, error) {
+	result, err := silence.Detect(bytes.NewReader(pcmData), pcmFormat, silence.Options{
+		ThresholdDb:   thresholdDb,
+		MinDurationMs: int(minGapSec * 1000),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return silence.Split(result, minGapSec), nil
+}
+
+func writeCue(w io.Writer, fileName string, tracks []types.TrackSplit, sampleRate int) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "FILE \"%s\" WAVE\n", fileName)
+
+	for _, track := range tracks {
+		fmt.Fprintf(&buf, "  TRACK %02d AUDIO\n", track.Number)
+		fmt.Fprintf(&buf, "    TITLE \"Track %02d\"\n", track.Number)
+
+		if track.PregapSample < track.StartSample {
+			fmt.Fprintf(&buf, "    INDEX 00 %s\n", cueTimestamp(track.PregapSample, sampleRate))
+		}
+
+		fmt.Fprintf(&buf, "    INDEX 01 %s\n", cueTimestamp(track.StartSample, sampleRate))
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// cueTimestamp formats a sample offset as MM:SS:FF, rounding down to the enclosing CD frame.
+func cueTimestamp(sample uint64, sampleRate int) string {
+	frames := sample * cueFramesPerSecond / uint64(sampleRate) //nolint:gosec // sample rate is validated positive
+
+	return fmt.Sprintf(
+		"%02d:%02d:%02d",
+		frames/(60*cueFramesPerSecond),
+		frames/cueFramesPerSecond%60,
+		frames%cueFramesPerSecond,
+	)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// TestCueSheet splits a side with known silences: 2.5 s of lead-in, the first track's pregap, a 2.5 s gap before
+// the second track, a 0.5 s pause inside it, under the 2 s minimum gap, and 0.5 s of run-out.
+func TestCueSheet(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	silences := [][2]float64{{0, 2.5}, {5.5, 8}, {10, 10.5}, {12, 12.5}}
+
+	data := testutil.Synthesize(format, 12.5, func(frame, _ int) float64 {
+		at := float64(frame) / float64(format.SampleRate)
+		for _, silence := range silences {
+			if at >= silence[0] && at < silence[1] {
+				return 0
+			}
+		}
+
+		return 0.5 * math.Sin(2*math.Pi*440*at)
+	})
+
+	tracks, err := proposeTracks(data, format, 2, -50)
+	if err != nil {
+		t.Fatalf("splitting failed: %v", err)
+	}
+
+	var sheet bytes.Buffer
+	if err := writeCue(&sheet, "side-a.wav", tracks, format.SampleRate); err != nil {
+		t.Fatalf("writing the CUE sheet failed: %v", err)
+	}
+
+	want := `FILE "side-a.wav" WAVE
+  TRACK 01 AUDIO
+    TITLE "Track 01"
+    INDEX 00 00:00:00
+    INDEX 01 00:02:37
+  TRACK 02 AUDIO
+    TITLE "Track 02"
+    INDEX 00 00:05:37
+    INDEX 01 00:08:00
+`
+
+	if sheet.String() != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, sheet.String())
+	}
+}
@@ -2,14 +2,20 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"slices"
 
 	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium/internal/report"
 )
 
 func digestCommand() *cli.Command {
@@ -19,8 +25,14 @@ func digestCommand() *cli.Command {
 		ArgsUsage: "<report.jsonl>",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:  "issue",
-				Usage: "Show files affected by a specific issue type (e.g., clipping, noise-floor)",
+				Name: "issue",
+				Usage: "Show files affected by a specific issue type (e.g., clipping, noise-floor, " +
+					"format-unexpected, too-short, channel-layout-suspect, gapless-metadata)",
+			},
+			&cli.StringFlag{
+				Name: "rebands",
+				Usage: "Re-interpret stored raw measurements against bands from a JSON file " +
+					"(check name to mild/moderate/severe)",
 			},
 		},
 		Action: func(_ context.Context, cmd *cli.Command) error {
@@ -28,28 +40,93 @@ func digestCommand() *cli.Command {
 				return errors.New("expected exactly one argument: path to report.jsonl")
 			}
 
-			return runDigest(cmd.Args().First(), cmd.String("issue"))
+			return runDigest(cmd.Args().First(), cmd.String("issue"), cmd.String("rebands"))
 		},
 	}
 }
 
-func runDigest(reportPath, issueFilter string) error {
+func runDigest(reportPath, issueFilter, rebandsPath string) error {
 	records, rawLines, err := readRecordsWithRaw(reportPath)
 	if err != nil {
 		return err
 	}
 
-	printDigest(records)
+	if rebandsPath != "" {
+		bands, err := loadRebands(rebandsPath)
+		if err != nil {
+			return err
+		}
 
-	if issueFilter != "" {
+		applyRebands(records, rawLines, bands)
+	}
+
+	printDigest(records, readSampleRecord(reportPath))
+
+	switch issueFilter {
+	case "":
+	case formatUnexpectedIssue:
+		printFormatDetail(records)
+	case tooShortIssue:
+		printTooShortDetail(records)
+	case layoutSuspectIssue:
+		printLayoutDetail(records)
+	case gaplessSuspectIssue:
+		printGaplessDetail(records)
+	default:
 		printIssueDetail(records, rawLines, issueFilter)
 	}
 
 	return nil
 }
 
-func readRecordsWithRaw(path string) ([]digestRecord, [][]byte, error) {
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// gzipReport is a gzip-compressed report: reads come decompressed, Close closes the file too.
+type gzipReport struct {
+	*gzip.Reader
+
+	file *os.File
+}
+
+func (report *gzipReport) Close() error {
+	return errors.Join(report.Reader.Close(), report.file.Close())
+}
+
+// openReport opens a report for reading, decompressing it when it is gzipped (report.jsonl.gz), whatever its
+// name: the magic bytes decide.
+func openReport(path string) (io.ReadCloser, error) {
 	file, err := os.Open(path) //nolint:gosec // CLI tool opens user-specified report files
+	if err != nil {
+		return nil, err
+	}
+
+	// A read error leaves magic short of gzip: a plain report, possibly empty.
+	magic := make([]byte, len(gzipMagic))
+	_, _ = io.ReadFull(file, magic)
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	if !bytes.Equal(magic, gzipMagic) {
+		return file, nil
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	return &gzipReport{Reader: reader, file: file}, nil
+}
+
+func readRecordsWithRaw(path string) ([]digestRecord, [][]byte, error) {
+	file, err := openReport(path)
 	if err != nil {
 		return nil, nil, fmt.Errorf("opening report: %w", err)
 	}
@@ -68,16 +145,22 @@ func readRecordsWithRaw(path string) ([]digestRecord, [][]byte, error) {
 	for scanner.Scan() {
 		line := make([]byte, len(scanner.Bytes()))
 		copy(line, scanner.Bytes())
-		lines = append(lines, line)
 
 		var rec digestRecord
 		if err := json.Unmarshal(line, &rec); err != nil {
 			records = append(records, digestRecord{Error: "parse error"})
+			lines = append(lines, line)
+
+			continue
+		}
 
+		// Album aggregate records and the header are not files.
+		if rec.Album != nil || rec.Scan != nil || rec.Sample != nil {
 			continue
 		}
 
 		records = append(records, rec)
+		lines = append(lines, line)
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -87,17 +170,42 @@ func readRecordsWithRaw(path string) ([]digestRecord, [][]byte, error) {
 	return records, lines, nil
 }
 
-func printDigest(records []digestRecord) {
+func printDigest(records []digestRecord, sample *report.SampleRecord) {
 	total := len(records)
 	errors := 0
+	unexpectedFormat := 0
+	tooShort := 0
+	layoutSuspect := 0
+	gaplessSuspect := 0
+	errorKinds := map[string]int{}
 	sevDist := map[string]int{"severe": 0, "moderate": 0, "mild": 0, "clean": 0}
 	issueDist := map[int]int{}
 	checkStats := map[string]*checkBreakdown{}
 
 	for _, rec := range records {
+		if rec.FormatUnexpected {
+			unexpectedFormat++
+		}
+
+		if rec.TooShort {
+			tooShort++
+		}
+
+		if rec.LayoutSuspect {
+			layoutSuspect++
+		}
+
+		if rec.GaplessOK != nil && !*rec.GaplessOK {
+			gaplessSuspect++
+		}
+
 		if rec.Error != "" || rec.Analysis == nil {
 			errors++
 
+			if rec.ErrorKind != "" {
+				errorKinds[rec.ErrorKind]++
+			}
+
 			continue
 		}
 
@@ -143,7 +251,29 @@ func printDigest(records []digestRecord) {
 	fmt.Println()
 	fmt.Printf("Total tracks:  %d\n", total)
 	fmt.Printf("Failed:        %d\n", errors)
+
+	for _, kind := range slices.Sorted(maps.Keys(errorKinds)) {
+		fmt.Printf("  %s: %d\n", kind, errorKinds[kind])
+	}
+
 	fmt.Printf("Analyzed:      %d\n", analyzed)
+
+	if unexpectedFormat > 0 {
+		fmt.Printf("Unexpected format: %d\n", unexpectedFormat)
+	}
+
+	if tooShort > 0 {
+		fmt.Printf("Too short:     %d\n", tooShort)
+	}
+
+	if layoutSuspect > 0 {
+		fmt.Printf("Layout suspect: %d\n", layoutSuspect)
+	}
+
+	if gaplessSuspect > 0 {
+		fmt.Printf("Gapless metadata: %d\n", gaplessSuspect)
+	}
+
 	fmt.Println()
 
 	fmt.Println("--- Worst Severity ---")
@@ -185,8 +315,24 @@ func printDigest(records []digestRecord) {
 		fmt.Printf("  %s\n", bd.Check)
 		fmt.Printf("    total: %d  severe: %d  moderate: %d  mild: %d\n", bd.Total, bd.Severe, bd.Moderate, bd.Mild)
 	}
+
+	if sample != nil {
+		printExtrapolation(sample, analyzed, sevDist["clean"], breakdowns)
+	}
 }
 
+// formatUnexpectedIssue selects tracks flagged by report --expect, which is a policy flag rather than a check.
+const formatUnexpectedIssue = "format-unexpected"
+
+// tooShortIssue selects tracks flagged by report --min-track-duration, also a policy flag.
+const tooShortIssue = "too-short"
+
+// layoutSuspectIssue selects tracks whose channel layout looks wrong: a probe inconsistency or a full-range LFE.
+const layoutSuspectIssue = "channel-layout-suspect"
+
+// gaplessSuspectIssue selects lossy tracks whose encoder delay or padding breaks gapless playback.
+const gaplessSuspectIssue = "gapless-metadata"
+
 //nolint:gochecknoglobals
 var checkKeyMap = map[string]string{
 	"clipping":           "clipping",
@@ -206,6 +352,15 @@ var checkKeyMap = map[string]string{
 	"loudness":           "loudness",
 	"dynamic-range":      "loudness",
 	"dropouts":           "dropouts",
+	"undithered":         "dither",
+	"de-essing":          "spectral",
+	"bass-rolloff":       "spectral",
+	"vinyl-cut-safety":   "stereo",
+	"repeats":            "repeats",
+	"pitch-offset":       "spectral",
+	"width-changes":      "stereo",
+	"trimmed-lead-in":    "truncation",
+	"spectral-tilt":      "spectral",
 }
 
 type issueEntry struct {
@@ -280,6 +435,130 @@ func printIssueDetail(records []digestRecord, rawLines [][]byte, check string) {
 	}
 }
 
+func printFormatDetail(records []digestRecord) {
+	fmt.Println()
+
+	var files []digestRecord
+
+	for _, rec := range records {
+		if rec.FormatUnexpected {
+			files = append(files, rec)
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Println("No tracks with an unexpected format")
+
+		return
+	}
+
+	fmt.Printf("=== %s: %d tracks ===\n\n", formatUnexpectedIssue, len(files))
+
+	for _, rec := range files {
+		file := rec.File
+		if file == "" {
+			file = "(redacted)"
+		}
+
+		fmt.Printf("  %s\n", file)
+		fmt.Printf("    %s\n", rec.FormatDetail)
+		fmt.Println()
+	}
+}
+
+func printTooShortDetail(records []digestRecord) {
+	fmt.Println()
+
+	var files []digestRecord
+
+	for _, rec := range records {
+		if rec.TooShort {
+			files = append(files, rec)
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Println("No tracks under the minimum duration")
+
+		return
+	}
+
+	fmt.Printf("=== %s: %d tracks ===\n\n", tooShortIssue, len(files))
+
+	for _, rec := range files {
+		file := rec.File
+		if file == "" {
+			file = "(redacted)"
+		}
+
+		fmt.Printf("  %s\n", file)
+		fmt.Printf("    %.1fs\n", rec.DurationSec)
+		fmt.Println()
+	}
+}
+
+func printLayoutDetail(records []digestRecord) {
+	fmt.Println()
+
+	var files []digestRecord
+
+	for _, rec := range records {
+		if rec.LayoutSuspect {
+			files = append(files, rec)
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Println("No tracks with a suspect channel layout")
+
+		return
+	}
+
+	fmt.Printf("=== %s: %d tracks ===\n\n", layoutSuspectIssue, len(files))
+
+	for _, rec := range files {
+		file := rec.File
+		if file == "" {
+			file = "(redacted)"
+		}
+
+		fmt.Printf("  %s\n", file)
+		fmt.Printf("    %s\n", rec.LayoutDetail)
+		fmt.Println()
+	}
+}
+
+func printGaplessDetail(records []digestRecord) {
+	fmt.Println()
+
+	var files []digestRecord
+
+	for _, rec := range records {
+		if rec.GaplessOK != nil && !*rec.GaplessOK {
+			files = append(files, rec)
+		}
+	}
+
+	if len(files) == 0 {
+		fmt.Println("No lossy tracks with broken gapless metadata")
+
+		return
+	}
+
+	fmt.Printf("=== %s: %d tracks ===\n\n", gaplessSuspectIssue, len(files))
+
+	for _, rec := range files {
+		file := rec.File
+		if file == "" {
+			file = "(redacted)"
+		}
+
+		fmt.Printf("  %s\n", file)
+		fmt.Printf("    %s\n", rec.GaplessDetail)
+		fmt.Println()
+	}
+}
+
 func extractDetailFromRaw(rawLine []byte, key string) map[string]any {
 	var full struct {
 		Analysis map[string]any `json:"analysis"`
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const digestedReport = `{"scan":{"started":"2026-01-02T03:04:05Z","complete":true}}
+{"file":"/music/a.flac","analysis":{"summary":{"issue_count":0,"worst_severity":"no issue"},"issues":[]}}
+{"file":"/music/b.flac","error":"decoding failed","error_kind":"decode"}
+`
+
+// TestReadRecordsGzip reads a gzipped report as its plain version, whatever its name: the magic bytes decide.
+func TestReadRecordsGzip(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var compressed bytes.Buffer
+
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(digestedReport)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reports := map[string][]byte{
+		"report.jsonl":    []byte(digestedReport),
+		"report.jsonl.gz": compressed.Bytes(),
+		"renamed.jsonl":   compressed.Bytes(),
+	}
+
+	for name, content := range reports {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plain, plainLines, err := readRecordsWithRaw(filepath.Join(dir, "report.jsonl"))
+	if err != nil || len(plain) != 2 {
+		t.Fatalf("expected the 2 file records of the plain report, got %d: %v", len(plain), err)
+	}
+
+	for _, name := range []string{"report.jsonl.gz", "renamed.jsonl"} {
+		records, lines, err := readRecordsWithRaw(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if !reflect.DeepEqual(records, plain) || !reflect.DeepEqual(lines, plainLines) {
+			t.Errorf("%s: expected the records of the plain report, got %+v", name, records)
+		}
+	}
+}
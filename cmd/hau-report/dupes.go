@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium/internal/fingerprint"
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+// dupesMaxDurationDiffSec bounds the duration difference of two tracks compared acoustically: the fingerprint
+// alignment tolerates a few seconds of leading silence, not a different edit.
+const dupesMaxDurationDiffSec = 5.0
+
+var errNoFingerprints = errors.New("no fingerprinted records (run hau-report report --fingerprint)")
+
+func dupesCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "dupes",
+		Usage:     "List likely duplicate tracks in a haustorium JSONL report made with --fingerprint",
+		ArgsUsage: "<report.jsonl>",
+		Flags: []cli.Flag{
+			&cli.FloatFlag{
+				Name:  "max-ber",
+				Usage: "Bit error rate (0-1) under which two acoustic fingerprints are the same recording",
+				Value: fingerprint.MatchBitErrorRate,
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 1 {
+				return errors.New("expected exactly one argument: path to report.jsonl")
+			}
+
+			return runDupes(os.Stdout, cmd.Args().First(), cmd.Float("max-ber"))
+		},
+	}
+}
+
+type fingerprinted struct {
+	file        string
+	fingerprint *fingerprint.Fingerprint
+}
+
+// dupeCluster is a group of likely duplicates. Exact clusters share the same decoded samples.
+type dupeCluster struct {
+	files []string
+	exact bool
+	ber   float64 // worst bit error rate linking the cluster, for acoustic clusters
+}
+
+func runDupes(writer io.Writer, reportPath string, maxBER float64) error {
+	tracks, err := readFingerprints(reportPath)
+	if err != nil {
+		return err
+	}
+
+	if len(tracks) == 0 {
+		return errNoFingerprints
+	}
+
+	clusters := clusterDupes(tracks, maxBER)
+
+	if len(clusters) == 0 {
+		_, err = fmt.Fprintf(writer, "No duplicates among %d tracks\n", len(tracks))
+
+		return err
+	}
+
+	for idx, cluster := range clusters {
+		kind := "identical samples"
+		if !cluster.exact {
+			kind = fmt.Sprintf("same recording, %.0f%% of fingerprint bits differ", cluster.ber*100)
+		}
+
+		if _, err = fmt.Fprintf(writer, "\nCluster %d (%s):\n", idx+1, kind); err != nil {
+			return err
+		}
+
+		for _, file := range cluster.files {
+			if _, err = fmt.Fprintf(writer, "  %s\n", file); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readFingerprints(path string) ([]fingerprinted, error) {
+	file, err := openReport(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening report: %w", err)
+	}
+	defer file.Close()
+
+	var tracks []fingerprinted
+
+	scanner := bufio.NewScanner(file)
+
+	const maxLineSize = 1024 * 1024 // 1MB
+	scanner.Buffer(make([]byte, 0, maxLineSize), maxLineSize)
+
+	for scanner.Scan() {
+		var rec report.Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Fingerprint == nil {
+			continue
+		}
+
+		tracks = append(tracks, fingerprinted{file: rec.File, fingerprint: rec.Fingerprint})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// clusterDupes groups tracks with identical samples, then links the remaining groups whose acoustic fingerprints
+// match. Only tracks of similar duration are compared, so the cost stays close to linear on a collection.
+func clusterDupes(tracks []fingerprinted, maxBER float64) []dupeCluster {
+	// Exact groups, by content hash.
+	var groups [][]int
+
+	byHash := map[string]int{}
+
+	for idx, track := range tracks {
+		group, ok := byHash[track.fingerprint.PCMHash]
+		if !ok {
+			group = len(groups)
+			byHash[track.fingerprint.PCMHash] = group
+			groups = append(groups, nil)
+		}
+
+		groups[group] = append(groups[group], idx)
+	}
+
+	// Acoustic links between groups (union-find), comparing one representative per group.
+	parent := make([]int, len(groups))
+	worst := make([]float64, len(groups))
+
+	for group := range parent {
+		parent[group] = group
+	}
+
+	var find func(int) int
+
+	find = func(group int) int {
+		if parent[group] != group {
+			parent[group] = find(parent[group])
+		}
+
+		return parent[group]
+	}
+
+	order := make([]int, len(groups))
+	for group := range order {
+		order[group] = group
+	}
+
+	duration := func(group int) float64 { return tracks[groups[group][0]].fingerprint.DurationSec }
+
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(duration(a), duration(b)) })
+
+	for i, first := range order {
+		for _, second := range order[i+1:] {
+			if duration(second)-duration(first) > dupesMaxDurationDiffSec {
+				break
+			}
+
+			ber := fingerprint.BitErrorRate(
+				tracks[groups[first][0]].fingerprint.Acoustic,
+				tracks[groups[second][0]].fingerprint.Acoustic,
+			)
+			if ber >= maxBER {
+				continue
+			}
+
+			rootFirst, rootSecond := find(first), find(second)
+			if rootFirst != rootSecond {
+				parent[rootSecond] = rootFirst
+				worst[rootFirst] = math.Max(math.Max(worst[rootFirst], worst[rootSecond]), ber)
+			} else {
+				worst[rootFirst] = math.Max(worst[rootFirst], ber)
+			}
+		}
+	}
+
+	members := map[int][]int{}
+
+	for group := range groups {
+		root := find(group)
+		members[root] = append(members[root], group)
+	}
+
+	var clusters []dupeCluster
+
+	for root, linked := range members {
+		var files []string
+
+		for _, group := range linked {
+			for _, idx := range groups[group] {
+				files = append(files, tracks[idx].file)
+			}
+		}
+
+		if len(files) < 2 {
+			continue
+		}
+
+		slices.Sort(files)
+
+		clusters = append(clusters, dupeCluster{files: files, exact: len(linked) == 1, ber: worst[root]})
+	}
+
+	// Exact clusters first, then by first file.
+	slices.SortFunc(clusters, func(a, b dupeCluster) int {
+		if a.exact != b.exact {
+			if a.exact {
+				return -1
+			}
+
+			return 1
+		}
+
+		return cmp.Compare(a.files[0], b.files[0])
+	})
+
+	return clusters
+}
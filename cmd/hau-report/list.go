@@ -0,0 +1,67 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"bufio"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// stdinList is the --from-file value reading the list from standard input.
+const stdinList = "-"
+
+// listAudioFiles reads a newline-delimited list of audio files (from stdin for "-"), in place of walking a folder.
+// Each entry is still validated: entries that are missing, not regular files, or not .flac or .m4a are skipped
+// with a warning, and --since applies. The result is sorted, without duplicates, so albums stay contiguous.
+func listAudioFiles(list string, since time.Time) ([]string, error) {
+	var reader io.Reader = os.Stdin
+
+	if list != stdinList {
+		file, err := os.Open(list) //nolint:gosec // path is intentionally user-provided
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	var files []string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		path := strings.TrimSpace(scanner.Text())
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			slog.Warn("skipping listed file", "file", path, "error", err)
+
+			continue
+		}
+
+		switch {
+		case !info.Mode().IsRegular():
+			slog.Warn("skipping listed file", "file", path, "error", "not a regular file")
+		case !isAudioFile(path):
+			slog.Warn("skipping listed file", "file", path, "error", "not a .flac or .m4a file")
+		case !since.IsZero() && !info.ModTime().After(since):
+		default:
+			files = append(files, path)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.Sort(files)
+
+	return slices.Compact(files), nil
+}
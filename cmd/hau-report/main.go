@@ -19,7 +19,12 @@ func main() {
 		Version: version.Version() + " " + version.Commit(),
 		Commands: []*cli.Command{
 			reportCommand(),
+			watchCommand(),
 			digestCommand(),
+			cueCommand(),
+			playlistCommand(),
+			dupesCommand(),
+			notchesCommand(),
 		},
 	}
 
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	notchesTolerance = 0.02 // relative frequency difference under which two notches are the same
+	notchesMinHz     = 12.0 // and in Hz, at least: about two bins of the spectral analysis at 44.1 kHz
+	notchesMinShared = 2
+)
+
+var errNoNotches = errors.New("no spectral notches in the report (it needs the spectral raw block: --raw all)")
+
+func notchesCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "notches",
+		Usage:     "Group the tracks of a haustorium JSONL report sharing the same spectral notches: the same chain",
+		ArgsUsage: "<report.jsonl>",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "min-shared",
+				Usage: "Notches two tracks must share to be linked",
+				Value: notchesMinShared,
+			},
+			&cli.FloatFlag{
+				Name:  "tolerance",
+				Usage: "Relative frequency difference (0-1) under which two notches are the same",
+				Value: notchesTolerance,
+			},
+		},
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 1 {
+				return errors.New("expected exactly one argument: path to report.jsonl")
+			}
+
+			return runNotches(os.Stdout, cmd.Args().First(), max(cmd.Int("min-shared"), 1), cmd.Float("tolerance"))
+		},
+	}
+}
+
+// notchedTrack is a track with spectral notches, by frequency.
+type notchedTrack struct {
+	file    string
+	notches []recordNotch
+}
+
+type recordNotch struct {
+	Hz      float64 `json:"hz"`
+	DepthDb float64 `json:"depth_db"`
+}
+
+// notchCluster is a group of tracks linked by shared notches, with the notches all of them share.
+type notchCluster struct {
+	tracks []notchedTrack
+	common []float64
+}
+
+func runNotches(writer io.Writer, reportPath string, minShared int, tolerance float64) error {
+	tracks, err := readNotches(reportPath)
+	if err != nil {
+		return err
+	}
+
+	if len(tracks) == 0 {
+		return errNoNotches
+	}
+
+	clusters := clusterNotches(tracks, minShared, tolerance)
+
+	if len(clusters) == 0 {
+		_, err = fmt.Fprintf(writer, "No shared notches among %d tracks with notches\n", len(tracks))
+
+		return err
+	}
+
+	for idx, cluster := range clusters {
+		header := fmt.Sprintf("\nCluster %d (%d tracks", idx+1, len(cluster.tracks))
+		if len(cluster.common) > 0 {
+			header += ", all notched at " + formatHz(cluster.common)
+		}
+
+		if _, err = fmt.Fprintln(writer, header+"):"); err != nil {
+			return err
+		}
+
+		for _, track := range cluster.tracks {
+			notches := make([]string, 0, len(track.notches))
+			for _, notch := range track.notches {
+				notches = append(notches, fmt.Sprintf("%.0f Hz (-%.0f dB)", notch.Hz, notch.DepthDb))
+			}
+
+			if _, err = fmt.Fprintf(writer, "  %s: %s\n", track.file, strings.Join(notches, ", ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func readNotches(path string) ([]notchedTrack, error) {
+	file, err := openReport(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening report: %w", err)
+	}
+	defer file.Close()
+
+	var tracks []notchedTrack
+
+	scanner := bufio.NewScanner(file)
+
+	const maxLineSize = 1024 * 1024 // 1MB
+	scanner.Buffer(make([]byte, 0, maxLineSize), maxLineSize)
+
+	for scanner.Scan() {
+		var rec struct {
+			File     string `json:"file"`
+			Analysis struct {
+				Spectral struct {
+					Notches []recordNotch `json:"notches"`
+				} `json:"spectral"`
+			} `json:"analysis"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || len(rec.Analysis.Spectral.Notches) == 0 {
+			continue
+		}
+
+		tracks = append(tracks, notchedTrack{file: rec.File, notches: rec.Analysis.Spectral.Notches})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading report: %w", err)
+	}
+
+	return tracks, nil
+}
+
+// clusterNotches links the tracks sharing at least minShared notches (union-find). The notches of the collection
+// are sorted by frequency, so that only those within the tolerance of each other are compared.
+func clusterNotches(tracks []notchedTrack, minShared int, tolerance float64) []notchCluster {
+	type entry struct {
+		hz    float64
+		track int
+	}
+
+	var entries []entry
+
+	for idx, track := range tracks {
+		for _, notch := range track.notches {
+			entries = append(entries, entry{hz: notch.Hz, track: idx})
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int { return cmp.Compare(a.hz, b.hz) })
+
+	shared := map[[2]int]int{}
+
+	for i, first := range entries {
+		for _, second := range entries[i+1:] {
+			if !sameNotch(first.hz, second.hz, tolerance) {
+				break
+			}
+
+			if first.track != second.track {
+				pair := [2]int{min(first.track, second.track), max(first.track, second.track)}
+				shared[pair]++
+			}
+		}
+	}
+
+	parent := make([]int, len(tracks))
+	for idx := range parent {
+		parent[idx] = idx
+	}
+
+	var find func(int) int
+
+	find = func(idx int) int {
+		if parent[idx] != idx {
+			parent[idx] = find(parent[idx])
+		}
+
+		return parent[idx]
+	}
+
+	for pair, count := range shared {
+		if count < minShared {
+			continue
+		}
+
+		if rootFirst, rootSecond := find(pair[0]), find(pair[1]); rootFirst != rootSecond {
+			parent[rootSecond] = rootFirst
+		}
+	}
+
+	members := map[int][]notchedTrack{}
+
+	for idx, track := range tracks {
+		root := find(idx)
+		members[root] = append(members[root], track)
+	}
+
+	var clusters []notchCluster
+
+	for _, linked := range members {
+		if len(linked) < 2 {
+			continue
+		}
+
+		slices.SortFunc(linked, func(a, b notchedTrack) int { return cmp.Compare(a.file, b.file) })
+
+		clusters = append(clusters, notchCluster{tracks: linked, common: commonNotches(linked, tolerance)})
+	}
+
+	// Largest clusters first, then by first file.
+	slices.SortFunc(clusters, func(a, b notchCluster) int {
+		if len(a.tracks) != len(b.tracks) {
+			return cmp.Compare(len(b.tracks), len(a.tracks))
+		}
+
+		return cmp.Compare(a.tracks[0].file, b.tracks[0].file)
+	})
+
+	return clusters
+}
+
+// commonNotches returns the notch frequencies of the first track found in every other track.
+func commonNotches(tracks []notchedTrack, tolerance float64) []float64 {
+	var common []float64
+
+	for _, notch := range tracks[0].notches {
+		everywhere := true
+
+		for _, track := range tracks[1:] {
+			if !slices.ContainsFunc(track.notches, func(other recordNotch) bool {
+				return sameNotch(notch.Hz, other.Hz, tolerance)
+			}) {
+				everywhere = false
+
+				break
+			}
+		}
+
+		if everywhere {
+			common = append(common, notch.Hz)
+		}
+	}
+
+	return common
+}
+
+// sameNotch tells whether two notch frequencies are within the tolerance of each other.
+func sameNotch(first, second, tolerance float64) bool {
+	diff := second - first
+	if diff < 0 {
+		diff = -diff
+	}
+
+	return diff <= max(tolerance*min(first, second), notchesMinHz)
+}
+
+func formatHz(frequencies []float64) string {
+	parts := make([]string, 0, len(frequencies))
+	for _, hz := range frequencies {
+		parts = append(parts, fmt.Sprintf("%.0f", hz))
+	}
+
+	return strings.Join(parts, ", ") + " Hz"
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+const (
+	notifyMask      = syscall.IN_CREATE | syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO
+	notifyBufEvents = 64
+	notifyBuffered  = 64
+)
+
+// notifier maps the inotify watches of a folder tree to their directories.
+type notifier struct {
+	fd   int
+	dirs map[int32]string
+}
+
+// watchFolder reports, through inotify, the entries created, written or moved in anywhere under root: files, and
+// directories as a whole, which are watched in turn. After an event queue overflow, root itself is reported, to be
+// scanned again. The channel is closed once ctx is canceled.
+func watchFolder(ctx context.Context, root string) (<-chan string, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("inotify: %w", err)
+	}
+
+	// Non-blocking, the descriptor goes through the runtime poller: closing it ends a pending read.
+	file := os.NewFile(uintptr(fd), "inotify")
+	watches := &notifier{fd: fd, dirs: map[int32]string{}}
+
+	if err := watches.addTree(root); err != nil {
+		file.Close()
+
+		return nil, err
+	}
+
+	changes := make(chan string, notifyBuffered)
+
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	go watches.read(ctx, file, root, changes)
+
+	return changes, nil
+}
+
+// addTree watches dir and the directories under it.
+func (n *notifier) addTree(dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+
+		wd, err := syscall.InotifyAddWatch(n.fd, path, notifyMask|syscall.IN_ONLYDIR)
+		if err != nil {
+			return fmt.Errorf("watching %q: %w", path, err)
+		}
+
+		n.dirs[int32(wd)] = path //nolint:gosec // watch descriptors are int32 in the events
+
+		return nil
+	})
+}
+
+func (n *notifier) read(ctx context.Context, file *os.File, root string, changes chan<- string) {
+	defer close(changes)
+
+	buf := make([]byte, notifyBufEvents*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+
+	send := func(path string) bool {
+		select {
+		case changes <- path:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		count, err := file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= count; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:])) //nolint:gosec // the event's int32 field
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			length := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			name := strings.TrimRight(string(buf[offset+syscall.SizeofInotifyEvent:][:length]), "\x00")
+			offset += syscall.SizeofInotifyEvent + length
+
+			switch {
+			case mask&syscall.IN_Q_OVERFLOW != 0:
+				if !send(root) {
+					return
+				}
+			case mask&syscall.IN_IGNORED != 0:
+				delete(n.dirs, wd)
+			case name == "" || n.dirs[wd] == "":
+			default:
+				path := filepath.Join(n.dirs[wd], name)
+
+				if mask&syscall.IN_ISDIR != 0 {
+					if err := n.addTree(path); err != nil {
+						slog.Error("watching new directory", "dir", path, "error", err)
+					}
+				}
+
+				if !send(path) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+var errNotifyUnsupported = errors.New("filesystem notifications are only implemented on Linux")
+
+// watchFolder is not implemented on this system: the folder is scanned instead.
+func watchFolder(_ context.Context, _ string) (<-chan string, error) {
+	return nil, errNotifyUnsupported
+}
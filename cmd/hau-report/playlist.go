@@ -0,0 +1,163 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+var (
+	errPlaylistTooShort     = errors.New("expected at least two files")
+	errPlaylistNonCompliant = errors.New("playlist is not compliant")
+)
+
+// playlistEntry is the loudness and true peak of one playlist item, or why they could not be measured.
+type playlistEntry struct {
+	file       string
+	lufs       float64
+	truePeakDb float64
+	err        error
+}
+
+func playlistCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "playlist",
+		Usage:     "Check that a sequence of files sits within a loudness tolerance and under a true peak ceiling",
+		ArgsUsage: "<file> <file>...",
+		Flags: append([]cli.Flag{
+			&cli.FloatFlag{
+				Name:  "target",
+				Usage: "Target integrated loudness in LUFS (default: the first file's loudness)",
+			},
+			&cli.FloatFlag{
+				Name:  "tolerance",
+				Usage: "Allowed deviation from the target, in LU",
+				Value: 1,
+			},
+			&cli.FloatFlag{
+				Name:  "ceiling",
+				Usage: "Maximum true peak in dBTP",
+				Value: -1,
+			},
+		}, binaries.Flags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.NArg() < 2 {
+				return errPlaylistTooShort
+			}
+
+			var target *float64
+			if cmd.IsSet("target") {
+				value := cmd.Float("target")
+				target = &value
+			}
+
+			entries := measurePlaylist(ctx, binaries.FromCommand(cmd), cmd.Args().Slice())
+
+			return printPlaylist(os.Stdout, entries, target, cmd.Float("tolerance"), cmd.Float("ceiling"))
+		},
+	}
+}
+
+func measurePlaylist(ctx context.Context, bins binaries.Binaries, files []string) []playlistEntry {
+	entries := make([]playlistEntry, 0, len(files))
+
+	for _, filePath := range files {
+		entry := playlistEntry{file: filePath}
+
+		pcmData, pcmFormat, _, err := report.Decode(ctx, filePath, nil, &report.Options{
+			FFprobe: bins.FFprobe,
+			FFmpeg:  bins.FFmpeg,
+		})
+		if err != nil {
+			entry.err = err
+			entries = append(entries, entry)
+
+			continue
+		}
+
+		opts := haustorium.DefaultOptions()
+		opts.Checks = haustorium.CheckLoudness | haustorium.CheckInterSamplePeaks
+
+		result, err := haustorium.Analyze(func() (io.Reader, error) {
+			return bytes.NewReader(pcmData), nil
+		}, pcmFormat, opts)
+		if err != nil {
+			entry.err = fmt.Errorf("analysis failed: %w", err)
+		} else {
+			entry.lufs = result.Loudness.IntegratedLUFS
+			entry.truePeakDb = result.TruePeak.TruePeakDb
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// printPlaylist writes one line per entry with its loudness relative to the target, and flags outliers
+// and true peak overs. The target defaults to the first measured entry.
+func printPlaylist(w io.Writer, entries []playlistEntry, target *float64, tolerance, ceiling float64) error {
+	reference := math.NaN()
+	if target != nil {
+		reference = *target
+	}
+
+	for _, entry := range entries {
+		if math.IsNaN(reference) && entry.err == nil {
+			reference = entry.lufs
+		}
+	}
+
+	fmt.Fprintf(w, "Target: %.1f LUFS ±%.1f LU, ceiling %.1f dBTP\n\n", reference, tolerance, ceiling)
+
+	var outliers, overs, failures int
+
+	for idx, entry := range entries {
+		name := filepath.Base(entry.file)
+
+		if entry.err != nil {
+			failures++
+
+			fmt.Fprintf(w, "%3d. %-40s error: %v\n", idx+1, name, entry.err)
+
+			continue
+		}
+
+		var flags string
+
+		delta := entry.lufs - reference
+		if math.Abs(delta) > tolerance {
+			outliers++
+			flags += "  [loudness outlier]"
+		}
+
+		if entry.truePeakDb > ceiling {
+			overs++
+			flags += "  [over ceiling]"
+		}
+
+		fmt.Fprintf(w, "%3d. %-40s %6.1f LUFS (%+5.1f LU)  %5.1f dBTP%s\n",
+			idx+1, name, entry.lufs, delta, entry.truePeakDb, flags)
+	}
+
+	fmt.Fprintf(w, "\n%d files: %d loudness outliers, %d over ceiling, %d errors\n",
+		len(entries), outliers, overs, failures)
+
+	if outliers+overs+failures > 0 {
+		return errPlaylistNonCompliant
+	}
+
+	return nil
+}
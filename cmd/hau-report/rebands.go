@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/farcloser/haustorium"
+)
+
+var errUnknownRebandCheck = errors.New("check has no banded metric")
+
+// rebandMetric extracts the value a check is banded on from the raw analysis of a record.
+// ok is false when the raw data is missing, or (for hum) when the check is not detected at all.
+type rebandMetric func(analysis map[string]any) (value float64, ok bool)
+
+// rebandMetrics mirrors the values interpretResults feeds to Bands.Match for each banded check.
+// Secondary adjustments (clipping plateaus, ISP downweighting on lossy sources) are not replayed.
+//
+//nolint:gochecknoglobals
+var rebandMetrics = map[string]rebandMetric{
+	"clipping":     rawField("clipping", "events"),
+	"truncation":   rawField("truncation", "final_rms_db"),
+	"dc-offset":    rawField("dc_offset", "offset_db"),
+	"phase-issues": rawField("stereo", "cancellation_db"),
+	"channel-imbalance": func(analysis map[string]any) (float64, bool) {
+		value, ok := rawField("stereo", "imbalance_db")(analysis)
+
+		return math.Abs(value), ok
+	},
+	"silence-padding": func(analysis map[string]any) (float64, bool) {
+		leading, ok := rawField("silence", "leading_sec")(analysis)
+		trailing, _ := rawField("silence", "trailing_sec")(analysis)
+
+		return max(leading, trailing), ok
+	},
+	"hum": func(analysis map[string]any) (float64, bool) {
+		has50, _ := rawPath(analysis, "spectral", "has_50hz_hum").(bool)
+		has60, _ := rawPath(analysis, "spectral", "has_60hz_hum").(bool)
+
+		if !has50 && !has60 {
+			return 0, false
+		}
+
+		return rawField("spectral", "hum_level_db")(analysis)
+	},
+	"noise-floor": rawField("spectral", "noise_floor_db"),
+	"bass-rolloff": func(analysis map[string]any) (float64, bool) {
+		if rolloff, _ := rawPath(analysis, "spectral", "has_bass_rolloff").(bool); !rolloff {
+			return 0, false
+		}
+
+		return rawField("spectral", "bass_cutoff_hz")(analysis)
+	},
+	"spectral-tilt": func(analysis map[string]any) (float64, bool) {
+		tilt, ok := rawField("spectral", "spectral_tilt_db")(analysis)
+
+		return math.Abs(tilt - haustorium.SpectralTiltTypicalDbPerOct), ok
+	},
+	"inter-sample-peaks": rawField("true_peak", "isp_count"),
+	"dynamic-range":      rawField("loudness", "dr_score"),
+	"dropouts": func(analysis map[string]any) (float64, bool) {
+		deltas, ok := rawField("dropouts", "delta_count")(analysis)
+		zeroRuns, _ := rawField("dropouts", "zero_run_count")(analysis)
+		dcJumps, _ := rawField("dropouts", "dc_jump_count")(analysis)
+		impulses, _ := rawField("dropouts", "impulse_count")(analysis)
+		stuck, _ := rawField("dropouts", "stuck_count")(analysis)
+
+		return deltas + zeroRuns + dcJumps + impulses + stuck, ok
+	},
+	"repeats": rawField("repeats", "count"),
+}
+
+func rawPath(analysis map[string]any, section, key string) any {
+	detail, ok := analysis[section].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return detail[key]
+}
+
+func rawField(section, key string) rebandMetric {
+	return func(analysis map[string]any) (float64, bool) {
+		value, ok := rawPath(analysis, section, key).(float64)
+
+		return value, ok
+	}
+}
+
+// loadRebands reads a JSON object mapping check names to bands, e.g.
+// {"clipping": {"mild": 5, "moderate": 50, "severe": 500}}.
+func loadRebands(path string) (map[string]haustorium.Bands, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // CLI tool opens user-specified threshold files
+	if err != nil {
+		return nil, fmt.Errorf("reading rebands: %w", err)
+	}
+
+	var bands map[string]haustorium.Bands
+	if err := json.Unmarshal(data, &bands); err != nil {
+		return nil, fmt.Errorf("parsing rebands: %w", err)
+	}
+
+	for check := range bands {
+		if _, ok := rebandMetrics[check]; !ok {
+			known := slices.Sorted(maps.Keys(rebandMetrics))
+
+			return nil, fmt.Errorf("%w: %q (banded checks: %s)",
+				errUnknownRebandCheck, check, strings.Join(known, ", "))
+		}
+	}
+
+	return bands, nil
+}
+
+// applyRebands re-interprets the stored raw measurements of every record against new bands,
+// rewriting issue severities and the per-record summary in place.
+func applyRebands(records []digestRecord, rawLines [][]byte, bands map[string]haustorium.Bands) {
+	for idx := range records {
+		rec := &records[idx]
+		if rec.Error != "" || rec.Analysis == nil || idx >= len(rawLines) {
+			continue
+		}
+
+		var full struct {
+			Analysis map[string]any `json:"analysis"`
+		}
+
+		if err := json.Unmarshal(rawLines[idx], &full); err != nil || full.Analysis == nil {
+			continue
+		}
+
+		for i := range rec.Analysis.Issues {
+			rebandIssue(&rec.Analysis.Issues[i], full.Analysis, bands)
+		}
+
+		rec.Analysis.Summary = summarizeIssues(rec.Analysis.Issues)
+	}
+}
+
+func rebandIssue(issue *digestIssue, analysis map[string]any, bands map[string]haustorium.Bands) {
+	band, ok := bands[issue.Check]
+	if !ok {
+		return
+	}
+
+	value, ok := rebandMetrics[issue.Check](analysis)
+	if !ok {
+		return
+	}
+
+	severity, detected := band.Match(value)
+
+	// Hum and bass rolloff detection comes from the spectral flags; bands only grade it.
+	if issue.Check == "hum" || issue.Check == "bass-rolloff" {
+		detected = true
+
+		if severity == haustorium.SeverityNone {
+			severity = haustorium.SeverityMild
+		}
+	}
+
+	if severity.String() != issue.Severity {
+		issue.Summary = fmt.Sprintf("%s (rebanded from %s: %g)", issue.Summary, issue.Severity, value)
+	}
+
+	issue.Severity = severity.String()
+	issue.Detected = detected
+}
+
+func summarizeIssues(issues []digestIssue) digestSummary {
+	summary := digestSummary{WorstSeverity: haustorium.SeverityNone.String()}
+
+	for _, issue := range issues {
+		if issue.Detected {
+			summary.IssueCount++
+		}
+
+		if severityRank(issue.Severity) < severityRank(summary.WorstSeverity) {
+			summary.WorstSeverity = issue.Severity
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rebandedRecord is a report record whose clipping was graded mild on 20 events.
+const rebandedRecord = `{"file":"/music/track.flac","analysis":{` +
+	`"summary":{"issue_count":1,"worst_severity":"mild"},` +
+	`"issues":[{"check":"clipping","detected":true,"severity":"mild","summary":"20 clipping events"},` +
+	`{"check":"dc-offset","detected":false,"severity":"no issue","summary":"No DC offset"}],` +
+	`"clipping":{"events":20},"dc_offset":{"offset_db":-90}}}`
+
+func rebandRecord(t *testing.T, rebands string) []digestRecord {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	reportPath := filepath.Join(dir, "report.jsonl")
+	if err := os.WriteFile(reportPath, []byte(rebandedRecord+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rebandsPath := filepath.Join(dir, "rebands.json")
+	if err := os.WriteFile(rebandsPath, []byte(rebands), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	records, rawLines, err := readRecordsWithRaw(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bands, err := loadRebands(rebandsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applyRebands(records, rawLines, bands)
+
+	return records
+}
+
+func TestApplyRebands(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		rebands  string
+		severity string
+		count    int
+	}{
+		{`{"clipping": {"mild": 1, "moderate": 5, "severe": 10}}`, "severe", 1},
+		{`{"clipping": {"mild": 50, "moderate": 100, "severe": 500}}`, "no issue", 0},
+		{`{"dc-offset": {"mild": -60, "moderate": -40, "severe": -20}}`, "mild", 1}, // clipping left as graded
+	} {
+		analysis := rebandRecord(t, tc.rebands)[0].Analysis
+
+		if clipping := analysis.Issues[0]; clipping.Severity != tc.severity || clipping.Detected != (tc.count > 0) {
+			t.Errorf("%s: expected clipping %s, got %s (detected: %t)", tc.rebands, tc.severity, clipping.Severity,
+				clipping.Detected)
+		}
+
+		if analysis.Summary.WorstSeverity != tc.severity || analysis.Summary.IssueCount != tc.count {
+			t.Errorf("%s: expected a %s summary with %d issues, got %+v", tc.rebands, tc.severity, tc.count,
+				analysis.Summary)
+		}
+	}
+}
+
+func TestLoadRebandsUnknownCheck(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "rebands.json")
+	if err := os.WriteFile(path, []byte(`{"loudness": {"mild": 1, "moderate": 2, "severe": 3}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadRebands(path); !errors.Is(err, errUnknownRebandCheck) {
+		t.Fatalf("expected %v, got %v", errUnknownRebandCheck, err)
+	}
+}
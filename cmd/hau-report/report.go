@@ -3,19 +3,18 @@ package main
 
 import (
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -24,110 +23,352 @@ import (
 	"github.com/urfave/cli/v3"
 
 	"github.com/farcloser/haustorium"
-	"github.com/farcloser/haustorium/internal/integration/ffmpeg"
-	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/binaries"
 	"github.com/farcloser/haustorium/internal/output"
-	"github.com/farcloser/haustorium/internal/types"
+	"github.com/farcloser/haustorium/internal/report"
 )
 
 const outputFile = "haustorium-report.jsonl"
 
 var (
-	errNotDirectory      = errors.New("not a directory")
-	errNoAudioFiles      = errors.New("no .flac or .m4a files found")
-	errNoAudioStream     = errors.New("no audio streams found")
-	errInvalidSampleRate = errors.New("invalid sample rate")
-	errInvalidChannels   = errors.New("invalid channel count")
-	errInvalidBitDepth   = errors.New("must be 16, 24, or 32")
+	errNotDirectory     = errors.New("not a directory")
+	errNoAudioFiles     = errors.New("no .flac or .m4a files found")
+	errSinceConflict    = errors.New("--since and --since-report are mutually exclusive")
+	errInvalidSince     = errors.New("must be an RFC3339 timestamp or @file")
+	errSampleConflict   = errors.New("--sample and --sample-n are mutually exclusive")
+	errInvalidSample    = errors.New("must be a percentage (5%) or a fraction (0.05) of the collection")
+	errListConflict     = errors.New("--from-file and --from-stdin are mutually exclusive")
+	errListArgument     = errors.New("expected no argument with --from-file or --from-stdin")
+	errFolderArgument   = errors.New("expected exactly one argument: folder path")
+	errIncompleteReport = errors.New("the report is incomplete: its run was interrupted")
 )
 
 func reportCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "report",
 		Usage:     "Scan a music collection and write a haustorium JSONL report",
-		ArgsUsage: "<folder>",
-		Flags: []cli.Flag{
+		ArgsUsage: "<folder> (or none, with --from-file or --from-stdin)",
+		Flags: append(append(recordFlags(), []cli.Flag{
 			&cli.BoolFlag{
-				Name:  "redact-path",
-				Usage: "Strip file paths from the report",
+				Name:  "album-records",
+				Usage: "Write an album aggregate record (loudness spread, format consistency) after each directory",
+			},
+			&cli.BoolFlag{
+				Name: "resume",
+				Usage: "Keep albums fully and successfully recorded in the previous " + outputFile +
+					", process the rest",
+			},
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Append the files modified after this time (RFC3339, or @file: its mtime) to the report",
+			},
+			&cli.StringFlag{
+				Name:  "since-report",
+				Usage: "Append the files modified after the newest one recorded in this report (JSONL) to it",
 			},
 			&cli.StringFlag{
-				Name:    "source",
-				Aliases: []string{"S"},
-				Usage:   "Override audio source type for all files: digital, vinyl, live (default: auto-detect from path)",
+				Name:  "sample",
+				Usage: "Only process a random share of the files, e.g. 5%; the digest extrapolates to the collection",
 			},
 			&cli.IntFlag{
-				Name:    "workers",
-				Aliases: []string{"j"},
-				Usage:   "Number of concurrent workers",
-				Value:   runtime.NumCPU(),
+				Name:  "sample-n",
+				Usage: "Only process this many random files; the digest extrapolates to the collection",
 			},
-		},
+			&cli.Uint64Flag{
+				Name:  "seed",
+				Usage: "Random seed for --sample and --sample-n: the same seed selects the same files",
+				Value: 1,
+			},
+			&cli.StringFlag{
+				Name:  "from-file",
+				Usage: "Process the files listed in this file, one path per line, instead of scanning a folder",
+			},
+			&cli.BoolFlag{
+				Name:  "from-stdin",
+				Usage: "Process the files listed on standard input, one path per line, instead of scanning a folder",
+			},
+		}...), binaries.Flags()...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
-			if cmd.NArg() != 1 {
-				return errors.New("expected exactly one argument: folder path")
+			fileList := cmd.String("from-file")
+			if cmd.Bool("from-stdin") {
+				if fileList != "" {
+					return errListConflict
+				}
+
+				fileList = stdinList
+			}
+
+			switch {
+			case fileList != "" && cmd.NArg() != 0:
+				return errListArgument
+			case fileList == "" && cmd.NArg() != 1:
+				return errFolderArgument
+			default:
 			}
 
-			folder := cmd.Args().First()
-			redact := cmd.Bool("redact-path")
-			sourceOverride := cmd.String("source")
-			workers := cmd.Int("workers")
+			opts, err := recordOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			opts.fileList = fileList
+			opts.albumRecords = cmd.Bool("album-records")
+			opts.resume = cmd.Bool("resume")
+
+			opts.since, err = resolveSince(cmd.String("since"), cmd.String("since-report"))
+			if err != nil {
+				return err
+			}
 
-			workers = max(workers, 1)
+			opts.sample, err = parseSample(cmd.String("sample"), cmd.Int("sample-n"), cmd.Uint64("seed"))
+			if err != nil {
+				return err
+			}
 
-			return runReport(ctx, folder, redact, sourceOverride, workers)
+			return runReport(ctx, cmd.Args().First(), opts)
 		},
 	}
 }
 
-func runReport(ctx context.Context, folder string, redact bool, sourceOverride string, workers int) error {
-	info, err := os.Stat(folder)
-	if err != nil || !info.IsDir() {
-		return fmt.Errorf("%q: %w", folder, errNotDirectory)
+// recordFlags returns the flags shaping each file's record, shared by report and watch.
+func recordFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "redact-path",
+			Usage: "Strip file paths from the report",
+		},
+		&cli.StringFlag{
+			Name:    "source",
+			Aliases: []string{"S"},
+			Usage:   "Override audio source type for all files: digital, vinyl, live (default: auto-detect from path)",
+		},
+		&cli.IntFlag{
+			Name:    "workers",
+			Aliases: []string{"j"},
+			Usage:   "Number of concurrent workers",
+			Value:   runtime.NumCPU(),
+		},
+		&cli.BoolFlag{
+			Name:  "bit-depth-from-tags",
+			Usage: "Take the original bit depth from the file's tags (bits_per_sample, bit_depth...) when present",
+		},
+		&cli.FloatFlag{
+			Name:  "min-confidence",
+			Usage: "Report detections below this confidence (0-1) as not detected",
+		},
+		&cli.FloatFlag{
+			Name:  "loudness-target",
+			Usage: "Grade loudness against this integrated target (LUFS) instead of reporting it as informational",
+		},
+		&cli.FloatFlag{
+			Name:  "true-peak-ceiling",
+			Usage: "True peak ceiling (dBTP) for --loudness-target",
+			Value: -1,
+		},
+		&cli.FloatFlag{
+			Name:  "short-term-allowance",
+			Usage: "How far (LU) the loudest short-term window may exceed --loudness-target",
+			Value: 5,
+		},
+		&cli.FloatFlag{
+			Name:  "trim-edges",
+			Usage: "Skip the first and last seconds in every check but loudness, truncation and silence-padding",
+		},
+		&cli.DurationFlag{
+			Name:  "min-track-duration",
+			Usage: "Flag tracks shorter than this (e.g. 10s) as too short: fragments, incomplete rips",
+		},
+		&cli.StringFlag{
+			Name:  "expect",
+			Usage: "Flag tracks whose probed format differs from <bits>/<rate> (e.g. 16/44100, 24/*)",
+		},
+		&cli.BoolFlag{
+			Name: "probe-sidecar",
+			Usage: "Use <file>" + report.ProbeSidecarSuffix +
+				" next to each audio file, when present, instead of running ffprobe",
+		},
+		&cli.StringFlag{
+			Name:  "raw",
+			Usage: "Which raw analyzer blocks to keep in the report: none, detected, all (digest --rebands needs all)",
+			Value: "all",
+		},
+		&cli.StringFlag{
+			Name:  "include",
+			Usage: "Only keep these raw analyzer blocks in the report (comma-separated: loudness,true_peak...)",
+		},
+		&cli.BoolFlag{
+			Name:  "fingerprint",
+			Usage: "Add a content hash and an acoustic fingerprint to each record, for hau-report dupes",
+		},
+	}
+}
+
+// recordOptions reads the flags of recordFlags.
+func recordOptions(cmd *cli.Command) (*reportOptions, error) {
+	opts := &reportOptions{
+		redact:  cmd.Bool("redact-path"),
+		workers: max(cmd.Int("workers"), 1),
+		file: report.Options{
+			FFprobe:      cmd.String("ffprobe"),
+			FFmpeg:       cmd.String("ffmpeg"),
+			ProbeSidecar: cmd.Bool("probe-sidecar"),
+			TagBitDepth:  cmd.Bool("bit-depth-from-tags"),
+			MinDuration:  cmd.Duration("min-track-duration"),
+			Fingerprint:  cmd.Bool("fingerprint"),
+		},
 	}
 
-	// Collect audio files.
-	files, err := collectAudioFiles(folder)
+	opts.file.Analysis = fileAnalysis(cmd, opts.workers)
+
+	var err error
+
+	opts.file.Expect, err = report.ParseExpect(cmd.String("expect"))
 	if err != nil {
-		return fmt.Errorf("scanning folder: %w", err)
+		return nil, fmt.Errorf("--expect %w", err)
+	}
+
+	opts.raw, err = output.ParseRaw(cmd.String("raw"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts.include, err = output.ParseInclude(cmd.String("include"))
+	if err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// fileAnalysis returns the analysis options of each file, as the flags of recordFlags ask: every check, with the
+// defaults of the file's source (--source, or detected from its path).
+func fileAnalysis(cmd *cli.Command, workers int) func(filePath string) (haustorium.Options, error) {
+	sourceOverride := cmd.String("source")
+	minConfidence := cmd.Float("min-confidence")
+	loudnessTarget := cmd.Float("loudness-target")
+	peakCeiling := cmd.Float("true-peak-ceiling")
+	shortTermLU := cmd.Float("short-term-allowance")
+	trimEdges := cmd.Float("trim-edges")
+
+	return func(filePath string) (haustorium.Options, error) {
+		source, err := detectSource(filePath, sourceOverride)
+		if err != nil {
+			return haustorium.Options{}, fmt.Errorf("invalid source: %w", err)
+		}
+
+		analyzeOpts := haustorium.OptionsForSource(source)
+		analyzeOpts.Checks = haustorium.ChecksAll
+		analyzeOpts.MinConfidence = minConfidence
+		analyzeOpts.LoudnessTargetLUFS = loudnessTarget
+		analyzeOpts.TruePeakCeilingDb = peakCeiling
+		analyzeOpts.ShortTermAllowanceLU = shortTermLU
+		analyzeOpts.TrimEdgesSec = trimEdges
+
+		// The workers already keep the CPUs busy, one file each.
+		if workers > 1 {
+			analyzeOpts.SpectralWorkers = 1
+		}
+
+		return analyzeOpts, nil
+	}
+}
+
+// reportOptions carries the report flags down to the per-file processing.
+type reportOptions struct {
+	fileList     string // files to process, one per line ("-": stdin); empty: scan the folder
+	redact       bool
+	workers      int
+	since        time.Time            // zero: process everything
+	sample       *report.SampleRecord // nil: process every file
+	raw          output.Raw
+	include      map[string]bool // raw blocks to keep; nil: all
+	albumRecords bool            // write an aggregate record after each album
+	resume       bool            // keep complete albums from the previous report
+	file         report.Options  // the record of each file
+}
+
+func runReport(ctx context.Context, folder string, opts *reportOptions) error {
+	// Files modified from now on are left to the next incremental run.
+	header := report.Record{Scan: &report.ScanRecord{Started: time.Now(), Since: opts.since}, Sample: opts.sample}
+
+	files, err := reportFiles(folder, opts)
+	if err != nil {
+		return err
+	}
+
+	// Incremental scans with nothing new are not an error.
+	if len(files) == 0 && !opts.since.IsZero() {
+		fmt.Fprintf(os.Stderr, "No files modified since %s\n", opts.since.Format(time.RFC3339))
+
+		return nil
 	}
 
 	if len(files) == 0 {
-		return fmt.Errorf("%q: %w", folder, errNoAudioFiles)
+		origin := cmp.Or(folder, opts.fileList)
+		if opts.fileList == stdinList {
+			origin = "stdin"
+		}
+
+		return fmt.Errorf("%q: %w", origin, errNoAudioFiles)
 	}
 
-	fmt.Fprintf(os.Stderr, "Found %d files to analyze (%d workers)\n", len(files), workers)
+	if opts.sample != nil {
+		files = selectSample(files, opts.sample)
 
-	// Process files concurrently.
-	startTime := time.Now()
-	results := make([]Record, len(files))
+		fmt.Fprintf(os.Stderr, "Sampling %d of %d files (seed %d)\n",
+			opts.sample.Selected, opts.sample.Population, opts.sample.Seed)
+	}
 
-	var progress atomic.Int64
+	albums := groupByAlbum(files)
 
-	sem := make(chan struct{}, workers)
+	resume := &resumeState{}
+	if opts.resume {
+		resume, err = loadResume(outputFile, albums)
+		if err != nil {
+			return err
+		}
 
-	var waitGroup sync.WaitGroup
+		// Resuming an interrupted run finishes its scan, started back then.
+		if previous := readHeader(outputFile); previous != nil && previous.Scan != nil && !previous.Scan.Complete {
+			header.Scan.Started = previous.Scan.Started
+		}
+	}
 
-	for idx, filePath := range files {
-		waitGroup.Add(1)
+	// An incremental run merges its records into the report: the earlier ones it does not replace come first.
+	var kept [][]byte
+	if !opts.since.IsZero() {
+		kept, err = previousRecords(outputFile, albums)
+		if err != nil {
+			return err
+		}
+	}
 
-		go func(idx int, filePath string) {
-			defer waitGroup.Done()
+	pending := 0
 
-			sem <- struct{}{}
+	for _, alb := range albums {
+		if !resume.done[alb.dir] {
+			pending += len(alb.files)
+		}
+	}
 
-			defer func() { <-sem }()
+	fmt.Fprintf(os.Stderr, "Found %d files in %d albums to analyze (%d workers, %d already reported)\n",
+		pending, len(albums), opts.workers, len(files)-pending)
 
-			results[idx] = processFile(ctx, filePath, sourceOverride)
+	// Process albums in order, files concurrently within and across albums.
+	startTime := time.Now()
+	runs := make([]albumRun, len(albums))
 
-			done := progress.Add(1)
-			fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", done, len(files), filePath)
-		}(idx, filePath)
+	for idx, alb := range albums {
+		runs[idx].results = make([]report.Record, len(alb.files))
+		if !resume.done[alb.dir] {
+			runs[idx].waitGroup.Add(len(alb.files))
+		}
 	}
 
-	waitGroup.Wait()
+	go dispatchAlbums(ctx, albums, runs, resume, opts, pending)
 
-	// Write results in file order.
+	// Write each album as soon as it and all albums before it are done, so an interrupted run keeps them, after
+	// the header (complete once the run is) and the earlier records kept. A resumed run replays its albums itself.
 	out, err := os.Create(outputFile)
 	if err != nil {
 		return fmt.Errorf("creating output file: %w", err)
@@ -137,33 +378,78 @@ func runReport(ctx context.Context, folder string, redact bool, sourceOverride s
 	enc := json.NewEncoder(out)
 	failed := 0
 
+	if err := enc.Encode(header); err != nil {
+		slog.Error("writing report header", "error", err)
+	}
+
+	for _, line := range kept {
+		_, _ = out.Write(append(line, '\n'))
+	}
+
 	var totalProbe, totalDecode, totalAnalyze time.Duration
 
-	for idx := range results {
-		record := &results[idx]
+	var totalBytes int64
+
+	var totalAudio float64
 
-		if record.Error != "" {
-			failed++
+	for albumIdx, alb := range albums {
+		if resume.done[alb.dir] {
+			for _, line := range resume.lines[alb.dir] {
+				_, _ = out.Write(append(line, '\n'))
+			}
+
+			continue
 		}
 
-		if record.Timing != nil {
-			totalProbe += millisToDuration(record.Timing.ProbeMs)
-			totalDecode += millisToDuration(record.Timing.DecodeMs)
-			totalAnalyze += millisToDuration(record.Timing.AnalyzeMs)
+		runs[albumIdx].waitGroup.Wait()
+
+		results := runs[albumIdx].results
+
+		var albumRecord *report.AlbumRecord
+		if opts.albumRecords {
+			albumRecord = summarizeAlbum(alb.dir, results)
 		}
 
-		if redact {
-			record.File = ""
-			record.Probe = redactProbe(record.Probe)
+		for idx := range results {
+			record := &results[idx]
+
+			if record.Error != "" {
+				failed++
+			}
+
+			if record.Timing != nil {
+				totalProbe += millisToDuration(record.Timing.ProbeMs)
+				totalDecode += millisToDuration(record.Timing.DecodeMs)
+				totalAnalyze += millisToDuration(record.Timing.AnalyzeMs)
+				totalBytes += int64(record.Timing.PCMBytes)
+				totalAudio += record.Timing.AudioSec
+			}
+
+			finishRecord(record, opts)
+
+			if err := enc.Encode(record); err != nil {
+				slog.Error("writing record", "file", alb.files[idx], "error", err)
+			}
 		}
 
-		if err := enc.Encode(record); err != nil {
-			slog.Error("writing record", "file", files[idx], "error", err)
+		if albumRecord != nil {
+			if opts.redact {
+				albumRecord.Dir = ""
+			}
+
+			if err := enc.Encode(report.Record{Album: albumRecord}); err != nil {
+				slog.Error("writing album record", "album", alb.dir, "error", err)
+			}
 		}
 	}
 
 	out.Close()
 
+	header.Scan.Complete = true
+	if err := rewriteHeader(outputFile, header); err != nil {
+		slog.Error("completing report header", "error", err)
+	}
+
 	// Compress.
 	if err := compressFile(outputFile); err != nil {
 		slog.Error("compressing report", "error", err)
@@ -173,11 +459,11 @@ func runReport(ctx context.Context, folder string, redact bool, sourceOverride s
 	minutes := int(elapsed.Minutes())
 	seconds := int(elapsed.Seconds()) % 60
 
-	fmt.Fprintf(os.Stderr, "\nDone: %d files in %dm %ds (%d failed)\n", len(files), minutes, seconds, failed)
+	fmt.Fprintf(os.Stderr, "\nDone: %d files in %dm %ds (%d failed)\n", pending, minutes, seconds, failed)
 	fmt.Fprintf(os.Stderr, "Report written to %s (and %s.gz)\n", outputFile, outputFile)
 
 	// Timing breakdown.
-	analyzed := len(files) - failed
+	analyzed := pending - failed
 
 	fmt.Fprintf(os.Stderr, "\n--- Timing ---\n")
 	fmt.Fprintf(os.Stderr, "  Wall clock:  %s\n", elapsed.Truncate(time.Millisecond))
@@ -194,108 +480,77 @@ func runReport(ctx context.Context, folder string, redact bool, sourceOverride s
 		)
 	}
 
+	// Throughput, against the wall clock: what the whole run sustained, workers and decoding included.
+	if totalBytes > 0 && elapsed > 0 {
+		fmt.Fprintf(os.Stderr, "  PCM:         %.1f MB (%.1f MB/s)\n",
+			float64(totalBytes)/bytesPerMB, float64(totalBytes)/bytesPerMB/elapsed.Seconds())
+		fmt.Fprintf(os.Stderr, "  realtime:    %.1fx (%s of audio)\n",
+			totalAudio/elapsed.Seconds(), time.Duration(totalAudio*float64(time.Second)).Truncate(time.Second))
+	}
+
 	// Print digest summary.
 	fmt.Fprintln(os.Stderr)
 
-	return runDigest(outputFile, "")
+	return runDigest(outputFile, "", "")
 }
 
-func processFile(ctx context.Context, filePath, sourceOverride string) Record {
-	fileStart := time.Now()
-	timing := &RecordTiming{}
-
-	// Determine source type.
-	source, err := detectSource(filePath, sourceOverride)
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("invalid source: %v", err)}
-	}
-
-	// Probe.
-	probeStart := time.Now()
-
-	probeResult, err := ffprobe.Probe(ctx, filePath)
-
-	timing.ProbeMs = durationMs(time.Since(probeStart))
-
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("probe failed: %v", err), Timing: timing}
-	}
-
-	// Find first audio stream.
-	stream, err := findAudioStream(probeResult)
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("no audio stream: %v", err), Timing: timing}
-	}
-
-	// Build PCM format.
-	pcmFormat, err := buildPCMFormat(stream)
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("format error: %v", err), Timing: timing}
-	}
-
-	// Extract PCM.
-	decodeStart := time.Now()
-
-	file, err := os.Open(filePath) //nolint:gosec // CLI tool opens user-specified audio files
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("open failed: %v", err), Timing: timing}
-	}
-	defer file.Close()
-
-	var pcmBuf bytes.Buffer
-
-	extractFormat := &types.PCMFormat{BitDepth: types.Depth32}
-
-	if err = ffmpeg.ExtractStream(ctx, file, &pcmBuf, 0, extractFormat); err != nil {
-		timing.DecodeMs = durationMs(time.Since(decodeStart))
+// albumRun collects the records of one album; waitGroup is done when all of them are in.
+type albumRun struct {
+	results   []report.Record
+	waitGroup sync.WaitGroup
+}
 
-		return Record{File: filePath, Error: fmt.Sprintf("extraction failed: %v", err), Timing: timing}
-	}
+// dispatchAlbums starts files in album order, bounded by the worker count, skipping albums already reported.
+func dispatchAlbums(
+	ctx context.Context,
+	albums []album,
+	runs []albumRun,
+	resume *resumeState,
+	opts *reportOptions,
+	pending int,
+) {
+	var progress atomic.Int64
 
-	timing.DecodeMs = durationMs(time.Since(decodeStart))
+	sem := make(chan struct{}, opts.workers)
 
-	// Build reader factory.
-	pcmData := pcmBuf.Bytes()
-	factory := func() (io.Reader, error) {
-		return bytes.NewReader(pcmData), nil
-	}
+	for albumIdx, alb := range albums {
+		if resume.done[alb.dir] {
+			continue
+		}
 
-	// Run analysis.
-	analyzeStart := time.Now()
+		run := &runs[albumIdx]
 
-	opts := haustorium.OptionsForSource(source)
-	opts.Checks = haustorium.ChecksAll
+		for idx, filePath := range alb.files {
+			sem <- struct{}{}
 
-	result, err := haustorium.Analyze(factory, pcmFormat, opts)
+			go func() {
+				defer run.waitGroup.Done()
+				defer func() { <-sem }()
 
-	timing.AnalyzeMs = durationMs(time.Since(analyzeStart))
-	timing.TotalMs = durationMs(time.Since(fileStart))
+				run.results[idx] = report.Process(ctx, filePath, nil, &opts.file)
 
-	if err != nil {
-		return Record{File: filePath, Error: fmt.Sprintf("analysis failed: %v", err), Timing: timing}
+				done := progress.Add(1)
+				fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", done, pending, filePath)
+			}()
+		}
 	}
+}
 
-	// Build record.
-	record := Record{
-		File:     filePath,
-		Analysis: output.ResultToMap(result),
-		Timing:   timing,
+// finishRecord prunes a record's raw blocks and redacts it, as the options ask, before it is written.
+func finishRecord(record *report.Record, opts *reportOptions) {
+	if record.Analysis != nil {
+		output.PruneRaw(record.Analysis, opts.raw)
+		output.IncludeRaw(record.Analysis, opts.include)
 	}
 
-	// Serialize probe data (strips tags/disposition since Go structs don't include them).
-	probeJSON, err := json.Marshal(probeResult)
-	if err == nil {
-		record.Probe = probeJSON
-	} else {
-		record.ProbeError = "probe serialization failed"
+	if opts.redact {
+		record.File = ""
+		record.Probe = redactProbe(record.Probe)
 	}
-
-	return record
 }
 
-func durationMs(d time.Duration) float64 {
-	return float64(d.Microseconds()) / 1000.0
-}
+// bytesPerMB is the megabyte of the throughput figures, binary like the line size limits.
+const bytesPerMB = 1024 * 1024
 
 func millisToDuration(ms float64) time.Duration {
 	return time.Duration(ms * float64(time.Millisecond))
@@ -316,66 +571,40 @@ func detectSource(filePath, sourceOverride string) (haustorium.Source, error) {
 	return haustorium.SourceDigital, nil
 }
 
-func findAudioStream(result *ffprobe.Result) (*ffprobe.Stream, error) {
-	for i := range result.Streams {
-		if result.Streams[i].CodecType == "audio" {
-			return &result.Streams[i], nil
+// reportFiles returns the audio files to process: the listed ones, or those found under the folder.
+func reportFiles(folder string, opts *reportOptions) ([]string, error) {
+	if opts.fileList != "" {
+		files, err := listAudioFiles(opts.fileList, opts.since)
+		if err != nil {
+			return nil, fmt.Errorf("reading file list: %w", err)
 		}
-	}
 
-	return nil, errNoAudioStream
-}
-
-func buildPCMFormat(stream *ffprobe.Stream) (types.PCMFormat, error) {
-	sampleRate, err := strconv.Atoi(stream.SampleRate)
-	if err != nil || sampleRate <= 0 {
-		return types.PCMFormat{}, fmt.Errorf("%q: %w", stream.SampleRate, errInvalidSampleRate)
+		return files, nil
 	}
 
-	if stream.Channels <= 0 {
-		return types.PCMFormat{}, fmt.Errorf("%d: %w", stream.Channels, errInvalidChannels)
-	}
-
-	return types.PCMFormat{
-		SampleRate:       sampleRate,
-		BitDepth:         types.Depth32,
-		Channels:         uint(stream.Channels), //nolint:gosec // validated positive value
-		ExpectedBitDepth: resolveExpectedBitDepth(stream),
-	}, nil
-}
-
-func resolveExpectedBitDepth(stream *ffprobe.Stream) types.BitDepth {
-	if stream.BitsPerRawSample != "" {
-		if bits, err := strconv.Atoi(stream.BitsPerRawSample); err == nil {
-			if bd, err := toBitDepth(bits); err == nil {
-				return bd
-			}
-		}
+	info, err := os.Stat(folder)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%q: %w", folder, errNotDirectory)
 	}
 
-	if stream.BitsPerSample > 0 {
-		if bd, err := toBitDepth(stream.BitsPerSample); err == nil {
-			return bd
-		}
+	files, err := collectAudioFiles(folder, opts.since)
+	if err != nil {
+		return nil, fmt.Errorf("scanning folder: %w", err)
 	}
 
-	return types.Depth32
+	return files, nil
 }
 
-func toBitDepth(bits int) (types.BitDepth, error) {
-	switch bits {
-	case 16:
-		return types.Depth16, nil
-	case 24:
-		return types.Depth24, nil
-	case 32:
-		return types.Depth32, nil
-	default:
-		return 0, fmt.Errorf("%d: %w", bits, errInvalidBitDepth)
-	}
+// isAudioFile tells whether the path has the extension of the files reported on: .flac or .m4a.
+func isAudioFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	return ext == ".flac" || ext == ".m4a"
 }
 
-func collectAudioFiles(root string) ([]string, error) {
+// collectAudioFiles returns the sorted list of audio files under root.
+// If since is non-zero, only files modified strictly after it are returned.
+func collectAudioFiles(root string, since time.Time) ([]string, error) {
 	var files []string
 
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
@@ -387,11 +616,23 @@ func collectAudioFiles(root string) ([]string, error) {
 			return nil
 		}
 
-		ext := strings.ToLower(filepath.Ext(path))
-		if ext == ".flac" || ext == ".m4a" {
-			files = append(files, path)
+		if !isAudioFile(path) {
+			return nil
+		}
+
+		if !since.IsZero() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			if !info.ModTime().After(since) {
+				return nil
+			}
 		}
 
+		files = append(files, path)
+
 		return nil
 	})
 	if err != nil {
@@ -403,6 +644,28 @@ func collectAudioFiles(root string) ([]string, error) {
 	return files, nil
 }
 
+// rewriteHeader replaces the first line of a report with header, through a temporary file.
+func rewriteHeader(path string, header report.Record) error {
+	data, err := os.ReadFile(path) //nolint:gosec // reading our own output file
+	if err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	_, rest, _ := bytes.Cut(data, []byte("\n"))
+
+	//nolint:gosec // a report
+	if err := os.WriteFile(path+".tmp", append(append(line, '\n'), rest...), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
 func compressFile(path string) error {
 	data, err := os.ReadFile(path) //nolint:gosec // reading our own output file
 	if err != nil {
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+// parseSample turns the --sample / --sample-n flags into a sampling plan; nil means no sampling.
+func parseSample(fraction string, count int, seed uint64) (*report.SampleRecord, error) {
+	switch {
+	case fraction != "" && count > 0:
+		return nil, errSampleConflict
+	case count > 0:
+		return &report.SampleRecord{Count: count, Seed: seed}, nil
+	case fraction == "":
+		return nil, nil //nolint:nilnil // no sampling
+	}
+
+	raw, percent := strings.CutSuffix(strings.TrimSpace(fraction), "%")
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("--sample %q: %w", fraction, errInvalidSample)
+	}
+
+	if percent {
+		value /= 100
+	}
+
+	if value <= 0 || value > 1 {
+		return nil, fmt.Errorf("--sample %q: %w", fraction, errInvalidSample)
+	}
+
+	return &report.SampleRecord{Fraction: value, Seed: seed}, nil
+}
+
+// selectSample picks the sampled files, reproducibly for a given seed, and records the population and selection
+// sizes on the plan. The selection is returned sorted, like collectAudioFiles.
+func selectSample(files []string, plan *report.SampleRecord) []string {
+	size := plan.Count
+	if plan.Fraction > 0 {
+		size = max(int(math.Round(float64(len(files))*plan.Fraction)), 1)
+	}
+
+	size = min(size, len(files))
+
+	shuffled := slices.Clone(files)
+	rng := rand.New(rand.NewPCG(plan.Seed, plan.Seed)) //nolint:gosec // reproducible sampling, not security
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	selected := shuffled[:size]
+	slices.Sort(selected)
+
+	plan.Population = len(files)
+	plan.Selected = size
+
+	return selected
+}
+
+// readSampleRecord returns the sampling parameters of a report, written on its header; nil for a full scan.
+func readSampleRecord(path string) *report.SampleRecord {
+	header := readHeader(path)
+	if header == nil {
+		return nil
+	}
+
+	return header.Sample
+}
+
+// printExtrapolation scales the sample's per-check counts to the whole collection, with a 95% margin of error.
+func printExtrapolation(sample *report.SampleRecord, analyzed, clean int, breakdowns []*checkBreakdown) {
+	if analyzed == 0 {
+		return
+	}
+
+	estimate := func(count int) string {
+		share := float64(count) / float64(analyzed)
+		margin := 1.96 * math.Sqrt(share*(1-share)/float64(analyzed))
+
+		return fmt.Sprintf("~%.0f ± %.0f tracks (%.1f%%)",
+			share*float64(sample.Population), margin*float64(sample.Population), share*100)
+	}
+
+	fmt.Println()
+	fmt.Printf("--- Extrapolated To %d Tracks (random sample of %d, seed %d) ---\n",
+		sample.Population, sample.Selected, sample.Seed)
+	fmt.Printf("  clean: %s\n", estimate(clean))
+
+	for _, bd := range breakdowns {
+		fmt.Printf("  %s: %s\n", bd.Check, estimate(bd.Total))
+	}
+}
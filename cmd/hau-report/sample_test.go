@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSelectSample(t *testing.T) {
+	t.Parallel()
+
+	files := make([]string, 0, 40)
+	for i := range 40 {
+		files = append(files, fmt.Sprintf("/music/%02d.flac", i))
+	}
+
+	for _, tc := range []struct {
+		fraction string
+		count    int
+		selected int
+	}{
+		{"10%", 0, 4},
+		{"0.25", 0, 10},
+		{"0.1%", 0, 1}, // rounded to nothing, at least one file
+		{"", 7, 7},
+		{"", 100, 40}, // capped at the population
+	} {
+		plan, err := parseSample(tc.fraction, tc.count, 7)
+		if err != nil {
+			t.Fatalf("--sample %q --sample-n %d: %v", tc.fraction, tc.count, err)
+		}
+
+		selected := selectSample(files, plan)
+		if len(selected) != tc.selected || plan.Selected != tc.selected || plan.Population != len(files) {
+			t.Errorf("--sample %q --sample-n %d: expected %d of %d files, got %d (recorded %d of %d)",
+				tc.fraction, tc.count, tc.selected, len(files), len(selected), plan.Selected, plan.Population)
+		}
+
+		if !slices.IsSorted(selected) {
+			t.Errorf("--sample %q --sample-n %d: expected a sorted selection, got %v", tc.fraction, tc.count, selected)
+		}
+	}
+
+	// The seed alone decides the selection.
+	pick := func(seed uint64) []string {
+		plan, err := parseSample("", 5, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return selectSample(files, plan)
+	}
+
+	if first, again := pick(7), pick(7); !slices.Equal(first, again) {
+		t.Fatalf("expected the same selection for the same seed, got %v and %v", first, again)
+	}
+
+	if first, other := pick(7), pick(8); slices.Equal(first, other) {
+		t.Fatalf("expected another selection for another seed, got %v twice", first)
+	}
+}
+
+func TestParseSampleInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, fraction := range []string{"0", "0%", "150%", "1.5", "some"} {
+		if _, err := parseSample(fraction, 0, 1); err == nil {
+			t.Errorf("--sample %q: expected an error", fraction)
+		}
+	}
+}
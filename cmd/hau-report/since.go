@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+// resolveSince turns the --since / --since-report flags into a cutoff time.
+// A zero time means no filtering.
+func resolveSince(since, sinceReport string) (time.Time, error) {
+	switch {
+	case since != "" && sinceReport != "":
+		return time.Time{}, errSinceConflict
+	case since != "":
+		return parseSince(since)
+	case sinceReport != "":
+		return sinceFromReport(sinceReport)
+	default:
+		return time.Time{}, nil
+	}
+}
+
+// parseSince accepts either an RFC3339 timestamp, or @path to use the modification time of path.
+func parseSince(value string) (time.Time, error) {
+	if path, ok := strings.CutPrefix(value, "@"); ok {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("--since %q: %w", value, err)
+		}
+
+		return info.ModTime(), nil
+	}
+
+	cutoff, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since %q: %w", value, errInvalidSince)
+	}
+
+	return cutoff, nil
+}
+
+// sinceFromReport derives a cutoff from an existing report: the time its scan started, from its header, so that
+// the files modified during that scan are processed again, whatever happened to the files it recorded since.
+// Reports without a header (from haustorium process --emit-jsonl, or earlier versions) fall back to their own
+// mtime. An interrupted report is refused: the files it lacks are older than its scan.
+func sinceFromReport(reportPath string) (time.Time, error) {
+	info, err := os.Stat(reportPath)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since-report %q: %w", reportPath, err)
+	}
+
+	header := readHeader(reportPath)
+	if header == nil || header.Scan == nil {
+		return info.ModTime(), nil
+	}
+
+	if !header.Scan.Complete {
+		resume := "--resume"
+		if !header.Scan.Since.IsZero() {
+			resume += " --since " + header.Scan.Since.Format(time.RFC3339)
+		}
+
+		return time.Time{}, fmt.Errorf("--since-report %q: %w: finish it with %s", reportPath, errIncompleteReport,
+			resume)
+	}
+
+	return header.Scan.Started, nil
+}
+
+// readHeader returns the first line of a report, when it is a header; nil otherwise.
+func readHeader(path string) *report.Record {
+	file, err := openReport(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	const maxLineSize = 1024 * 1024 // 1MB
+	scanner.Buffer(make([]byte, 0, maxLineSize), maxLineSize)
+
+	if !scanner.Scan() {
+		return nil
+	}
+
+	var rec report.Record
+	if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || (rec.Scan == nil && rec.Sample == nil) {
+		return nil
+	}
+
+	return &rec
+}
+
+// previousRecords returns the lines of an earlier report an incremental run keeps, in report order: all but the
+// header, the records of the files the run processes again (their new records replace them), and the album
+// records of their albums. A missing report keeps nothing.
+func previousRecords(path string, albums []album) ([][]byte, error) {
+	file, err := os.Open(path) //nolint:gosec // CLI tool reads its own previous report
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("opening previous report: %w", err)
+	}
+	defer file.Close()
+
+	current := map[string]bool{}
+
+	for _, alb := range albums {
+		current[alb.dir] = true
+		for _, filePath := range alb.files {
+			current[filePath] = true
+		}
+	}
+
+	var kept [][]byte
+
+	scanner := bufio.NewScanner(file)
+
+	const maxLineSize = 1024 * 1024 // 1MB
+	scanner.Buffer(make([]byte, 0, maxLineSize), maxLineSize)
+
+	for scanner.Scan() {
+		var rec struct {
+			File   string              `json:"file"`
+			Album  *report.AlbumRecord `json:"album"`
+			Scan   *report.ScanRecord  `json:"scan"`
+			Sample json.RawMessage     `json:"sample"`
+		}
+
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.Scan != nil || rec.Sample != nil {
+			continue
+		}
+
+		if (rec.Album != nil && current[rec.Album.Dir]) || (rec.File != "" && current[rec.File]) {
+			continue
+		}
+
+		kept = append(kept, slices.Clone(scanner.Bytes()))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading previous report: %w", err)
+	}
+
+	return kept, nil
+}
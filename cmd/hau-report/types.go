@@ -3,29 +3,23 @@ package main
 
 import "encoding/json"
 
-// Record is a single line in the JSONL report file.
-type Record struct {
-	File       string          `json:"file,omitempty"`
-	Analysis   map[string]any  `json:"analysis,omitempty"`
-	Probe      json.RawMessage `json:"probe,omitempty"`
-	ProbeError string          `json:"probe_error,omitempty"`
-	Error      string          `json:"error,omitempty"`
-	Timing     *RecordTiming   `json:"timing,omitempty"`
-}
-
-// RecordTiming captures per-file processing durations in milliseconds.
-type RecordTiming struct {
-	ProbeMs   float64 `json:"probe_ms"`
-	DecodeMs  float64 `json:"decode_ms"`
-	AnalyzeMs float64 `json:"analyze_ms"`
-	TotalMs   float64 `json:"total_ms"`
-}
-
 // digestRecord holds the typed fields needed by the digest command.
 type digestRecord struct {
-	File     string          `json:"file,omitempty"`
-	Analysis *digestAnalysis `json:"analysis,omitempty"`
-	Error    string          `json:"error,omitempty"`
+	File             string          `json:"file,omitempty"`
+	Analysis         *digestAnalysis `json:"analysis,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	ErrorKind        string          `json:"error_kind,omitempty"`
+	FormatUnexpected bool            `json:"format_unexpected,omitempty"`
+	FormatDetail     string          `json:"format_detail,omitempty"`
+	TooShort         bool            `json:"too_short,omitempty"`
+	DurationSec      float64         `json:"duration_sec,omitempty"`
+	LayoutSuspect    bool            `json:"channel_layout_suspect,omitempty"`
+	LayoutDetail     string          `json:"channel_layout_detail,omitempty"`
+	GaplessOK        *bool           `json:"gapless_metadata_ok,omitempty"`
+	GaplessDetail    string          `json:"gapless_detail,omitempty"`
+	Album            json.RawMessage `json:"album,omitempty"`
+	Scan             json.RawMessage `json:"scan,omitempty"`
+	Sample           json.RawMessage `json:"sample,omitempty"`
 }
 
 type digestAnalysis struct {
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+const (
+	watchInterval = 2 * time.Second
+	watchSettle   = 5 * time.Second
+)
+
+var errWatchRedact = errors.New(
+	"--redact-path cannot be used with watch: the records would have no paths to skip the reported files by",
+)
+
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "watch",
+		Usage:     "Watch a drop folder, and append a record to the report for each audio file landing in it",
+		ArgsUsage: "<folder>",
+		Flags: append(append(recordFlags(), []cli.Flag{
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "How often to check landing files (or scan the folder, without filesystem notifications)",
+				Value: watchInterval,
+			},
+			&cli.DurationFlag{
+				Name:  "settle",
+				Usage: "Analyze a file once its size and modification time held this long (a copy is over)",
+				Value: watchSettle,
+			},
+		}...), binaries.Flags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 1 {
+				return errFolderArgument
+			}
+
+			opts, err := recordOptions(cmd)
+			if err != nil {
+				return err
+			}
+
+			if opts.redact {
+				return errWatchRedact
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return runWatch(ctx, cmd.Args().First(), opts, max(cmd.Duration("interval"), time.Millisecond),
+				cmd.Duration("settle"))
+		},
+	}
+}
+
+// runWatch watches the folder, and appends to the report a record for each audio file not in it yet, once the
+// file settled, until interrupted. The files already in the report are skipped, so a watch picks up where the
+// previous one stopped. Files left unfinished by the interruption are not recorded.
+//
+// The folder is scanned once, for the files dropped while nothing watched it; filesystem notifications then
+// tell the files landing, and only those are checked every interval until they settle. Without notifications
+// (other systems than Linux, or when inotify fails, e.g. out of watches), the folder is scanned every interval.
+func runWatch(ctx context.Context, folder string, opts *reportOptions, interval, settle time.Duration) error {
+	info, err := os.Stat(folder)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("%q: %w", folder, errNotDirectory)
+	}
+
+	watcher := &dropWatcher{settle: settle, done: map[string]bool{}, pending: map[string]*pendingFile{}}
+
+	records, _, err := readRecordsWithRaw(outputFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	for _, rec := range records {
+		if rec.File != "" {
+			watcher.done[doneKey(rec.File)] = true
+		}
+	}
+
+	changes, err := watchFolder(ctx, folder)
+	if err != nil {
+		slog.Warn("no filesystem notifications: scanning the folder every interval", "error", err)
+	}
+
+	out, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gosec // a report
+	if err != nil {
+		return fmt.Errorf("opening output file: %w", err)
+	}
+	defer out.Close()
+
+	fmt.Fprintf(os.Stderr, "Watching %s, appending to %s (%d files already reported, %d workers)\n",
+		folder, outputFile, len(watcher.done), opts.workers)
+
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		reported  int
+	)
+
+	enc := json.NewEncoder(out)
+	sem := make(chan struct{}, opts.workers)
+	ticker := time.NewTicker(interval)
+
+	defer ticker.Stop()
+
+	scan := true
+
+	for {
+		if scan {
+			if err := watcher.scan(folder, time.Now()); err != nil {
+				slog.Error("scanning folder", "folder", folder, "error", err)
+			}
+		}
+
+		for _, filePath := range watcher.settled(time.Now()) {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+
+			if ctx.Err() != nil {
+				break
+			}
+
+			waitGroup.Go(func() {
+				defer func() { <-sem }()
+
+				record := report.Process(ctx, filePath, nil, &opts.file)
+				if ctx.Err() != nil {
+					return
+				}
+
+				finishRecord(&record, opts)
+
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if err := enc.Encode(record); err != nil {
+					slog.Error("writing record", "file", filePath, "error", err)
+
+					return
+				}
+
+				reported++
+
+				if record.Error != "" {
+					fmt.Fprintf(os.Stderr, "[%d] %s: %s\n", reported, filePath, record.Error)
+				} else {
+					fmt.Fprintf(os.Stderr, "[%d] %s\n", reported, filePath)
+				}
+			})
+		}
+
+		// Until the next interval, note what the notifications tell. Once they stop, scan instead.
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				waitGroup.Wait()
+				fmt.Fprintf(os.Stderr, "\nStopped: %d files reported to %s\n", reported, outputFile)
+
+				return nil
+			case path, ok := <-changes:
+				if !ok {
+					changes = nil
+
+					continue
+				}
+
+				watcher.observe(path, time.Now())
+			case <-ticker.C:
+				waiting = false
+			}
+		}
+
+		scan = changes == nil
+	}
+}
+
+// pendingFile is an audio file seen in the drop folder, not reported yet, with its size and modification time,
+// and since when they held.
+type pendingFile struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+}
+
+// dropWatcher tracks the audio files of a drop folder until they settle: a file is handed out once, when its size
+// and modification time did not change for the settle delay, so that a copy in progress is not analyzed partially.
+type dropWatcher struct {
+	settle  time.Duration
+	done    map[string]bool // reported, or handed out, by doneKey
+	pending map[string]*pendingFile
+}
+
+// doneKey is the key of a file in the done set: its absolute, clean path, so that the records of a watch started
+// as "drop" match the files of one started as "./drop/", or given the absolute path of the folder.
+func doneKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(path)
+}
+
+// scan looks at every audio file under the folder.
+func (w *dropWatcher) scan(folder string, now time.Time) error {
+	files, err := collectAudioFiles(folder, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		w.observe(path, now)
+	}
+
+	return nil
+}
+
+// observe notes an entry of the drop folder: an audio file to wait for, or a directory moved in, to scan.
+func (w *dropWatcher) observe(path string, now time.Time) {
+	if w.done[doneKey(path)] || w.pending[path] != nil {
+		return
+	}
+
+	info, err := os.Stat(path)
+
+	switch {
+	case err != nil:
+		// Moved away since.
+	case info.IsDir():
+		if err := w.scan(path, now); err != nil {
+			slog.Error("scanning folder", "folder", path, "error", err)
+		}
+	case isAudioFile(path) && info.Mode().IsRegular():
+		w.pending[path] = &pendingFile{size: info.Size(), modTime: info.ModTime(), since: now}
+	default:
+	}
+}
+
+// settled returns the files whose size and modification time held for the settle delay, sorted, and forgets
+// those gone before they settled (a copy moved on, or aborted).
+func (w *dropWatcher) settled(now time.Time) []string {
+	var settled []string
+
+	for path, pending := range w.pending {
+		info, err := os.Stat(path)
+		if err != nil {
+			delete(w.pending, path)
+
+			continue
+		}
+
+		if pending.size != info.Size() || !pending.modTime.Equal(info.ModTime()) {
+			*pending = pendingFile{size: info.Size(), modTime: info.ModTime(), since: now}
+
+			continue
+		}
+
+		if now.Sub(pending.since) >= w.settle {
+			delete(w.pending, path)
+			w.done[doneKey(path)] = true
+			settled = append(settled, path)
+		}
+	}
+
+	slices.Sort(settled)
+
+	return settled
+}
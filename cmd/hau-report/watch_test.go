@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestDropWatcherDoneKey checks that a reported file is skipped whatever form its path takes: relative or
+// absolute, with or without ./ and trailing separators.
+func TestDropWatcherDoneKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	if err := os.Mkdir("drop", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join("drop", "track.flac"), []byte("fLaC"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, recorded := range []string{"drop/track.flac", filepath.Join(dir, "drop", "track.flac")} {
+		for _, folder := range []string{"drop", "./drop/", filepath.Join(dir, "drop") + "/"} {
+			watcher := &dropWatcher{done: map[string]bool{doneKey(recorded): true}, pending: map[string]*pendingFile{}}
+			if err := watcher.scan(folder, time.Now()); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(watcher.pending) != 0 {
+				t.Errorf("recorded as %s, watching %s: expected the file to be skipped, got %v", recorded, folder,
+					watcher.pending)
+			}
+		}
+	}
+}
@@ -2,11 +2,13 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"strings"
 
@@ -16,20 +18,24 @@ import (
 	"github.com/farcloser/haustorium/internal/types"
 )
 
-var errInvalidArgCount = errors.New("expected exactly one argument: file path or \"-\" for stdin")
+var (
+	errInvalidArgCount   = errors.New("expected exactly one argument: file path or \"-\" for stdin")
+	errMissingSampleRate = errors.New("--sample-rate is required when the input has no haustorium-pcm header")
+)
 
 func analyzeCommand() *cli.Command {
 	return &cli.Command{
 		Name:      "analyze",
 		Usage:     "Analyze raw PCM audio for quality issues",
 		ArgsUsage: "<file | ->",
-		Flags: []cli.Flag{
+		Description: "Input starting with a haustorium-pcm header carries its own format: " +
+			"the format flags are then ignored.",
+		Flags: append([]cli.Flag{
 			// PCMFormat flags.
 			&cli.IntFlag{
-				Name:     "sample-rate",
-				Aliases:  []string{"s"},
-				Usage:    "Sample rate in Hz (e.g., 44100, 48000, 96000)",
-				Required: true,
+				Name:    "sample-rate",
+				Aliases: []string{"s"},
+				Usage:   "Sample rate in Hz (e.g., 44100, 48000, 96000), required without a haustorium-pcm header",
 			},
 			&cli.IntFlag{
 				Name:    "bit-depth",
@@ -47,12 +53,31 @@ func analyzeCommand() *cli.Command {
 				Name:  "expected-bit-depth",
 				Usage: "Expected bit depth for authenticity check (defaults to --bit-depth value)",
 			},
+			&cli.StringFlag{
+				Name: "layout",
+				Usage: "Sample layout: native, or 24-in-32 " +
+					"(24-bit samples left-justified in 32-bit, with --bit-depth 32)",
+				Value: "native",
+			},
+			&cli.StringFlag{
+				Name:  "endian",
+				Usage: "Sample byte order: little, or big",
+				Value: "little",
+			},
+			&cli.StringFlag{
+				Name:  "source-codec",
+				Usage: "Codec the PCM was decoded from (e.g. flac, mp3): lossy codecs make the authenticity checks n/a",
+			},
+			&cli.Uint64Flag{
+				Name:  "start-offset",
+				Usage: "Sample index of the input's first frame in the original file; shifts reported event positions",
+			},
 
 			// Check selection.
 			&cli.StringFlag{
 				Name:    "checks",
 				Aliases: []string{"C"},
-				Usage:   "Comma-separated checks or presets: all, defects, loudness, clipping, truncation, fake-bit-depth, fake-sample-rate, lossy-transcode, dc-offset, fake-stereo, phase-issues, inverted-phase, channel-imbalance, silence-padding, hum, noise-floor, inter-sample-peaks, dynamic-range, dropouts",
+				Usage:   "Comma-separated checks or presets: all, defects, loudness, mix, clipping, truncation, fake-bit-depth, fake-sample-rate, lossy-transcode, dc-offset, fake-stereo, phase-issues, inverted-phase, channel-imbalance, silence-padding, hum, noise-floor, inter-sample-peaks, dynamic-range, dropouts, undithered, de-essing, bass-rolloff, vinyl-cut-safety, repeats, pitch-offset, width-changes, trimmed-lead-in, spectral-tilt",
 				Value:   "all",
 			},
 
@@ -63,6 +88,68 @@ func analyzeCommand() *cli.Command {
 				Usage:   "Audio source type adjusting detection thresholds: digital, vinyl, live",
 				Value:   "digital",
 			},
+			&cli.StringFlag{
+				Name:  "profile",
+				Usage: "Workflow preset: archival, broadcast, streaming, vinyl-transfer (flags given override it)",
+			},
+			&cli.FloatFlag{
+				Name:  "min-confidence",
+				Usage: "Report detections below this confidence (0-1) as not detected",
+			},
+			&cli.FloatFlag{
+				Name:  "pitch-reference",
+				Usage: "Tuning reference (A4, in Hz) for pitch-offset",
+				Value: 440,
+			},
+			&cli.FloatFlag{
+				Name:  "trim-edges",
+				Usage: "Skip the first and last seconds in every check but loudness, truncation and silence-padding",
+			},
+			&cli.FloatFlag{
+				Name:  "quiet-windows-fraction",
+				Usage: "Share (0-1) of the quietest spectral windows the noise floor is measured on",
+				Value: 0.2,
+			},
+			&cli.FloatFlag{
+				Name:  "quiet-gate",
+				Usage: "RMS (dBFS) the quiet windows must exceed for the noise floor to be measured on them",
+				Value: -50,
+			},
+			&cli.StringFlag{
+				Name:  "genre",
+				Usage: "Genre adjusting dynamic range expectations: classical, jazz, rock, pop, electronic",
+			},
+			&cli.BoolFlag{
+				Name:  "edge-noise-floor",
+				Usage: "Also measure the noise floor of the leading and trailing silence (default with --source vinyl)",
+			},
+			&cli.BoolFlag{
+				Name:  "thorough",
+				Usage: "Run the spectral checks on every window instead of 100 spread across the track (slower)",
+			},
+			&cli.BoolFlag{
+				Name:  "bit-depth-sections",
+				Usage: "Also measure the bit depth of every second, to find zero-padded sections in a genuine file",
+			},
+			&cli.FloatFlag{
+				Name:  "loudness-target",
+				Usage: "Grade loudness against this integrated target (LUFS) instead of reporting it as informational",
+			},
+			&cli.FloatFlag{
+				Name:  "true-peak-ceiling",
+				Usage: "True peak ceiling (dBTP) for --loudness-target",
+				Value: -1,
+			},
+			&cli.FloatFlag{
+				Name:  "short-term-allowance",
+				Usage: "How far (LU) the loudest short-term window may exceed --loudness-target",
+				Value: 5,
+			},
+
+			&cli.BoolFlag{
+				Name:  "stream",
+				Usage: "Print clipping and dropouts to stderr as they are found, before the final result",
+			},
 
 			// Output format.
 			&cli.StringFlag{
@@ -71,63 +158,135 @@ func analyzeCommand() *cli.Command {
 				Usage:   "Output format: console, json, markdown",
 				Value:   "console",
 			},
-			&cli.BoolFlag{
-				Name:    "debug",
-				Aliases: []string{"D"},
-				Usage:   "Include all raw analyzer data in output",
-			},
-		},
-		Action: func(_ context.Context, cmd *cli.Command) error {
+		}, displayFlags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
 			if cmd.NArg() != 1 {
 				return fmt.Errorf("%w: got %d", errInvalidArgCount, cmd.NArg())
 			}
 
-			// Parse PCM format.
-			format, err := parsePCMFormat(cmd)
+			opts, err := analysisOptions(cmd)
 			if err != nil {
 				return err
 			}
 
-			// Parse checks.
-			checks, err := parseChecks(cmd.String("checks"))
-			if err != nil {
-				return err
-			}
+			opts.StartOffsetFrames = cmd.Uint64("start-offset")
+			opts.SourceCodec = cmd.String("source-codec")
 
-			source, err := haustorium.ParseSource(cmd.String("source"))
-			if err != nil {
-				return err
-			}
+			inputPath := cmd.Args().First()
 
-			opts := haustorium.OptionsForSource(source)
-			opts.Checks = checks
+			var result *haustorium.Result
 
-			// Build reader factory.
-			inputPath := cmd.Args().First()
+			if cmd.Bool("stream") {
+				result, err = analyzeStream(ctx, cmd, inputPath, opts)
+			} else {
+				result, err = analyzeInput(cmd, inputPath, opts)
+			}
 
-			factory, cleanup, err := readerFactory(inputPath)
 			if err != nil {
 				return err
 			}
-			defer cleanup()
 
-			// Run analysis.
-			result, err := haustorium.Analyze(factory, format, opts)
+			if result.ByteSwapSuspected {
+				slog.Warn("samples look byte-swapped: check --endian", "endian", cmd.String("endian"))
+			}
+
+			disp, err := parseDisplay(cmd)
 			if err != nil {
-				return fmt.Errorf("analysis failed: %w", err)
+				return err
 			}
 
-			return outputResult(inputPath, result, cmd.String("format"), cmd.Bool("debug"))
+			return outputResult(inputPath, result, cmd.String("format"), disp)
 		},
 	}
 }
 
+func analyzeInput(cmd *cli.Command, inputPath string, opts haustorium.Options) (*haustorium.Result, error) {
+	factory, header, cleanup, err := readerFactory(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// PCM format: from the haustorium-pcm header if any, from the flags otherwise.
+	format, err := inputFormat(cmd, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := haustorium.Analyze(factory, format, opts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// analyzeStream reads the input once, printing the findings of each block to stderr as they come.
+func analyzeStream(
+	ctx context.Context,
+	cmd *cli.Command,
+	inputPath string,
+	opts haustorium.Options,
+) (*haustorium.Result, error) {
+	reader, header, cleanup, err := streamInput(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	format, err := inputFormat(cmd, header)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := haustorium.AnalyzeStream(ctx, reader, format, opts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	for event := range events {
+		switch {
+		case event.Err != nil:
+			return nil, fmt.Errorf("analysis failed: %w", event.Err)
+		case event.Issue != nil:
+			fmt.Fprintf(os.Stderr, "[%s] %s: %s\n", event.Issue.Severity, event.Issue.Check, event.Issue.Summary)
+		case event.Result != nil:
+			return event.Result, nil
+		default:
+		}
+	}
+
+	return nil, ctx.Err()
+}
+
+// inputFormat is the format of the haustorium-pcm header if any, of the flags otherwise.
+func inputFormat(cmd *cli.Command, header *types.PCMFormat) (types.PCMFormat, error) {
+	if header != nil {
+		return *header, nil
+	}
+
+	return parsePCMFormat(cmd)
+}
+
 func parsePCMFormat(cmd *cli.Command) (types.PCMFormat, error) {
-	sampleRate := cmd.Int("sample-rate")
-	rawBitDepth := cmd.Int("bit-depth")
-	channels := cmd.Int("channels")
-	expectedBitDepth := cmd.Int("expected-bit-depth")
+	if !cmd.IsSet("sample-rate") {
+		return types.PCMFormat{}, errMissingSampleRate
+	}
+
+	return buildRawFormat(
+		cmd.Int("sample-rate"),
+		cmd.Int("bit-depth"),
+		cmd.Int("channels"),
+		cmd.Int("expected-bit-depth"),
+		cmd.String("layout"),
+		cmd.String("endian"),
+	)
+}
 
+func buildRawFormat(
+	sampleRate, rawBitDepth, channels, expectedBitDepth int,
+	rawLayout, rawEndian string,
+) (types.PCMFormat, error) {
 	bitDepth, err := toBitDepth(rawBitDepth)
 	if err != nil {
 		return types.PCMFormat{}, fmt.Errorf("--bit-depth: %w", err)
@@ -141,14 +300,52 @@ func parsePCMFormat(cmd *cli.Command) (types.PCMFormat, error) {
 		}
 	}
 
+	layout, err := toLayout(rawLayout)
+	if err != nil {
+		return types.PCMFormat{}, fmt.Errorf("--layout: %w", err)
+	}
+
+	byteOrder, err := toByteOrder(rawEndian)
+	if err != nil {
+		return types.PCMFormat{}, fmt.Errorf("--endian: %w", err)
+	}
+
 	return types.PCMFormat{
 		SampleRate:       sampleRate,
 		BitDepth:         bitDepth,
 		Channels:         uint(channels), //nolint:gosec // validated positive value
 		ExpectedBitDepth: ebd,
+		Layout:           layout,
+		ByteOrder:        byteOrder,
 	}, nil
 }
 
+var errInvalidEndian = errors.New("must be little or big")
+
+func toByteOrder(name string) (types.ByteOrder, error) {
+	switch name {
+	case "little", "":
+		return types.LittleEndian, nil
+	case "big":
+		return types.BigEndian, nil
+	default:
+		return 0, errInvalidEndian
+	}
+}
+
+var errInvalidLayout = errors.New("must be native or 24-in-32")
+
+func toLayout(name string) (types.Layout, error) {
+	switch name {
+	case "native", "":
+		return types.LayoutNative, nil
+	case "24-in-32":
+		return types.Layout24In32, nil
+	default:
+		return 0, errInvalidLayout
+	}
+}
+
 var errInvalidBitDepth = errors.New("must be 16, 24, or 32")
 
 func toBitDepth(v int) (types.BitDepth, error) {
@@ -183,9 +380,19 @@ var checkNames = map[string]haustorium.Check{
 	"loudness":           haustorium.CheckLoudness,
 	"dynamic-range":      haustorium.CheckDynamicRange,
 	"dropouts":           haustorium.CheckDropouts,
+	"undithered":         haustorium.CheckUndithered,
+	"de-essing":          haustorium.CheckDeEssing,
+	"bass-rolloff":       haustorium.CheckBassRolloff,
+	"vinyl-cut-safety":   haustorium.CheckVinylCutSafety,
+	"repeats":            haustorium.CheckRepeats,
+	"pitch-offset":       haustorium.CheckPitchOffset,
+	"width-changes":      haustorium.CheckWidthChanges,
+	"trimmed-lead-in":    haustorium.CheckTrimmedLeadIn,
+	"spectral-tilt":      haustorium.CheckSpectralTilt,
 	// Presets.
 	"all":     haustorium.ChecksAll,
 	"defects": haustorium.ChecksDefects,
+	"mix":     haustorium.ChecksMix,
 }
 
 func parseChecks(raw string) (haustorium.Check, error) {
@@ -214,28 +421,116 @@ func parseChecks(raw string) (haustorium.Check, error) {
 
 // readerFactory returns a factory that produces fresh readers for multi-pass analysis.
 // For files, it re-opens the file each time. For stdin, it buffers the entire input.
-func readerFactory(source string) (haustorium.ReaderFactory, func(), error) {
+// When the input starts with a haustorium-pcm header, the readers skip it, and its format is returned.
+func readerFactory(source string) (haustorium.ReaderFactory, *types.PCMFormat, func(), error) {
 	if source == "-" {
 		data, err := io.ReadAll(os.Stdin)
 		if err != nil {
-			return nil, func() {}, fmt.Errorf("reading stdin: %w", err)
+			return nil, nil, func() {}, fmt.Errorf("reading stdin: %w", err)
+		}
+
+		header, err := parseHeader(data)
+		if err != nil {
+			return nil, nil, func() {}, err
+		}
+
+		if header != nil {
+			data = data[haustorium.PCMHeaderSize:]
 		}
 
 		factory := func() (io.Reader, error) {
 			return bytes.NewReader(data), nil
 		}
 
-		return factory, func() {}, nil
+		return factory, header, func() {}, nil
 	}
 
-	// Verify the file exists upfront.
-	if _, err := os.Stat(source); err != nil {
-		return nil, func() {}, fmt.Errorf("cannot access %s: %w", source, err)
+	// Verify the file exists upfront, and look for a header.
+	file, err := os.Open(source) //nolint:gosec // CLI tool opens user-specified audio files
+	if err != nil {
+		return nil, nil, func() {}, fmt.Errorf("cannot access %s: %w", source, err)
+	}
+
+	prefix := make([]byte, haustorium.PCMHeaderSize)
+	n, err := io.ReadFull(file, prefix)
+	_ = file.Close()
+
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, nil, func() {}, fmt.Errorf("reading %s: %w", source, err)
+	}
+
+	header, err := parseHeader(prefix[:n])
+	if err != nil {
+		return nil, nil, func() {}, err
 	}
 
 	factory := func() (io.Reader, error) {
-		return os.Open(source) //nolint:gosec // CLI tool opens user-specified audio files
+		file, err := os.Open(source) //nolint:gosec // CLI tool opens user-specified audio files
+		if err != nil || header == nil {
+			return file, err
+		}
+
+		if _, err := file.Seek(haustorium.PCMHeaderSize, io.SeekStart); err != nil {
+			_ = file.Close()
+
+			return nil, err
+		}
+
+		return file, nil
+	}
+
+	return factory, header, func() {}, nil
+}
+
+// streamInput opens the input for a single pass, without buffering stdin. A haustorium-pcm header is skipped,
+// and its format returned.
+func streamInput(source string) (io.Reader, *types.PCMFormat, func(), error) {
+	input := os.Stdin
+	cleanup := func() {}
+
+	if source != "-" {
+		file, err := os.Open(source) //nolint:gosec // CLI tool opens user-specified audio files
+		if err != nil {
+			return nil, nil, cleanup, fmt.Errorf("cannot access %s: %w", source, err)
+		}
+
+		input = file
+		cleanup = func() { _ = file.Close() }
+	}
+
+	reader := bufio.NewReader(input)
+
+	prefix, err := reader.Peek(haustorium.PCMHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		cleanup()
+
+		return nil, nil, func() {}, fmt.Errorf("reading %s: %w", source, err)
+	}
+
+	header, err := parseHeader(prefix)
+	if err != nil {
+		cleanup()
+
+		return nil, nil, func() {}, err
+	}
+
+	if header != nil {
+		_, _ = reader.Discard(haustorium.PCMHeaderSize)
+	}
+
+	return reader, header, cleanup, nil
+}
+
+// parseHeader returns the format of a haustorium-pcm header at the start of data, or nil if there is none.
+func parseHeader(data []byte) (*types.PCMFormat, error) {
+	format, ok, err := haustorium.ParsePCMHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, nil //nolint:nilnil // no header: the format comes from the flags
 	}
 
-	return factory, func() {}, nil
+	return &format, nil
 }
@@ -0,0 +1,57 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	chartWidth   = 50    // characters for the loudest band
+	chartFloorDb = -80.0 // bands at or below this get an empty bar
+)
+
+// printSpectrumChart renders the spectral band energy as a bar chart, one row per band, so that a brick-wall
+// cutoff shows at a glance. Levels are relative to the spectral reference band.
+func printSpectrumChart(writer io.Writer, spectral *types.SpectralResult) error {
+	if len(spectral.BandEnergy) == 0 {
+		return nil
+	}
+
+	top := chartFloorDb + 10
+	for _, level := range spectral.BandEnergy {
+		top = max(top, level)
+	}
+
+	if _, err := fmt.Fprintf(writer, "\nspectrum (band energy, dB relative to reference):\n"); err != nil {
+		return err
+	}
+
+	for i, level := range spectral.BandEnergy {
+		label := "?"
+		if i < len(spectral.BandFreqs) {
+			label = frequencyLabel(spectral.BandFreqs[i])
+		}
+
+		length := int((level - chartFloorDb) / (top - chartFloorDb) * chartWidth)
+		length = min(max(length, 0), chartWidth)
+
+		bar := strings.Repeat("#", length) + strings.Repeat(" ", chartWidth-length)
+		if _, err := fmt.Fprintf(writer, "  %9s |%s| %6.1f dB\n", label, bar, level); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func frequencyLabel(hz float64) string {
+	if hz >= 1000 {
+		return fmt.Sprintf("%g kHz", hz/1000)
+	}
+
+	return fmt.Sprintf("%g Hz", hz)
+}
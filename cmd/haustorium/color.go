@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/farcloser/haustorium"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// resolveColor decides whether console output is colored: always, never, or auto (stdout is a terminal and
+// NO_COLOR is not set).
+func resolveColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+
+		// An unknown stdout is not a terminal.
+		info, err := os.Stdout.Stat()
+
+		return err == nil && info.Mode()&os.ModeCharDevice != 0, nil
+	default:
+		return false, fmt.Errorf("unknown color mode %q (valid: auto, always, never)", mode)
+	}
+}
+
+// colorize wraps a detected issue's line in its severity color; passed checks stay plain.
+func colorize(line string, issue haustorium.Issue) string {
+	if !issue.Detected || issue.NotApplicable {
+		return line
+	}
+
+	switch issue.Severity {
+	case haustorium.SeveritySevere:
+		return ansiRed + line + ansiReset
+	case haustorium.SeverityModerate:
+		return ansiYellow + line + ansiReset
+	case haustorium.SeverityMild:
+		return ansiCyan + line + ansiReset
+	default:
+		return line
+	}
+}
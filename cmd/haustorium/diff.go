@@ -0,0 +1,258 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+
+	"github.com/farcloser/primordium/format"
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/output"
+)
+
+var errDiffArgs = errors.New("expected exactly two arguments: the file paths")
+
+// diffMissing stands for a value one side does not have: a check not run, an analyzer that did not apply.
+const diffMissing = "-"
+
+func diffCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Analyze two versions of the same track and compare their results side by side",
+		ArgsUsage: "<fileA> <fileB>",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "checks",
+				Aliases: []string{"C"},
+				Usage:   "Comma-separated checks or presets, as for process",
+				Value:   "all",
+			},
+			&cli.StringFlag{
+				Name:    "source",
+				Aliases: []string{"S"},
+				Usage:   "Audio source type adjusting detection thresholds: digital, vinyl, live",
+				Value:   "digital",
+			},
+			&cli.StringFlag{
+				Name:  "genre",
+				Usage: "Genre adjusting dynamic range expectations: classical, jazz, rock, pop, electronic",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: console, json, markdown",
+				Value:   "console",
+			},
+		}, binaries.Flags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.NArg() != 2 {
+				return fmt.Errorf("%w: got %d", errDiffArgs, cmd.NArg())
+			}
+
+			checks, err := parseChecks(cmd.String("checks"))
+			if err != nil {
+				return err
+			}
+
+			source, err := haustorium.ParseSource(cmd.String("source"))
+			if err != nil {
+				return err
+			}
+
+			genre, err := haustorium.ParseGenre(cmd.String("genre"))
+			if err != nil {
+				return err
+			}
+
+			opts := haustorium.OptionsForSource(source)
+			opts.Checks = checks
+			opts.Genre = genre
+
+			pathA, pathB := cmd.Args().Get(0), cmd.Args().Get(1)
+
+			resultA, err := diffAnalyze(ctx, binaries.FromCommand(cmd), pathA, opts)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pathA, err)
+			}
+
+			resultB, err := diffAnalyze(ctx, binaries.FromCommand(cmd), pathB, opts)
+			if err != nil {
+				return fmt.Errorf("%s: %w", pathB, err)
+			}
+
+			formatName := cmd.String("format")
+
+			formatter, err := format.GetFormatter(formatName)
+			if err != nil {
+				return err
+			}
+
+			data := &format.Data{
+				Object: pathA + " vs " + pathB,
+				Meta:   buildDiff(resultA, resultB, formatName == "json"),
+			}
+
+			return formatter.PrintAll([]*format.Data{data}, os.Stdout)
+		},
+	}
+}
+
+// diffAnalyze decodes the first audio stream of a file and analyzes it.
+func diffAnalyze(
+	ctx context.Context,
+	bins binaries.Binaries,
+	filePath string,
+	opts haustorium.Options,
+) (*haustorium.Result, error) {
+	factory, pcmFormat, stream, err := decodeInput(ctx, bins, filePath, 0, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.SourceCodec = stream.CodecName
+
+	result, err := haustorium.Analyze(factory, pcmFormat, opts)
+	if err != nil {
+		return nil, fmt.Errorf("analysis failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// buildDiff lays out two results side by side: summary, key properties and issues for every check, then the raw
+// metrics that differ. Records are "A | B" strings, or {a, b} objects in JSON.
+func buildDiff(resultA, resultB *haustorium.Result, structured bool) map[string]any {
+	column := func(valueA, valueB any) any {
+		if structured {
+			return map[string]any{"a": valueA, "b": valueB}
+		}
+
+		return fmt.Sprintf("%v  |  %v", valueA, valueB)
+	}
+
+	meta := map[string]any{
+		"summary": map[string]any{
+			"issue_count":    column(resultA.IssueCount, resultB.IssueCount),
+			"worst_severity": column(resultA.WorstSeverity.String(), resultB.WorstSeverity.String()),
+			"severity_codes": column(resultA.SeverityCodes(), resultB.SeverityCodes()),
+		},
+	}
+
+	properties := map[string]any{}
+	propsA, propsB := buildProperties(resultA), buildProperties(resultB)
+
+	for key := range joinKeys(propsA, propsB) {
+		properties[key] = column(diffValue(propsA, key), diffValue(propsB, key))
+	}
+
+	if len(properties) > 0 {
+		meta["properties"] = properties
+	}
+
+	issues := map[string]any{}
+	issuesA, issuesB := diffIssues(resultA), diffIssues(resultB)
+
+	for key := range joinKeys(issuesA, issuesB) {
+		issues[key] = column(diffValue(issuesA, key), diffValue(issuesB, key))
+	}
+
+	if len(issues) > 0 {
+		meta["issues"] = issues
+	}
+
+	// Raw metrics: only the differences, the bulk of them being equal on two versions of the same track.
+	metrics := map[string]any{}
+	rawA, rawB := diffMetrics(resultA), diffMetrics(resultB)
+
+	for key := range joinKeys(rawA, rawB) {
+		valueA, valueB := diffValue(rawA, key), diffValue(rawB, key)
+		if valueA != valueB {
+			metrics[key] = column(valueA, valueB)
+		}
+	}
+
+	if len(metrics) > 0 {
+		meta["metrics"] = metrics
+	}
+
+	return meta
+}
+
+// diffIssues describes the verdict of every check of a result, by check name.
+func diffIssues(result *haustorium.Result) map[string]any {
+	issues := make(map[string]any, len(result.Issues))
+
+	for _, issue := range result.Issues {
+		line := fmt.Sprintf("[%s] %s", issue.Severity, issue.Summary)
+		if issue.NotApplicable {
+			line = "[n/a] " + issue.Summary
+		}
+
+		issues[issue.Check.String()] = line
+	}
+
+	return issues
+}
+
+// diffMetrics flattens the raw analyzer results into dotted keys ("loudness.integrated_lufs") and printable
+// values. Lists are reduced to their length: events and segments are not aligned between the two files.
+func diffMetrics(result *haustorium.Result) map[string]any {
+	raw := output.ResultToMap(result)
+
+	// The verdicts and the options are compared elsewhere, or the same on both sides.
+	delete(raw, "summary")
+	delete(raw, "issues")
+	delete(raw, "config")
+
+	metrics := map[string]any{}
+	flattenMetrics(metrics, "", raw)
+
+	return metrics
+}
+
+func flattenMetrics(metrics map[string]any, prefix string, values map[string]any) {
+	for key, value := range values {
+		switch typed := value.(type) {
+		case map[string]any:
+			flattenMetrics(metrics, prefix+key+".", typed)
+		case float64:
+			metrics[prefix+key] = strconv.FormatFloat(typed, 'g', 6, 64)
+		default:
+			if list := reflect.ValueOf(value); list.Kind() == reflect.Slice {
+				metrics[prefix+key] = fmt.Sprintf("%d entries", list.Len())
+			} else {
+				metrics[prefix+key] = fmt.Sprint(value)
+			}
+		}
+	}
+}
+
+// joinKeys returns the keys of both maps.
+func joinKeys(mapA, mapB map[string]any) map[string]bool {
+	keys := map[string]bool{}
+
+	for key := range mapA {
+		keys[key] = true
+	}
+
+	for key := range mapB {
+		keys[key] = true
+	}
+
+	return keys
+}
+
+func diffValue(values map[string]any, key string) any {
+	if value, ok := values[key]; ok {
+		return value
+	}
+
+	return diffMissing
+}
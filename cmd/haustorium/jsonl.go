@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/report"
+)
+
+var errRecordFailed = errors.New("processing failed")
+
+// emitRecord probes, decodes and analyzes a file through the report pipeline, and writes its record to stdout, as
+// one JSONL line: the record hau-report report writes for it, so that reports can be built file by file, and read
+// by hau-report digest as they are. A failure is recorded too, as the report does, and returned.
+func emitRecord(
+	ctx context.Context,
+	cmd *cli.Command,
+	filePath string,
+	streamIndex int,
+	probeResult *ffprobe.Result,
+	opts haustorium.Options,
+) error {
+	bins := binaries.FromCommand(cmd)
+
+	record := report.Process(ctx, filePath, probeResult, &report.Options{
+		FFprobe:     bins.FFprobe,
+		FFmpeg:      bins.FFmpeg,
+		Stream:      streamIndex,
+		TagBitDepth: cmd.Bool("bit-depth-from-tags"),
+		Analysis: func(string) (haustorium.Options, error) {
+			return opts, nil
+		},
+	})
+
+	if err := json.NewEncoder(os.Stdout).Encode(record); err != nil {
+		return fmt.Errorf("writing record: %w", err)
+	}
+
+	if record.Error != "" {
+		return fmt.Errorf("%w: %s", errRecordFailed, record.Error)
+	}
+
+	return nil
+}
@@ -20,6 +20,8 @@ func main() {
 		Commands: []*cli.Command{
 			analyzeCommand(),
 			processCommand(),
+			serveCommand(),
+			diffCommand(),
 		},
 	}
 
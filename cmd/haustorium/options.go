@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+)
+
+var errProfileSource = errors.New("--profile and --source are mutually exclusive: the profile sets the source")
+
+// analysisOptions builds the options of analyze and process: from --profile if given, from --source otherwise,
+// then every analysis flag set on the command line on top. Flags left unset keep the profile's values; their
+// defaults are the source presets' own.
+func analysisOptions(cmd *cli.Command) (haustorium.Options, error) {
+	profile, err := haustorium.ParseProfile(cmd.String("profile"))
+	if err != nil {
+		return haustorium.Options{}, err
+	}
+
+	var opts haustorium.Options
+
+	if profile != haustorium.ProfileNone {
+		if cmd.IsSet("source") {
+			return haustorium.Options{}, errProfileSource
+		}
+
+		opts = haustorium.OptionsForProfile(profile)
+	} else {
+		source, err := haustorium.ParseSource(cmd.String("source"))
+		if err != nil {
+			return haustorium.Options{}, err
+		}
+
+		opts = haustorium.OptionsForSource(source)
+	}
+
+	if profile == haustorium.ProfileNone || cmd.IsSet("checks") {
+		opts.Checks, err = parseChecks(cmd.String("checks"))
+		if err != nil {
+			return haustorium.Options{}, err
+		}
+	}
+
+	if cmd.IsSet("genre") {
+		opts.Genre, err = haustorium.ParseGenre(cmd.String("genre"))
+		if err != nil {
+			return haustorium.Options{}, err
+		}
+	}
+
+	floats := map[string]*float64{
+		"min-confidence":         &opts.MinConfidence,
+		"pitch-reference":        &opts.PitchReferenceHz,
+		"quiet-windows-fraction": &opts.NoiseQuietFraction,
+		"quiet-gate":             &opts.NoiseQuietGateDbFS,
+		"trim-edges":             &opts.TrimEdgesSec,
+		"loudness-target":        &opts.LoudnessTargetLUFS,
+		"true-peak-ceiling":      &opts.TruePeakCeilingDb,
+		"short-term-allowance":   &opts.ShortTermAllowanceLU,
+	}
+
+	for name, field := range floats {
+		if cmd.IsSet(name) {
+			*field = cmd.Float(name)
+		}
+	}
+
+	bools := map[string]*bool{
+		"edge-noise-floor":   &opts.EdgeNoiseFloor,
+		"thorough":           &opts.SpectralThorough,
+		"bit-depth-sections": &opts.BitDepthSections,
+	}
+
+	for name, field := range bools {
+		if cmd.IsSet(name) {
+			*field = cmd.Bool(name)
+		}
+	}
+
+	return opts, nil
+}
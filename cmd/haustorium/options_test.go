@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+)
+
+// processOptions returns the options process builds from the arguments.
+func processOptions(t *testing.T, args ...string) (haustorium.Options, error) {
+	t.Helper()
+
+	var (
+		opts    haustorium.Options
+		optsErr error
+	)
+
+	cmd := processCommand()
+	cmd.Action = func(_ context.Context, cmd *cli.Command) error {
+		opts, optsErr = analysisOptions(cmd)
+
+		return nil
+	}
+
+	if err := cmd.Run(context.Background(), append([]string{"process"}, args...)); err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+
+	return opts, optsErr
+}
+
+func TestAnalysisOptionsProfile(t *testing.T) {
+	t.Parallel()
+
+	opts, err := processOptions(t, "--profile", "broadcast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The profile's values, not the flag defaults: -14 LUFS would be streaming, "all" the checks flag's.
+	if opts.Profile != haustorium.ProfileBroadcast || opts.LoudnessTargetLUFS != -23 ||
+		opts.Checks != haustorium.ChecksDefects|haustorium.ChecksLoudness || opts.Loudness.Mild != 0.5 {
+		t.Fatalf("expected the broadcast options, got %+v", opts)
+	}
+}
+
+func TestAnalysisOptionsProfileOverride(t *testing.T) {
+	t.Parallel()
+
+	opts, err := processOptions(t, "--profile", "broadcast", "--loudness-target", "-16", "--checks", "clipping",
+		"--thorough")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.LoudnessTargetLUFS != -16 || opts.Checks != haustorium.CheckClipping || !opts.SpectralThorough {
+		t.Fatalf("expected the flags over the profile, got %+v", opts)
+	}
+
+	// What the flags leave alone keeps the profile's value.
+	if opts.Loudness.Mild != 0.5 {
+		t.Fatalf("expected the broadcast loudness bands, got %+v", opts.Loudness)
+	}
+
+	// Down to the flags whose default differs from the profile's: 0 for --trim-edges.
+	opts, err = processOptions(t, "--profile", "vinyl-transfer", "--thorough")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.TrimEdgesSec != 2 || !opts.SpectralThorough {
+		t.Fatalf("expected the vinyl-transfer trim with --thorough, got %+v", opts)
+	}
+}
+
+func TestAnalysisOptionsProfileSource(t *testing.T) {
+	t.Parallel()
+
+	if _, err := processOptions(t, "--profile", "archival", "--source", "vinyl"); !errors.Is(err, errProfileSource) {
+		t.Fatalf("expected %v, got %v", errProfileSource, err)
+	}
+}
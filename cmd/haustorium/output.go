@@ -2,11 +2,17 @@
 package main
 
 import (
+	"cmp"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"slices"
 
 	"github.com/farcloser/primordium/format"
+	"github.com/urfave/cli/v3"
 
 	"github.com/farcloser/haustorium"
 	"github.com/farcloser/haustorium/internal/output"
@@ -14,6 +20,8 @@ import (
 
 const docsBaseURL = "https://github.com/farcloser/haustorium/blob/main/docs/issues"
 
+var errJSONLayout = errors.New("--json-pretty and --json-compact are mutually exclusive")
+
 // issueInfo maps checks to their HAU ID and category.
 type issueInfo struct {
 	hauID    string
@@ -27,11 +35,13 @@ var issueInfoMap = map[haustorium.Check]issueInfo{
 	haustorium.CheckFakeSampleRate: {hauID: "HAU-003", category: "1. Source authenticity"},
 	haustorium.CheckLossyTranscode: {hauID: "HAU-004", category: "1. Source authenticity"},
 	haustorium.CheckFakeStereo:     {hauID: "HAU-005", category: "1. Source authenticity"},
+	haustorium.CheckPitchOffset:    {hauID: "HAU-023", category: "1. Source authenticity"},
 
 	// Stereo field
 	haustorium.CheckPhaseIssues:      {hauID: "HAU-006", category: "2. Stereo field"},
 	haustorium.CheckInvertedPhase:    {hauID: "HAU-007", category: "2. Stereo field"},
 	haustorium.CheckChannelImbalance: {hauID: "HAU-008", category: "2. Stereo field"},
+	haustorium.CheckWidthChanges:     {hauID: "HAU-024", category: "2. Stereo field"},
 
 	// Dynamics & levels
 	haustorium.CheckClipping:         {hauID: "HAU-001", category: "3. Dynamics & levels"},
@@ -48,6 +58,15 @@ var issueInfoMap = map[haustorium.Check]issueInfo{
 	haustorium.CheckDropouts:       {hauID: "HAU-015", category: "5. Digital artifacts"},
 	haustorium.CheckTruncation:     {hauID: "HAU-016", category: "5. Digital artifacts"},
 	haustorium.CheckSilencePadding: {hauID: "HAU-017", category: "5. Digital artifacts"},
+	haustorium.CheckUndithered:     {hauID: "HAU-018", category: "5. Digital artifacts"},
+	haustorium.CheckRepeats:        {hauID: "HAU-022", category: "5. Digital artifacts"},
+	haustorium.CheckTrimmedLeadIn:  {hauID: "HAU-025", category: "5. Digital artifacts"},
+
+	// Mix quality
+	haustorium.CheckDeEssing:       {hauID: "HAU-019", category: "6. Mix quality"},
+	haustorium.CheckBassRolloff:    {hauID: "HAU-020", category: "6. Mix quality"},
+	haustorium.CheckVinylCutSafety: {hauID: "HAU-021", category: "6. Mix quality"},
+	haustorium.CheckSpectralTilt:   {hauID: "HAU-026", category: "6. Mix quality"},
 }
 
 // categoryOrder defines the display order for categories (numbered for sorting).
@@ -59,19 +78,157 @@ var categoryOrder = []string{
 	"3. Dynamics & levels",
 	"4. Noise & interference",
 	"5. Digital artifacts",
+	"6. Mix quality",
+}
+
+// display holds the output flags shared by analyze and process.
+type display struct {
+	debug       bool
+	verbose     bool
+	raw         output.Raw
+	include     map[string]bool     // raw blocks to keep (--include); nil: all
+	bySeverity  bool                // list issues worst first, instead of grouped by category
+	minSeverity haustorium.Severity // omit issues below this severity
+	compact     bool                // with json output, one line instead of indented
+	color       bool                // with console output, color detected issues by severity
+	probe       map[string]any      // probed file format, shown with the results (process --show-format); nil: none
+}
+
+func parseDisplay(cmd *cli.Command) (display, error) {
+	raw, err := output.ParseRaw(cmd.String("raw"))
+	if err != nil {
+		return display{}, err
+	}
+
+	include, err := output.ParseInclude(cmd.String("include"))
+	if err != nil {
+		return display{}, err
+	}
+
+	minSeverity, err := haustorium.ParseSeverity(cmd.String("min-severity"))
+	if err != nil {
+		return display{}, err
+	}
+
+	var bySeverity bool
+
+	switch sortBy := cmd.String("sort"); sortBy {
+	case "category":
+	case "severity":
+		bySeverity = true
+	default:
+		return display{}, fmt.Errorf("unknown sort %q (valid: category, severity)", sortBy)
+	}
+
+	if cmd.Bool("json-compact") && cmd.Bool("json-pretty") {
+		return display{}, errJSONLayout
+	}
+
+	color, err := resolveColor(cmd.String("color"))
+	if err != nil {
+		return display{}, err
+	}
+
+	return display{
+		debug:       cmd.Bool("debug"),
+		verbose:     cmd.Bool("verbose"),
+		raw:         raw,
+		include:     include,
+		bySeverity:  bySeverity,
+		minSeverity: minSeverity,
+		compact:     cmd.Bool("json-compact"),
+		color:       color,
+	}, nil
+}
+
+// displayFlags are the flags parseDisplay reads.
+func displayFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "debug",
+			Aliases: []string{"D"},
+			Usage:   "Include all raw analyzer data in output",
+		},
+		&cli.BoolFlag{
+			Name:    "verbose",
+			Aliases: []string{"V"},
+			Usage:   "With console output, also chart the spectral band energy",
+		},
+		&cli.StringFlag{
+			Name:  "raw",
+			Usage: "With --debug, which raw analyzer blocks to include: none, detected, all",
+			Value: "all",
+		},
+		&cli.StringFlag{
+			Name:  "include",
+			Usage: "With --debug, only include these raw analyzer blocks (comma-separated: loudness,true_peak...)",
+		},
+		&cli.StringFlag{
+			Name:  "sort",
+			Usage: "Issue order: category (grouped), severity (detected issues first, worst first)",
+			Value: "category",
+		},
+		&cli.StringFlag{
+			Name:  "min-severity",
+			Usage: "Omit issues below this severity: none, mild, moderate, severe (mild hides passed checks)",
+			Value: "none",
+		},
+		&cli.BoolFlag{
+			Name:  "json-pretty",
+			Usage: "With json output, indent it for reading (default)",
+		},
+		&cli.BoolFlag{
+			Name:  "json-compact",
+			Usage: "With json output, write it on a single line, for storage",
+		},
+		&cli.StringFlag{
+			Name: "color",
+			Usage: "Color console output by severity: " +
+				"auto (when stdout is a terminal and NO_COLOR is unset), always, never",
+			Value: "auto",
+		},
+	}
+}
+
+// outputResult writes the result to stdout. With verbose, console output is followed by a spectrum chart.
+func outputResult(filePath string, result *haustorium.Result, formatName string, disp display) error {
+	if err := writeResult(os.Stdout, filePath, result, formatName, disp); err != nil {
+		return err
+	}
+
+	if disp.verbose && formatName == "console" && result.Spectral != nil {
+		return printSpectrumChart(os.Stdout, result.Spectral)
+	}
+
+	return nil
 }
 
-func outputResult(filePath string, result *haustorium.Result, formatName string, debug bool) error {
+func writeResult(
+	writer io.Writer,
+	filePath string,
+	result *haustorium.Result,
+	formatName string,
+	disp display,
+) error {
 	formatter, err := format.GetFormatter(formatName)
 	if err != nil {
 		return err
 	}
 
+	// Color is for the console only: json and markdown stay plain, whatever the flag.
+	disp.color = disp.color && formatName == "console"
+
 	var meta map[string]any
-	if debug {
+	if disp.debug {
 		meta = output.ResultToMap(result)
+		output.PruneRaw(meta, disp.raw)
+		output.IncludeRaw(meta, disp.include)
 	} else {
-		meta = buildFriendlyOutput(result)
+		meta = buildFriendlyOutput(result, disp)
+	}
+
+	if disp.probe != nil {
+		meta["probe"] = disp.probe
 	}
 
 	data := &format.Data{
@@ -79,19 +236,61 @@ func outputResult(filePath string, result *haustorium.Result, formatName string,
 		Meta:   meta,
 	}
 
-	return formatter.PrintAll([]*format.Data{data}, os.Stdout)
+	// The json formatter always indents: compact output is encoded here, in the same shape.
+	if disp.compact && formatName == "json" {
+		if err := json.NewEncoder(writer).Encode([]*format.Data{data}); err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	return formatter.PrintAll([]*format.Data{data}, writer)
 }
 
 // buildFriendlyOutput creates a user-friendly summary of the analysis results.
-func buildFriendlyOutput(result *haustorium.Result) map[string]any {
+func buildFriendlyOutput(result *haustorium.Result, disp display) map[string]any {
 	meta := map[string]any{
 		"summary": fmt.Sprintf("%d issues found (worst: %s)", result.IssueCount, result.WorstSeverity),
 	}
 
+	if result.ByteSwapSuspected {
+		meta["warning"] = "samples look byte-swapped: the input's byte order likely does not match the format"
+	}
+
+	if len(result.Warnings) > 0 {
+		meta["warnings"] = result.Warnings
+	}
+
 	// Group issues by category.
 	categoryIssues := make(map[string][]any)
 
+	listed := make([]haustorium.Issue, 0, len(result.Issues))
+
 	for _, issue := range result.Issues {
+		if issue.Severity >= disp.minSeverity {
+			listed = append(listed, issue)
+		}
+	}
+
+	if disp.bySeverity {
+		// Detected issues first, worst first; then the passed checks. Ties keep the check order.
+		slices.SortStableFunc(listed, func(a, b haustorium.Issue) int {
+			if a.Detected != b.Detected {
+				if a.Detected {
+					return -1
+				}
+
+				return 1
+			}
+
+			return cmp.Compare(b.Severity, a.Severity)
+		})
+	}
+
+	var ordered []any
+
+	for _, issue := range listed {
 		info, ok := issueInfoMap[issue.Check]
 		if !ok {
 			continue
@@ -102,15 +301,32 @@ func buildFriendlyOutput(result *haustorium.Result) map[string]any {
 			marker = "!!"
 		}
 
+		summary := issue.Summary
+		if issue.AffectedChannels != "" {
+			summary += " (" + issue.AffectedChannels + ")"
+		}
+
 		docURL := fmt.Sprintf("%s/%s.md", docsBaseURL, info.hauID)
 		line := fmt.Sprintf("%s [%s] %s: %s (%.0f%% confidence) - %s",
-			marker, issue.Severity, issue.Check, issue.Summary, issue.Confidence*100, docURL)
+			marker, issue.Severity, issue.Check, summary, issue.Confidence*100, docURL)
+
+		if issue.NotApplicable {
+			line = fmt.Sprintf("%s [n/a] %s: %s - %s", marker, issue.Check, summary, docURL)
+		}
+
+		if disp.color {
+			line = colorize(line, issue)
+		}
 
 		categoryIssues[info.category] = append(categoryIssues[info.category], line)
+		ordered = append(ordered, line)
 	}
 
 	// Build ordered issues map.
-	if len(categoryIssues) > 0 {
+	switch {
+	case disp.bySeverity && len(ordered) > 0:
+		meta["issues"] = ordered
+	case len(categoryIssues) > 0:
 		issues := make(map[string]any)
 
 		for _, cat := range categoryOrder {
@@ -120,6 +336,7 @@ func buildFriendlyOutput(result *haustorium.Result) map[string]any {
 		}
 
 		meta["issues"] = issues
+	default:
 	}
 
 	// Key properties.
@@ -157,6 +374,14 @@ func buildProperties(result *haustorium.Result) map[string]any {
 				imbalanceSide(r.ImbalanceDb),
 			)
 		}
+
+		if r.SuspectedDownmix {
+			props["downmix"] = fmt.Sprintf(
+				"suspected multichannel downmix (midrange correlation: %.2f, upper band: %.2f)",
+				r.MidCorrelation,
+				r.SideCorrelation,
+			)
+		}
 	}
 
 	if r := result.BitDepth; r != nil {
@@ -7,14 +7,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"strconv"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/farcloser/haustorium"
-	"github.com/farcloser/haustorium/internal/integration/ffmpeg"
+	"github.com/farcloser/haustorium/internal/binaries"
 	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/report"
 	"github.com/farcloser/haustorium/internal/types"
 )
 
@@ -25,13 +25,17 @@ func processCommand() *cli.Command {
 		Name:      "process",
 		Usage:     "Extract PCM from an audio file and analyze for quality issues",
 		ArgsUsage: "<file>",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "checks",
 				Aliases: []string{"C"},
-				Usage:   "Comma-separated checks or presets: all, defects, loudness, clipping, truncation, fake-bit-depth, fake-sample-rate, lossy-transcode, dc-offset, fake-stereo, phase-issues, inverted-phase, channel-imbalance, silence-padding, hum, noise-floor, inter-sample-peaks, dynamic-range, dropouts",
+				Usage:   "Comma-separated checks or presets: all, defects, loudness, mix, clipping, truncation, fake-bit-depth, fake-sample-rate, lossy-transcode, dc-offset, fake-stereo, phase-issues, inverted-phase, channel-imbalance, silence-padding, hum, noise-floor, inter-sample-peaks, dynamic-range, dropouts, undithered, de-essing, bass-rolloff, vinyl-cut-safety, repeats, pitch-offset, width-changes, trimmed-lead-in, spectral-tilt",
 				Value:   "all",
 			},
+			&cli.StringFlag{
+				Name:  "probe-json",
+				Usage: "Use this ffprobe JSON output (-show_format -show_streams) instead of running ffprobe",
+			},
 			&cli.IntFlag{
 				Name:  "stream",
 				Usage: "Audio stream index (0-based)",
@@ -43,6 +47,63 @@ func processCommand() *cli.Command {
 				Usage:   "Audio source type adjusting detection thresholds: digital, vinyl, live",
 				Value:   "digital",
 			},
+			&cli.StringFlag{
+				Name:  "profile",
+				Usage: "Workflow preset: archival, broadcast, streaming, vinyl-transfer (flags given override it)",
+			},
+			&cli.FloatFlag{
+				Name:  "min-confidence",
+				Usage: "Report detections below this confidence (0-1) as not detected",
+			},
+			&cli.FloatFlag{
+				Name:  "pitch-reference",
+				Usage: "Tuning reference (A4, in Hz) for pitch-offset",
+				Value: 440,
+			},
+			&cli.FloatFlag{
+				Name:  "trim-edges",
+				Usage: "Skip the first and last seconds in every check but loudness, truncation and silence-padding",
+			},
+			&cli.FloatFlag{
+				Name:  "quiet-windows-fraction",
+				Usage: "Share (0-1) of the quietest spectral windows the noise floor is measured on",
+				Value: 0.2,
+			},
+			&cli.FloatFlag{
+				Name:  "quiet-gate",
+				Usage: "RMS (dBFS) the quiet windows must exceed for the noise floor to be measured on them",
+				Value: -50,
+			},
+			&cli.StringFlag{
+				Name:  "genre",
+				Usage: "Genre adjusting dynamic range expectations: classical, jazz, rock, pop, electronic",
+			},
+			&cli.BoolFlag{
+				Name:  "edge-noise-floor",
+				Usage: "Also measure the noise floor of the leading and trailing silence (default with --source vinyl)",
+			},
+			&cli.BoolFlag{
+				Name:  "thorough",
+				Usage: "Run the spectral checks on every window instead of 100 spread across the track (slower)",
+			},
+			&cli.BoolFlag{
+				Name:  "bit-depth-sections",
+				Usage: "Also measure the bit depth of every second, to find zero-padded sections in a genuine file",
+			},
+			&cli.FloatFlag{
+				Name:  "loudness-target",
+				Usage: "Grade loudness against this integrated target (LUFS) instead of reporting it as informational",
+			},
+			&cli.FloatFlag{
+				Name:  "true-peak-ceiling",
+				Usage: "True peak ceiling (dBTP) for --loudness-target",
+				Value: -1,
+			},
+			&cli.FloatFlag{
+				Name:  "short-term-allowance",
+				Usage: "How far (LU) the loudest short-term window may exceed --loudness-target",
+				Value: 5,
+			},
 			&cli.StringFlag{
 				Name:    "format",
 				Aliases: []string{"f"},
@@ -50,11 +111,18 @@ func processCommand() *cli.Command {
 				Value:   "console",
 			},
 			&cli.BoolFlag{
-				Name:    "debug",
-				Aliases: []string{"D"},
-				Usage:   "Include all raw analyzer data in output",
+				Name:  "bit-depth-from-tags",
+				Usage: "Take the original bit depth from the file's tags (bits_per_sample, bit_depth...) when present",
 			},
-		},
+			&cli.BoolFlag{
+				Name:  "show-format",
+				Usage: "Show the probed codec, container, sample rate, bit depth, channels, bitrate and duration",
+			},
+			&cli.BoolFlag{
+				Name:  "emit-jsonl",
+				Usage: "Write the hau-report report record of the file (analysis, probe, timing) as one JSONL line",
+			},
+		}, append(displayFlags(), binaries.Flags()...)...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
 			if cmd.NArg() != 1 {
 				return fmt.Errorf("%w: got %d", errProcessArgs, cmd.NArg())
@@ -63,120 +131,135 @@ func processCommand() *cli.Command {
 			filePath := cmd.Args().First()
 			streamIndex := cmd.Int("stream")
 
-			checks, err := parseChecks(cmd.String("checks"))
+			opts, err := analysisOptions(cmd)
 			if err != nil {
 				return err
 			}
 
-			// Probe the file for audio properties.
-			probeResult, err := ffprobe.Probe(ctx, filePath)
-			if err != nil {
-				return fmt.Errorf("probing file: %w", err)
-			}
-
-			stream, err := findAudioStream(probeResult, streamIndex)
-			if err != nil {
-				return err
-			}
+			var probeResult *ffprobe.Result
 
-			format, err := buildPCMFormat(stream)
-			if err != nil {
-				return err
+			if probePath := cmd.String("probe-json"); probePath != "" {
+				probeResult, err = ffprobe.Load(probePath)
+				if err != nil {
+					return fmt.Errorf("loading probe: %w", err)
+				}
 			}
 
-			// Extract PCM (32-bit) from the file via ffmpeg.
-			file, openErr := os.Open(filePath) //nolint:gosec // CLI tool opens user-specified audio files
-			if openErr != nil {
-				return fmt.Errorf("opening file: %w", openErr)
+			if cmd.Bool("emit-jsonl") {
+				return emitRecord(ctx, cmd, filePath, streamIndex, probeResult, opts)
 			}
-			defer file.Close()
-
-			var pcmBuf bytes.Buffer
 
-			extractFormat := &types.PCMFormat{BitDepth: types.Depth32}
-
-			if err = ffmpeg.ExtractStream(ctx, file, &pcmBuf, streamIndex, extractFormat); err != nil {
-				return fmt.Errorf("extracting PCM: %w", err)
+			// The probe is kept for the format summary, instead of decodeInput running its own.
+			if probeResult == nil && cmd.Bool("show-format") {
+				probeResult, err = ffprobe.Probe(ctx, cmd.String("ffprobe"), filePath)
+				if err != nil {
+					return fmt.Errorf("probing file: %w", err)
+				}
 			}
 
-			// Build reader factory from extracted PCM.
-			pcmData := pcmBuf.Bytes()
-			factory := func() (io.Reader, error) {
-				return bytes.NewReader(pcmData), nil
+			factory, format, stream, err := decodeInput(
+				ctx, binaries.FromCommand(cmd), filePath, streamIndex, probeResult, cmd.Bool("bit-depth-from-tags"),
+			)
+			if err != nil {
+				return err
 			}
 
 			// Run analysis.
-			source, sourceErr := haustorium.ParseSource(cmd.String("source"))
-			if sourceErr != nil {
-				return sourceErr
-			}
-
-			opts := haustorium.OptionsForSource(source)
-			opts.Checks = checks
+			opts.SourceCodec = stream.CodecName
 
 			result, err := haustorium.Analyze(factory, format, opts)
 			if err != nil {
 				return fmt.Errorf("analysis failed: %w", err)
 			}
 
-			return outputResult(filePath, result, cmd.String("format"), cmd.Bool("debug"))
+			disp, err := parseDisplay(cmd)
+			if err != nil {
+				return err
+			}
+
+			if cmd.Bool("show-format") {
+				disp.probe = probeSummary(probeResult, stream)
+			}
+
+			return outputResult(filePath, result, cmd.String("format"), disp)
 		},
 	}
 }
 
-func findAudioStream(result *ffprobe.Result, streamIndex int) (*ffprobe.Stream, error) {
-	audioCount := 0
+// probeSummary describes the probed file: codec, container, sample rate, bit depth, channels, bitrate and
+// duration. Values the probe left out are omitted.
+func probeSummary(probeResult *ffprobe.Result, stream *ffprobe.Stream) map[string]any {
+	summary := map[string]any{
+		"codec":    stream.CodecName,
+		"channels": stream.Channels,
+	}
 
-	for i := range result.Streams {
-		if result.Streams[i].CodecType == "audio" {
-			if audioCount == streamIndex {
-				return &result.Streams[i], nil
-			}
+	if stream.CodecLongName != "" {
+		summary["codec"] = stream.CodecLongName
+	}
 
-			audioCount++
-		}
+	if probeResult.Format.FormatName != "" {
+		summary["container"] = probeResult.Format.FormatName
 	}
 
-	return nil, fmt.Errorf("audio stream index %d not found (file has %d audio streams)", streamIndex, audioCount)
-}
+	if stream.ChannelLayout != "" {
+		summary["channels"] = fmt.Sprintf("%d (%s)", stream.Channels, stream.ChannelLayout)
+	}
 
-func buildPCMFormat(stream *ffprobe.Stream) (types.PCMFormat, error) {
-	sampleRate, err := strconv.Atoi(stream.SampleRate)
-	if err != nil || sampleRate <= 0 {
-		return types.PCMFormat{}, fmt.Errorf("invalid sample rate from probe: %q", stream.SampleRate)
+	if rate, err := strconv.Atoi(stream.SampleRate); err == nil {
+		summary["sample_rate"] = fmt.Sprintf("%d Hz", rate)
 	}
 
-	if stream.Channels <= 0 {
-		return types.PCMFormat{}, fmt.Errorf("invalid channel count from probe: %d", stream.Channels)
+	// Lossy codecs have no bit depth: the probe leaves both fields out.
+	if bits, err := strconv.Atoi(stream.BitsPerRawSample); err == nil && bits > 0 {
+		summary["bit_depth"] = fmt.Sprintf("%d bits", bits)
+	} else if stream.BitsPerSample > 0 {
+		summary["bit_depth"] = fmt.Sprintf("%d bits", stream.BitsPerSample)
 	}
 
-	return types.PCMFormat{
-		SampleRate:       sampleRate,
-		BitDepth:         types.Depth32,
-		Channels:         uint(stream.Channels), //nolint:gosec // validated positive value
-		ExpectedBitDepth: resolveExpectedBitDepth(stream),
-	}, nil
-}
+	for _, raw := range []string{stream.BitRate, probeResult.Format.BitRate} {
+		if bitRate, err := strconv.Atoi(raw); err == nil && bitRate > 0 {
+			summary["bitrate"] = fmt.Sprintf("%d kbps", bitRate/1000)
 
-// resolveExpectedBitDepth determines the original bit depth from ffprobe data.
-// For lossless codecs (FLAC, ALAC), bits_per_raw_sample is most reliable.
-// For PCM containers (WAV, AIFF), bits_per_sample is authoritative.
-// For lossy codecs, no meaningful bit depth exists. Defaults to Depth32
-// (matching extraction bit depth, which disables the fake-bit-depth check).
-func resolveExpectedBitDepth(stream *ffprobe.Stream) types.BitDepth {
-	if stream.BitsPerRawSample != "" {
-		if bits, err := strconv.Atoi(stream.BitsPerRawSample); err == nil {
-			if bd, err := toBitDepth(bits); err == nil {
-				return bd
-			}
+			break
 		}
 	}
 
-	if stream.BitsPerSample > 0 {
-		if bd, err := toBitDepth(stream.BitsPerSample); err == nil {
-			return bd
+	for _, raw := range []string{stream.Duration, probeResult.Format.Duration} {
+		if seconds, err := strconv.ParseFloat(raw, 64); err == nil && seconds > 0 {
+			minutes := int(seconds / 60)
+			summary["duration"] = fmt.Sprintf("%d:%06.3f", minutes, seconds-float64(minutes*60))
+
+			break
 		}
 	}
 
-	return types.Depth32
+	return summary
+}
+
+// decodeInput decodes the audio stream of a file through the report pipeline (see report.Decode), for
+// haustorium.Analyze. A non-nil probeResult (precomputed ffprobe output) skips the probe.
+func decodeInput(
+	ctx context.Context,
+	bins binaries.Binaries,
+	filePath string,
+	streamIndex int,
+	probeResult *ffprobe.Result,
+	tagBitDepth bool,
+) (haustorium.ReaderFactory, types.PCMFormat, *ffprobe.Stream, error) {
+	pcmData, format, stream, err := report.Decode(ctx, filePath, probeResult, &report.Options{
+		FFprobe:     bins.FFprobe,
+		FFmpeg:      bins.FFmpeg,
+		Stream:      streamIndex,
+		TagBitDepth: tagBitDepth,
+	})
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	factory := func() (io.Reader, error) {
+		return bytes.NewReader(pcmData), nil
+	}
+
+	return factory, format, stream, nil
 }
@@ -0,0 +1,367 @@
+//nolint:wrapcheck
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/output"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	serveReadHeaderTimeout = 10 * time.Second
+	serveShutdownTimeout   = 30 * time.Second
+	defaultMaxUploadBytes  = 1 << 30 // 1 GiB
+)
+
+var (
+	errInvalidQuery  = errors.New("invalid query parameter")
+	errMaxConcurrent = errors.New("--max-concurrent must be at least 1")
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run an HTTP server that analyzes uploaded audio files (POST /analyze)",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address to listen on (:8080 for every interface)",
+				Value: "127.0.0.1:8080",
+			},
+			&cli.Int64Flag{
+				Name:  "max-upload",
+				Usage: "Maximum accepted request body size in bytes",
+				Value: defaultMaxUploadBytes,
+			},
+			&cli.IntFlag{
+				Name:  "max-concurrent",
+				Usage: "Maximum analyses run at once: further requests wait for one to finish",
+				Value: runtime.NumCPU(),
+			},
+		}, binaries.Flags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Int("max-concurrent") < 1 {
+				return fmt.Errorf("%w: %d", errMaxConcurrent, cmd.Int("max-concurrent"))
+			}
+
+			handler := analyzeHandler(cmd.Int64("max-upload"), cmd.Int("max-concurrent"), binaries.FromCommand(cmd))
+
+			return runServer(ctx, cmd.String("addr"), handler)
+		},
+	}
+}
+
+// analyzeHandler serves POST /analyze, running at most maxConcurrent analyses at once.
+func analyzeHandler(maxUpload int64, maxConcurrent int, bins binaries.Binaries) http.Handler {
+	slots := make(chan struct{}, maxConcurrent)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /analyze", func(writer http.ResponseWriter, req *http.Request) {
+		select {
+		case slots <- struct{}{}:
+			defer func() { <-slots }()
+		case <-req.Context().Done():
+			return
+		}
+
+		handleAnalyze(writer, req, maxUpload, bins)
+	})
+
+	return mux
+}
+
+func runServer(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: serveReadHeaderTimeout,
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx) //nolint:contextcheck // the parent context is already done
+	}()
+
+	slog.Info("listening", "addr", addr)
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// handleAnalyze runs Analyze on the request body.
+//
+// The body is an audio file decoded through ffprobe/ffmpeg, like the process command. When the sample-rate
+// query parameter is set, it is raw PCM instead, described by sample-rate, bit-depth, channels,
+// expected-bit-depth, layout, endian and source-codec, like the analyze command.
+// Other query parameters: checks, source, genre, min-confidence, loudness-target, true-peak-ceiling, stream, debug,
+// raw, and name: the file name the result is reported under (its "object"), since the body comes without one.
+// The analysis stops when the client goes away.
+func handleAnalyze(writer http.ResponseWriter, req *http.Request, maxUpload int64, bins binaries.Binaries) {
+	query := req.URL.Query()
+
+	opts, err := serveOptions(query)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	raw, err := output.ParseRaw(query.Get("raw"))
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	body := http.MaxBytesReader(writer, req.Body, maxUpload)
+
+	var (
+		factory haustorium.ReaderFactory
+		format  types.PCMFormat
+	)
+
+	if query.Has("sample-rate") {
+		var release func()
+
+		factory, format, release, err = rawUpload(body, query)
+		if err == nil {
+			defer release()
+		}
+
+		opts.SourceCodec = query.Get("source-codec")
+	} else {
+		factory, format, opts.SourceCodec, err = fileUpload(req.Context(), bins, body, query)
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), uploadErrorStatus(err))
+
+		return
+	}
+
+	result, err := haustorium.AnalyzeContext(req.Context(), factory, format, opts)
+	if req.Context().Err() != nil {
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("analysis failed: %v", err), uploadErrorStatus(err))
+
+		return
+	}
+
+	var out bytes.Buffer
+
+	show := display{debug: query.Has("debug"), raw: raw}
+	if err := writeResult(&out, query.Get("name"), result, "json", show); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	_, _ = writer.Write(out.Bytes())
+}
+
+func serveOptions(query url.Values) (haustorium.Options, error) {
+	checks, err := parseChecks(query.Get("checks"))
+	if err != nil {
+		return haustorium.Options{}, err
+	}
+
+	sourceName := query.Get("source")
+	if sourceName == "" {
+		sourceName = "digital"
+	}
+
+	source, err := haustorium.ParseSource(sourceName)
+	if err != nil {
+		return haustorium.Options{}, err
+	}
+
+	genre, err := haustorium.ParseGenre(query.Get("genre"))
+	if err != nil {
+		return haustorium.Options{}, err
+	}
+
+	opts := haustorium.OptionsForSource(source)
+	opts.Checks = checks
+	opts.Genre = genre
+
+	if raw := query.Get("min-confidence"); raw != "" {
+		opts.MinConfidence, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return haustorium.Options{}, fmt.Errorf("%w: min-confidence=%q", errInvalidQuery, raw)
+		}
+	}
+
+	if raw := query.Get("loudness-target"); raw != "" {
+		opts.LoudnessTargetLUFS, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return haustorium.Options{}, fmt.Errorf("%w: loudness-target=%q", errInvalidQuery, raw)
+		}
+	}
+
+	if raw := query.Get("true-peak-ceiling"); raw != "" {
+		opts.TruePeakCeilingDb, err = strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return haustorium.Options{}, fmt.Errorf("%w: true-peak-ceiling=%q", errInvalidQuery, raw)
+		}
+	}
+
+	return opts, nil
+}
+
+// rawUpload spools the raw PCM body to a temporary file, read back by every pass of the analysis, instead of
+// holding up to --max-upload bytes in memory. release removes the file, once the analysis is done.
+func rawUpload(
+	body io.Reader,
+	query url.Values,
+) (factory haustorium.ReaderFactory, format types.PCMFormat, release func(), err error) {
+	sampleRate, err := queryInt(query, "sample-rate", 0)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	bitDepth, err := queryInt(query, "bit-depth", 32)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	channels, err := queryInt(query, "channels", 2)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	expectedBitDepth, err := queryInt(query, "expected-bit-depth", 0)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	format, err = buildRawFormat(
+		sampleRate, bitDepth, channels, expectedBitDepth, query.Get("layout"), query.Get("endian"),
+	)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, fmt.Errorf("%w: %w", errInvalidQuery, err)
+	}
+
+	tmp, size, err := spool(body)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	release = func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+
+	// Section readers read at their own offsets: the passes of the analysis share the file.
+	factory = func() (io.Reader, error) {
+		return io.NewSectionReader(tmp, 0, size), nil
+	}
+
+	return factory, format, release, nil
+}
+
+// fileUpload spools the body to a temporary file, since ffprobe needs a seekable path.
+// The stream's codec name is returned with the format.
+func fileUpload(
+	ctx context.Context,
+	bins binaries.Binaries,
+	body io.Reader,
+	query url.Values,
+) (haustorium.ReaderFactory, types.PCMFormat, string, error) {
+	streamIndex, err := queryInt(query, "stream", 0)
+	if err != nil {
+		return nil, types.PCMFormat{}, "", err
+	}
+
+	tmp, _, err := spool(body)
+	if err != nil {
+		return nil, types.PCMFormat{}, "", err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Close(); err != nil {
+		return nil, types.PCMFormat{}, "", fmt.Errorf("writing temp file: %w", err)
+	}
+
+	factory, format, stream, err := decodeInput(ctx, bins, tmp.Name(), streamIndex, nil, false)
+	if err != nil {
+		return nil, types.PCMFormat{}, "", err
+	}
+
+	return factory, format, stream.CodecName, nil
+}
+
+// spool copies the body to a new temporary file, left open, and returns its size. The file is removed on failure.
+func spool(body io.Reader) (*os.File, int64, error) {
+	tmp, err := os.CreateTemp("", "haustorium-upload-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("creating temp file: %w", err)
+	}
+
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+
+		return nil, 0, fmt.Errorf("reading body: %w", err)
+	}
+
+	return tmp, size, nil
+}
+
+func queryInt(query url.Values, key string, fallback int) (int, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return fallback, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s=%q", errInvalidQuery, key, raw)
+	}
+
+	return value, nil
+}
+
+func uploadErrorStatus(err error) int {
+	var maxBytes *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytes):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, errInvalidQuery),
+		errors.Is(err, haustorium.ErrUnsupportedBitDepth),
+		errors.Is(err, haustorium.ErrInsufficientData),
+		errors.Is(err, haustorium.ErrFormatMismatch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
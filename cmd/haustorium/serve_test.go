@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/binaries"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+// rawQuery describes the default testutil fixture (16-bit stereo at 44.1 kHz) as raw PCM.
+const rawQuery = "/analyze?sample-rate=44100&bit-depth=16&channels=2"
+
+func serveRequest(t *testing.T, req *http.Request, maxUpload int64) *httptest.ResponseRecorder {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	analyzeHandler(maxUpload, 1, binaries.Binaries{}).ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestServeRawPCM(t *testing.T) {
+	t.Parallel()
+
+	body := testutil.Render(testutil.Spec{Defects: []testutil.Defect{{Kind: testutil.DefectClip, AtSec: 1}}})
+	req := httptest.NewRequest(http.MethodPost, rawQuery+"&checks=clipping&name=track.flac", bytes.NewReader(body))
+
+	recorder := serveRequest(t, req, defaultMaxUploadBytes)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected a JSON response, got %q", contentType)
+	}
+
+	// One result, named after the name parameter, with the friendly output of the process command.
+	var results []struct {
+		Object string         `json:"object"`
+		Meta   map[string]any `json:"meta"`
+	}
+
+	if err := json.Unmarshal(recorder.Body.Bytes(), &results); err != nil {
+		t.Fatalf("expected a JSON array of results: %v\n%s", err, recorder.Body)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected one result, got %d", len(results))
+	}
+
+	if results[0].Object != "track.flac" {
+		t.Errorf("expected the result under the name parameter, got %q", results[0].Object)
+	}
+
+	if _, ok := results[0].Meta["summary"]; !ok {
+		t.Errorf("expected a summary in the result: %v", results[0].Meta)
+	}
+}
+
+func TestServeBadQuery(t *testing.T) {
+	t.Parallel()
+
+	for _, query := range []string{
+		"/analyze?sample-rate=fast",
+		"/analyze?sample-rate=44100&bit-depth=12&channels=2",
+		rawQuery + "&min-confidence=high",
+		rawQuery + "&checks=nonsense",
+	} {
+		req := httptest.NewRequest(http.MethodPost, query, bytes.NewReader(testutil.Render(testutil.Spec{})))
+
+		if recorder := serveRequest(t, req, defaultMaxUploadBytes); recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d: %s", query, recorder.Code, recorder.Body)
+		}
+	}
+}
+
+func TestServeOversizedBody(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, rawQuery, bytes.NewReader(testutil.Render(testutil.Spec{})))
+
+	if recorder := serveRequest(t, req, 1024); recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestServeCanceledRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, rawQuery,
+		bytes.NewReader(testutil.Render(testutil.Spec{})))
+
+	// The client is gone: nothing is analyzed, nor written.
+	if recorder := serveRequest(t, req, defaultMaxUploadBytes); recorder.Body.Len() != 0 {
+		t.Fatalf("expected no response to a canceled request, got %d: %s", recorder.Code, recorder.Body)
+	}
+}
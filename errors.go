@@ -0,0 +1,24 @@
+package haustorium
+
+import (
+	"errors"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// Errors returned by Analyze. Read failures from the reader are wrapped in fault.ErrReadFailure.
+var (
+	// ErrUnsupportedBitDepth is returned when the PCM bit depth is not 16, 24 or 32.
+	ErrUnsupportedBitDepth = types.ErrUnsupportedBitDepth
+	// ErrInsufficientData is returned when the input does not hold a single complete frame.
+	ErrInsufficientData = types.ErrInsufficientData
+	// ErrFormatMismatch is returned when format parameters are inconsistent (no channels, no sample rate,
+	// or an expected bit depth higher than the PCM bit depth).
+	ErrFormatMismatch = types.ErrFormatMismatch
+)
+
+// ErrStreamTooLong is returned by AnalyzeStream, on its last event, when the stream exceeds Options.StreamMaxBytes.
+var ErrStreamTooLong = errors.New("stream too long to analyze whole")
+
+// ErrInvalidPCMHeader is returned by ParsePCMHeader when a haustorium-pcm header is malformed or unsupported.
+var ErrInvalidPCMHeader = errors.New("invalid haustorium-pcm header")
@@ -7,6 +7,7 @@ import (
 
 	"github.com/farcloser/primordium/fault"
 
+	"github.com/farcloser/haustorium/internal/audit/shared"
 	"github.com/farcloser/haustorium/internal/types"
 )
 
@@ -15,17 +16,30 @@ const (
 	genuineMask32 = 0xFFFF
 )
 
+// Options tune Authenticity.
+type Options struct {
+	// Windowed also measures the effective bit depth of every second of the track, to find mixed-resolution
+	// assemblies: genuine sections next to zero-padded ones, which the whole-track measurement reports as
+	// genuine. The whole track is then read, without stopping at the first genuine sample.
+	Windowed bool
+}
+
 // Authenticity detects if audio is zero-padded to a higher bit depth.
 // A "24-bit" file that's really 16-bit will have lower 8 bits always zero.
-func Authenticity(reader io.Reader, format types.PCMFormat) (*types.BitDepthAuthenticity, error) {
+func Authenticity(reader io.Reader, format types.PCMFormat, opts Options) (*types.BitDepthAuthenticity, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	claimed := format.ExpectedBitDepth
 
 	if format.BitDepth == types.Depth16 {
 		return &types.BitDepthAuthenticity{
-			Claimed:   claimed,
-			Effective: claimed,
-			IsPadded:  false,
-			Samples:   0,
+			Claimed:               claimed,
+			Effective:             claimed,
+			IsPadded:              false,
+			Samples:               0,
+			ConsistentAcrossTrack: true,
 		}, nil
 	}
 
@@ -46,11 +60,17 @@ func Authenticity(reader io.Reader, format types.PCMFormat) (*types.BitDepthAuth
 		genuineMask = genuineMask32
 	}
 
+	var sections *sectionTracker
+	if opts.Windowed {
+		sections = newSectionTracker(format, claimed)
+	}
+
+	reader = shared.NewFrameReader(reader, frameSize)
+
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
-			completeSamples := (n / bytesPerSample) * bytesPerSample
-			data := buf[:completeSamples]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth24:
@@ -58,21 +78,31 @@ func Authenticity(reader io.Reader, format types.PCMFormat) (*types.BitDepthAuth
 					sample := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16
 					usedBits |= sample
 					samples++
+
+					if sections != nil {
+						sections.add(sample)
+					}
 				}
 			case types.Depth32:
 				for i := 0; i < len(data); i += 4 {
-					usedBits |= binary.LittleEndian.Uint32(data[i:])
+					sample := binary.LittleEndian.Uint32(data[i:])
+					usedBits |= sample
 					samples++
+
+					if sections != nil {
+						sections.add(sample)
+					}
 				}
 			default:
 			}
 
-			if usedBits&genuineMask == genuineMask {
+			if sections == nil && usedBits&genuineMask == genuineMask {
 				return &types.BitDepthAuthenticity{
-					Claimed:   claimed,
-					Effective: format.BitDepth,
-					IsPadded:  false,
-					Samples:   samples,
+					Claimed:               claimed,
+					Effective:             format.BitDepth,
+					IsPadded:              false,
+					Samples:               samples,
+					ConsistentAcrossTrack: true,
 				}, nil
 			}
 		}
@@ -88,12 +118,81 @@ func Authenticity(reader io.Reader, format types.PCMFormat) (*types.BitDepthAuth
 
 	effective := effectiveBitDepth(usedBits, format.BitDepth)
 
-	return &types.BitDepthAuthenticity{
-		Claimed:   claimed,
-		Effective: effective,
-		IsPadded:  effective < claimed,
-		Samples:   samples,
-	}, nil
+	result := &types.BitDepthAuthenticity{
+		Claimed:               claimed,
+		Effective:             effective,
+		IsPadded:              effective < claimed,
+		Samples:               samples,
+		ConsistentAcrossTrack: true,
+	}
+
+	if sections != nil {
+		sections.fill(result)
+	}
+
+	return result, nil
+}
+
+// sectionTracker measures the effective bit depth of every second of the track. Digital silence uses no bits
+// at all, and is left out.
+type sectionTracker struct {
+	depth   types.BitDepth
+	claimed types.BitDepth
+	size    int
+	samples int
+	used    uint32
+
+	index    uint64 // windows read, silent ones included
+	windows  uint64
+	padded   uint64
+	lowest   types.BitDepth
+	firstSec float64 // start of the first padded window
+}
+
+func newSectionTracker(format types.PCMFormat, claimed types.BitDepth) *sectionTracker {
+	return &sectionTracker{
+		depth:   format.BitDepth,
+		claimed: claimed,
+		size:    max(format.SampleRate*int(format.Channels), 1), //nolint:gosec // channel count is small
+		lowest:  claimed,
+	}
+}
+
+func (t *sectionTracker) add(sample uint32) {
+	t.used |= sample
+
+	t.samples++
+	if t.samples < t.size {
+		return
+	}
+
+	if t.used != 0 {
+		if effective := effectiveBitDepth(t.used, t.depth); effective < t.claimed {
+			if t.padded == 0 {
+				t.firstSec = float64(t.index)
+			}
+
+			t.padded++
+			t.lowest = min(t.lowest, effective)
+		}
+
+		t.windows++
+	}
+
+	t.index++
+	t.samples, t.used = 0, 0
+}
+
+// fill sets the section counts. The track is inconsistent when some of its sections are padded, but not all.
+func (t *sectionTracker) fill(result *types.BitDepthAuthenticity) {
+	result.Windows = t.windows
+	result.PaddedWindows = t.padded
+	result.ConsistentAcrossTrack = t.padded == 0 || t.padded == t.windows
+
+	if !result.ConsistentAcrossTrack {
+		result.PaddedEffective = t.lowest
+		result.FirstPaddedSec = t.firstSec
+	}
 }
 
 func effectiveBitDepth(usedBits uint32, claimed types.BitDepth) types.BitDepth {
@@ -0,0 +1,26 @@
+package bitdepth_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/bitdepth"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkAuthenticity(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := bitdepth.Authenticity(reader, format, bitdepth.Options{})
+
+		return err
+	})
+}
+
+func BenchmarkDither(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := bitdepth.Dither(reader, format)
+
+		return err
+	})
+}
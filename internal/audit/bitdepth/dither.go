@@ -0,0 +1,208 @@
+package bitdepth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/farcloser/primordium/fault"
+
+	"github.com/farcloser/haustorium/internal/audit/shared"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	ditherWindowMs      = 50
+	ditherQuietMinLSB   = 0.5  // window RMS below this (in LSBs) is digital silence, not a quiet passage
+	ditherQuietMaxLSB   = 16.0 // window RMS above this (in LSBs) is loud enough to self-dither (~-66 dBFS at 16-bit)
+	ditherMinWindows    = 20   // need ~1s of quiet material before drawing a conclusion
+	ditherHoldThreshold = 0.75 // fraction of repeated samples above which quantization is signal-correlated
+	fadeoutMinWindows   = 10   // a fade-out's quiet tail must last 0.5 s to be judged
+)
+
+// ditherWindow is the measurement of a 50 ms window, kept for the fade-out analysis.
+type ditherWindow struct {
+	rms   float64 // in LSBs of the expected bit depth
+	holds uint64
+	steps uint64
+}
+
+// Dither looks for correlated quantization distortion in quiet passages, the signature of a bit-depth reduction
+// performed without dither.
+//
+// In quiet (but not silent) windows, the signal only spans a handful of LSBs at the expected bit depth.
+// With dither (or any analog noise above the LSB), consecutive samples keep toggling between levels.
+// Without it, the quantization error tracks the signal: a low-level tone turns into a staircase, and consecutive
+// samples repeat the same level for long stretches. The fraction of such repeats is the hold ratio.
+//
+// The fade-out is judged on its own: where undithered quantization is most audible, as zipper noise breaking up
+// the tail, and where the whole-track ratio can miss it, diluted by the other quiet passages, or under its second
+// of quiet material.
+func Dither(reader io.Reader, format types.PCMFormat) (*types.DitherResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	bytesPerSample := int(format.BitDepth / 8)         //nolint:gosec // bit depth and channel count are small constants
+	frameSize := bytesPerSample * int(format.Channels) //nolint:gosec // bit depth and channel count are small constants
+	numChannels := int(format.Channels)                //nolint:gosec // bit depth and channel count are small constants
+	buf := make([]byte, frameSize*4096)
+
+	// Quantize to the expected (source) bit depth, so that a 16-bit source decoded to 32-bit is judged in 16-bit LSBs.
+	expected := format.ExpectedBitDepth
+	if expected == 0 || expected > format.BitDepth {
+		expected = format.BitDepth
+	}
+
+	shift := format.BitDepth - expected
+	windowFrames := max(format.SampleRate*ditherWindowMs/1000, 1)
+
+	var (
+		frames       uint64
+		quietWindows uint64
+		totalHolds   uint64
+		totalSteps   uint64
+		windowSumSq  float64
+		windowHolds  uint64
+		windowSteps  uint64
+		windowCount  int
+		windows      []ditherWindow
+	)
+
+	prev := make([]int32, numChannels)
+	hasPrev := make([]bool, numChannels)
+
+	processSample := func(channel int, raw int32) {
+		level := raw >> shift
+
+		if hasPrev[channel] {
+			windowSteps++
+
+			if level == prev[channel] {
+				windowHolds++
+			}
+		}
+
+		prev[channel] = level
+		hasPrev[channel] = true
+		windowSumSq += float64(level) * float64(level)
+	}
+
+	processWindow := func() {
+		if windowCount == 0 {
+			return
+		}
+
+		rms := math.Sqrt(windowSumSq / float64(windowCount*numChannels))
+		windows = append(windows, ditherWindow{rms: rms, holds: windowHolds, steps: windowSteps})
+
+		if rms >= ditherQuietMinLSB && rms <= ditherQuietMaxLSB && windowSteps > 0 {
+			quietWindows++
+			totalHolds += windowHolds
+			totalSteps += windowSteps
+		}
+
+		windowSumSq = 0
+		windowHolds = 0
+		windowSteps = 0
+		windowCount = 0
+	}
+
+	reader = shared.NewFrameReader(reader, frameSize)
+
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+
+			for i := 0; i < len(data); i += frameSize {
+				for ch := range numChannels {
+					offset := i + ch*bytesPerSample
+
+					switch format.BitDepth {
+					case types.Depth16:
+						processSample(ch, int32(int16(binary.LittleEndian.Uint16(data[offset:]))))
+					case types.Depth24:
+						raw := int32(data[offset]) | int32(data[offset+1])<<8 | int32(data[offset+2])<<16
+						if raw&0x800000 != 0 {
+							raw |= ^0xFFFFFF
+						}
+
+						processSample(ch, raw)
+					case types.Depth32:
+						processSample(ch, int32(binary.LittleEndian.Uint32(data[offset:])))
+					default:
+					}
+				}
+
+				frames++
+				windowCount++
+
+				if windowCount >= windowFrames {
+					processWindow()
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+	}
+
+	processWindow()
+
+	var holdRatio float64
+	if totalSteps > 0 {
+		holdRatio = float64(totalHolds) / float64(totalSteps)
+	}
+
+	fadeWindows, fadeHoldRatio := fadeoutTail(windows)
+
+	return &types.DitherResult{
+		UnditheredLikely:          quietWindows >= ditherMinWindows && holdRatio > ditherHoldThreshold,
+		HoldRatio:                 holdRatio,
+		QuietWindows:              quietWindows,
+		QuietSec:                  float64(quietWindows*ditherWindowMs) / 1000,
+		FadeoutQuantizationLikely: fadeWindows >= fadeoutMinWindows && fadeHoldRatio > ditherHoldThreshold,
+		FadeoutHoldRatio:          fadeHoldRatio,
+		FadeoutSec:                float64(fadeWindows*ditherWindowMs) / 1000,
+		Frames:                    frames,
+	}, nil
+}
+
+// fadeoutTail finds the quiet tail of the fade-out: the quiet windows running up to the last window with signal,
+// trailing digital silence left out, led by louder music. It returns their count and hold ratio; none when the track
+// ends loud (a hard ending, or a cut), or is quiet throughout (no fade).
+func fadeoutTail(windows []ditherWindow) (uint64, float64) {
+	end := len(windows)
+	for end > 0 && windows[end-1].rms < ditherQuietMinLSB {
+		end--
+	}
+
+	start := end
+	for start > 0 && windows[start-1].rms >= ditherQuietMinLSB && windows[start-1].rms <= ditherQuietMaxLSB {
+		start--
+	}
+
+	if start == 0 || start == end {
+		return 0, 0
+	}
+
+	var holds, steps uint64
+
+	for _, window := range windows[start:end] {
+		holds += window.holds
+		steps += window.steps
+	}
+
+	if steps == 0 {
+		return 0, 0
+	}
+
+	return uint64(end - start), float64(holds) / float64(steps) //nolint:gosec // non-negative
+}
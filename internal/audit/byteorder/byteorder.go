@@ -0,0 +1,141 @@
+// Package byteorder tells byte-swapped PCM from PCM read in its own byte order.
+package byteorder
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/farcloser/primordium/fault"
+
+	"github.com/farcloser/haustorium/internal/audit/shared"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	scanSec        = 30  // leading audio compared, at most
+	maxNativeCorr  = 0.2 // lag-1 correlation under which the samples as read look like white noise
+	minSwappedCorr = 0.6 // lag-1 correlation over which the swapped samples look like audio
+)
+
+// Swapped reports whether the start of the stream reads as noise, while the same bytes reversed within each
+// sample read as audio. Audio is strongly correlated from one sample to the next; byte-swapped audio puts its low
+// bits on top, which reads as full-scale white noise. Genuine noise stays uncorrelated both ways, and silence
+// says nothing: neither is flagged.
+func Swapped(reader io.Reader, format types.PCMFormat) (bool, error) {
+	if err := format.Validate(); err != nil {
+		return false, err
+	}
+
+	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
+	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
+	frameSize := bytesPerSample * numChannels
+
+	limit := int64(format.SampleRate) * scanSec * int64(frameSize)
+	buf := make([]byte, frameSize*4096)
+
+	var maxVal float64
+
+	switch format.BitDepth {
+	case types.Depth16:
+		maxVal = shared.MaxValue16
+	case types.Depth24:
+		maxVal = shared.MaxValue24
+	case types.Depth32:
+		maxVal = shared.MaxValue32
+	default:
+	}
+
+	native := newCorrelation(numChannels)
+	swapped := newCorrelation(numChannels)
+
+	frames := shared.NewFrameReader(io.LimitReader(reader, limit), frameSize)
+
+	for {
+		n, err := frames.Read(buf)
+
+		// Reads hold whole frames: the first sample of each is on the first channel.
+		for i := 0; i < n; i += bytesPerSample {
+			channel := (i / bytesPerSample) % numChannels
+			value := decode(buf[i:i+bytesPerSample], format.BitDepth)
+
+			native.add(channel, float64(value)/maxVal)
+			swapped.add(channel, float64(swap(value, format.BitDepth))/maxVal)
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+	}
+
+	return native.lag1() < maxNativeCorr && swapped.lag1() > minSwappedCorr, nil
+}
+
+func decode(sample []byte, depth types.BitDepth) int32 {
+	switch depth {
+	case types.Depth16:
+		return int32(int16(binary.LittleEndian.Uint16(sample)))
+	case types.Depth24:
+		raw := int32(sample[0]) | int32(sample[1])<<8 | int32(sample[2])<<16
+		if raw&0x800000 != 0 {
+			raw |= ^0xFFFFFF
+		}
+
+		return raw
+	case types.Depth32:
+		return int32(binary.LittleEndian.Uint32(sample)) //nolint:gosec // two's complement reinterpretation
+	default:
+		return 0
+	}
+}
+
+// swap returns the sample decoded from its bytes in reverse order.
+func swap(value int32, depth types.BitDepth) int32 {
+	switch depth {
+	case types.Depth16:
+		return int32(int16(bits.ReverseBytes16(uint16(value)))) //nolint:gosec // two's complement reinterpretation
+	case types.Depth24:
+		raw := uint32(value) & 0xFFFFFF //nolint:gosec // two's complement reinterpretation
+		raw = raw>>16 | raw&0xFF00 | (raw&0xFF)<<16
+
+		if raw&0x800000 != 0 {
+			raw |= 0xFF000000
+		}
+
+		return int32(raw) //nolint:gosec // two's complement reinterpretation
+	case types.Depth32:
+		return int32(bits.ReverseBytes32(uint32(value))) //nolint:gosec // two's complement reinterpretation
+	default:
+		return 0
+	}
+}
+
+// correlation accumulates the lag-1 autocorrelation, per channel, over the whole stream.
+type correlation struct {
+	previous []float64
+	energy   float64
+	product  float64
+}
+
+func newCorrelation(numChannels int) *correlation {
+	return &correlation{previous: make([]float64, numChannels)}
+}
+
+func (c *correlation) add(channel int, sample float64) {
+	c.energy += sample * sample
+	c.product += sample * c.previous[channel]
+	c.previous[channel] = sample
+}
+
+func (c *correlation) lag1() float64 {
+	if c.energy == 0 {
+		return 0
+	}
+
+	return c.product / c.energy
+}
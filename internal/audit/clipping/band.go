@@ -0,0 +1,141 @@
+package clipping
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	bandBlockSize    = 1024 // ~43 Hz bins at 44.1 kHz, ~23 ms around each clipping event
+	bandMaxAnalyses  = 4000 // spectra taken, at most; later events are counted but not analyzed
+	bandMinAnalyses  = 10   // spectra needed to tell a band
+	bandConcentrated = 0.5  // share of the energy a band must hold for the clipping to concentrate there
+	bandCount        = int(types.BandTreble)
+)
+
+// bandTracker tells the frequency band driving the clipping. Around each clipping event (per channel), it takes a
+// windowed spectrum of the surrounding samples, centered on the event, and accumulates the share of its energy in
+// each coarse band. Clipping driven by the kick holds its energy in the bass; clipping cymbals, in the treble.
+type bandTracker struct {
+	fft     *fourier.FFT
+	window  []float64
+	binBand []int // band index, per bin; DC is left out (-1)
+
+	rings   [][]float64 // last bandBlockSize samples, per channel
+	pos     []int
+	pending []int // samples to wait before analyzing (0 = nothing pending), per channel
+
+	block    []float64
+	coeffs   []complex128
+	shares   [bandCount]float64 // accumulated energy share, per band
+	analyses int
+}
+
+func newBandTracker(sampleRate, numChannels int) *bandTracker {
+	window := make([]float64, bandBlockSize)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(bandBlockSize-1)))
+	}
+
+	binBand := make([]int, bandBlockSize/2+1)
+	binBand[0] = -1
+
+	for bin := 1; bin < len(binBand); bin++ {
+		binBand[bin] = int(types.BandOf(float64(bin*sampleRate)/bandBlockSize) - types.BandBass)
+	}
+
+	rings := make([][]float64, numChannels)
+	for channel := range rings {
+		rings[channel] = make([]float64, bandBlockSize)
+	}
+
+	return &bandTracker{
+		fft:     fourier.NewFFT(bandBlockSize),
+		window:  window,
+		binBand: binBand,
+		rings:   rings,
+		pos:     make([]int, numChannels),
+		pending: make([]int, numChannels),
+		block:   make([]float64, bandBlockSize),
+	}
+}
+
+func (t *bandTracker) sample(channel int, sample float64) {
+	t.rings[channel][t.pos[channel]] = sample
+	t.pos[channel] = (t.pos[channel] + 1) % bandBlockSize
+
+	if t.pending[channel] == 0 {
+		return
+	}
+
+	t.pending[channel]--
+	if t.pending[channel] == 0 {
+		t.analyze(channel)
+	}
+}
+
+// clip marks a clipping event of run samples, just ended on the channel: its spectrum is taken once the run sits
+// in the middle of the ring. Events while one is pending belong to the same block.
+func (t *bandTracker) clip(channel int, run uint64) {
+	if t.pending[channel] > 0 || t.analyses >= bandMaxAnalyses {
+		return
+	}
+
+	t.pending[channel] = max(bandBlockSize/2-int(min(run, bandBlockSize))/2, 1) //nolint:gosec // bounded above
+}
+
+func (t *bandTracker) analyze(channel int) {
+	ring := t.rings[channel]
+	for i := range t.block {
+		t.block[i] = ring[(t.pos[channel]+i)%bandBlockSize] * t.window[i]
+	}
+
+	t.coeffs = t.fft.Coefficients(t.coeffs, t.block)
+
+	var (
+		energy [bandCount]float64
+		total  float64
+	)
+
+	for bin := 1; bin < len(t.coeffs); bin++ {
+		power := real(t.coeffs[bin] * cmplx.Conj(t.coeffs[bin]))
+		energy[t.binBand[bin]] += power
+		total += power
+	}
+
+	if total == 0 {
+		return
+	}
+
+	for band := range energy {
+		t.shares[band] += energy[band] / total
+	}
+
+	t.analyses++
+}
+
+// band returns the band holding most of the energy around the clipping events, and its share (0-1):
+// BandBroadband when none holds half of it, BandNone with too few events.
+func (t *bandTracker) band() (types.FrequencyBand, float64) {
+	if t.analyses < bandMinAnalyses {
+		return types.BandNone, 0
+	}
+
+	peak := 0
+	for band := range t.shares {
+		if t.shares[band] > t.shares[peak] {
+			peak = band
+		}
+	}
+
+	share := t.shares[peak] / float64(t.analyses)
+	if share < bandConcentrated {
+		return types.BandBroadband, share
+	}
+
+	return types.BandBass + types.FrequencyBand(peak), share //nolint:gosec // a band index
+}
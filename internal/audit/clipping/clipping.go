@@ -4,9 +4,11 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/farcloser/primordium/fault"
 
+	"github.com/farcloser/haustorium/internal/audit/shared"
 	"github.com/farcloser/haustorium/internal/types"
 )
 
@@ -19,7 +21,188 @@ const (
 	min32 = -1 << 31  // -2147483648
 )
 
-func Detect(r io.Reader, format types.PCMFormat) (*types.ClippingDetection, error) {
+const (
+	longRunMs             = 1.0  // runs longer than this are beyond declipping
+	restorableMaxFraction = 1e-3 // clipped share of all samples, at most, for restorable clipping
+	restorableMaxLong     = 0.05 // long-run share of the clipped samples, at most, for restorable clipping
+	hopelessMinFraction   = 0.02 // clipped share of all samples over which clipping is unrecoverable
+	hopelessMinLong       = 0.5  // long-run share of the clipped samples over which clipping is unrecoverable
+)
+
+// Options configures plateau detection. Full-scale clipping is always detected.
+// Zero values stand for the defaults: a PlateauMinLevelDb of 0 dBFS cannot be asked for, no plateau is louder.
+type Options struct {
+	Plateaus          bool    // also detect flat runs below full scale (clipped, then gain-reduced)
+	PlateauMinRun     uint64  // identical consecutive samples needed for a plateau (default 4)
+	PlateauMinLevelDb float64 // plateaus quieter than this are ignored (default -6 dBFS)
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Plateaus:          false,
+		PlateauMinRun:     4,
+		PlateauMinLevelDb: -6,
+	}
+}
+
+// detector holds per-channel run state. Full-scale runs and plateau runs are tracked independently:
+// a full-scale run is never counted as a plateau.
+type detector struct {
+	result      *types.ClippingDetection
+	maxVal      int32
+	minVal      int32
+	plateaus    bool
+	minRun      uint64
+	longRun     uint64 // clipped run length (samples) over which a run counts as long
+	minLevel    int64  // absolute sample value below which plateaus are ignored
+	levelShift  types.BitDepth
+	consecutive []uint64
+	positive    []bool // polarity of the current full-scale run, per channel
+	prev        []int32
+	run         []uint64
+	levels      map[int64]uint64 // plateau count per absolute level, in LSBs of the expected bit depth
+	fullScale   float64
+	bands       *bandTracker
+}
+
+func (d *detector) process(channel int, sample int32) {
+	d.result.Samples++
+	d.bands.sample(channel, float64(sample)/d.fullScale)
+
+	if sample == d.maxVal || sample == d.minVal {
+		if d.consecutive[channel] == 0 {
+			d.positive[channel] = sample == d.maxVal
+		}
+
+		d.consecutive[channel]++
+	} else {
+		d.flushClip(channel)
+	}
+
+	if !d.plateaus {
+		return
+	}
+
+	if sample == d.prev[channel] {
+		d.run[channel]++
+
+		return
+	}
+
+	d.flushPlateau(channel)
+	d.prev[channel] = sample
+	d.run[channel] = 1
+}
+
+func (d *detector) flushClip(channel int) {
+	if d.consecutive[channel] >= 2 {
+		d.result.Channels[channel].Events++
+		if d.positive[channel] {
+			d.result.Channels[channel].PositiveEvents++
+		} else {
+			d.result.Channels[channel].NegativeEvents++
+		}
+
+		d.result.Channels[channel].ClippedSamples += d.consecutive[channel]
+		if d.consecutive[channel] > d.result.Channels[channel].LongestRun {
+			d.result.Channels[channel].LongestRun = d.consecutive[channel]
+		}
+
+		d.result.Events++
+		d.bands.clip(channel, d.consecutive[channel])
+
+		d.result.ClippedSamples += d.consecutive[channel]
+		if d.consecutive[channel] > d.longRun {
+			d.result.LongRunSamples += d.consecutive[channel]
+		}
+
+		if d.consecutive[channel] > d.result.LongestRun {
+			d.result.LongestRun = d.consecutive[channel]
+		}
+	}
+
+	d.consecutive[channel] = 0
+}
+
+func (d *detector) flushPlateau(channel int) {
+	value := d.prev[channel]
+
+	if d.run[channel] < d.minRun || value == d.maxVal || value == d.minVal {
+		return
+	}
+
+	level := int64(value)
+	if level < 0 {
+		level = -level
+	}
+
+	if level < d.minLevel {
+		return
+	}
+
+	d.result.PlateauEvents++
+	d.result.PlateauSamples += d.run[channel]
+	d.levels[level>>d.levelShift]++
+}
+
+// finalize picks the dominant plateau level. Levels one LSB apart are merged, since gain-reducing
+// +max and -min rarely rounds to the exact same magnitude.
+func (d *detector) finalize(fullScale float64) {
+	var (
+		bestLevel int64
+		bestCount uint64
+	)
+
+	for level := range d.levels {
+		count := d.levels[level-1] + d.levels[level] + d.levels[level+1]
+		if count > bestCount || (count == bestCount && level > bestLevel) {
+			bestLevel = level
+			bestCount = count
+		}
+	}
+
+	if bestCount == 0 {
+		return
+	}
+
+	d.result.PlateauLevelEvents = bestCount
+	d.result.PlateauLevelDb = 20 * math.Log10(float64(bestLevel<<d.levelShift)/fullScale)
+}
+
+// recoverability grades the full-scale clipping from the clipped share of the samples, and the share of the
+// clipped samples in long runs.
+func recoverability(result *types.ClippingDetection) types.Recoverability {
+	if result.ClippedSamples == 0 || result.Samples == 0 {
+		return types.RecoverabilityNone
+	}
+
+	fraction := float64(result.ClippedSamples) / float64(result.Samples)
+	long := float64(result.LongRunSamples) / float64(result.ClippedSamples)
+
+	switch {
+	case fraction > hopelessMinFraction || long > hopelessMinLong:
+		return types.Unrecoverable
+	case fraction < restorableMaxFraction && long < restorableMaxLong:
+		return types.Restorable
+	default:
+		return types.PartiallyRestorable
+	}
+}
+
+func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.ClippingDetection, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultOptions()
+	if opts.PlateauMinRun == 0 {
+		opts.PlateauMinRun = defaults.PlateauMinRun
+	}
+
+	if opts.PlateauMinLevelDb == 0 {
+		opts.PlateauMinLevelDb = defaults.PlateauMinLevelDb
+	}
+
 	bytesPerSample := int(format.BitDepth / 8)         //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * int(format.Channels) //nolint:gosec // bit depth and channel count are small constants
 	buf := make([]byte, frameSize*4096)
@@ -28,112 +211,69 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.ClippingDetection, erro
 	result := &types.ClippingDetection{
 		Channels: make([]types.ChannelClipping, numChannels),
 	}
-	consecutive := make([]uint64, numChannels)
+
+	det := &detector{
+		result:      result,
+		plateaus:    opts.Plateaus,
+		minRun:      opts.PlateauMinRun,
+		longRun:     uint64(float64(format.SampleRate) * longRunMs / 1000),
+		consecutive: make([]uint64, numChannels),
+		positive:    make([]bool, numChannels),
+		prev:        make([]int32, numChannels),
+		run:         make([]uint64, numChannels),
+		levels:      map[int64]uint64{},
+	}
+
+	switch format.BitDepth {
+	case types.Depth16:
+		det.maxVal, det.minVal = max16, min16
+	case types.Depth24:
+		det.maxVal, det.minVal = max24, min24
+	case types.Depth32:
+		det.maxVal, det.minVal = max32, min32
+	default:
+	}
+
+	fullScale := -float64(det.minVal)
+	det.fullScale = fullScale
+	det.bands = newBandTracker(format.SampleRate, numChannels)
+	det.minLevel = int64(fullScale * math.Pow(10, opts.PlateauMinLevelDb/20))
+
+	// Plateau levels are bucketed at the source bit depth, so a 16-bit source decoded to 32-bit merges neighbours
+	// one 16-bit LSB apart.
+	if format.ExpectedBitDepth > 0 && format.ExpectedBitDepth < format.BitDepth {
+		det.levelShift = format.BitDepth - format.ExpectedBitDepth
+	}
 
 	var sampleIndex int
 
+	r = shared.NewFrameReader(r, frameSize)
+
 	for {
 		n, err := r.Read(buf)
 		if n > 0 {
-			completeSamples := (n / bytesPerSample) * bytesPerSample
-			data := buf[:completeSamples]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
 				for i := 0; i < len(data); i += 2 {
-					channel := sampleIndex % numChannels
-					sample := int16(
-						binary.LittleEndian.Uint16(data[i:]),
-					)
-					result.Samples++
+					det.process(sampleIndex%numChannels, int32(int16(binary.LittleEndian.Uint16(data[i:]))))
 					sampleIndex++
-
-					if sample == max16 || sample == min16 {
-						consecutive[channel]++
-					} else {
-						if consecutive[channel] >= 2 {
-							result.Channels[channel].Events++
-
-							result.Channels[channel].ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.Channels[channel].LongestRun {
-								result.Channels[channel].LongestRun = consecutive[channel]
-							}
-
-							result.Events++
-
-							result.ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.LongestRun {
-								result.LongestRun = consecutive[channel]
-							}
-						}
-
-						consecutive[channel] = 0
-					}
 				}
 			case types.Depth24:
 				for i := 0; i < len(data); i += 3 {
-					channel := sampleIndex % numChannels
-
 					sample := int32(data[i]) | int32(data[i+1])<<8 | int32(data[i+2])<<16
 					if sample&0x800000 != 0 {
 						sample |= ^0xFFFFFF
 					}
 
-					result.Samples++
+					det.process(sampleIndex%numChannels, sample)
 					sampleIndex++
-
-					if sample == max24 || sample == min24 {
-						consecutive[channel]++
-					} else {
-						if consecutive[channel] >= 2 {
-							result.Channels[channel].Events++
-
-							result.Channels[channel].ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.Channels[channel].LongestRun {
-								result.Channels[channel].LongestRun = consecutive[channel]
-							}
-
-							result.Events++
-
-							result.ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.LongestRun {
-								result.LongestRun = consecutive[channel]
-							}
-						}
-
-						consecutive[channel] = 0
-					}
 				}
 			case types.Depth32:
 				for i := 0; i < len(data); i += 4 {
-					channel := sampleIndex % numChannels
-					sample := int32(
-						binary.LittleEndian.Uint32(data[i:]),
-					)
-					result.Samples++
+					det.process(sampleIndex%numChannels, int32(binary.LittleEndian.Uint32(data[i:])))
 					sampleIndex++
-
-					if sample == max32 || sample == min32 {
-						consecutive[channel]++
-					} else {
-						if consecutive[channel] >= 2 {
-							result.Channels[channel].Events++
-
-							result.Channels[channel].ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.Channels[channel].LongestRun {
-								result.Channels[channel].LongestRun = consecutive[channel]
-							}
-
-							result.Events++
-
-							result.ClippedSamples += consecutive[channel]
-							if consecutive[channel] > result.LongestRun {
-								result.LongestRun = consecutive[channel]
-							}
-						}
-
-						consecutive[channel] = 0
-					}
 				}
 			default:
 			}
@@ -148,24 +288,21 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.ClippingDetection, erro
 		}
 	}
 
-	// Flush trailing clips for all channels
+	// Flush trailing clips and plateaus for all channels
 	for channel := range numChannels {
-		if consecutive[channel] >= 2 {
-			result.Channels[channel].Events++
-
-			result.Channels[channel].ClippedSamples += consecutive[channel]
-			if consecutive[channel] > result.Channels[channel].LongestRun {
-				result.Channels[channel].LongestRun = consecutive[channel]
-			}
-
-			result.Events++
+		det.flushClip(channel)
 
-			result.ClippedSamples += consecutive[channel]
-			if consecutive[channel] > result.LongestRun {
-				result.LongestRun = consecutive[channel]
-			}
+		if det.plateaus {
+			det.flushPlateau(channel)
 		}
 	}
 
+	if det.plateaus {
+		det.finalize(fullScale)
+	}
+
+	result.Recoverability = recoverability(result)
+	result.Band, result.BandShare = det.bands.band()
+
 	return result, nil
 }
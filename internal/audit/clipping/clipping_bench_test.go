@@ -0,0 +1,18 @@
+package clipping_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/clipping"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkDetect(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := clipping.Detect(reader, format, clipping.Options{Plateaus: true})
+
+		return err
+	})
+}
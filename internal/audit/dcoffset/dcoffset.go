@@ -13,6 +13,10 @@ import (
 )
 
 func Detect(reader io.Reader, format types.PCMFormat) (*types.DCOffsetResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	bytesPerSample := int(format.BitDepth / 8)         //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * int(format.Channels) //nolint:gosec // bit depth and channel count are small constants
 	buf := make([]byte, frameSize*4096)
@@ -34,11 +38,12 @@ func Detect(reader io.Reader, format types.PCMFormat) (*types.DCOffsetResult, er
 	default:
 	}
 
+	reader = shared.NewFrameReader(reader, frameSize)
+
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
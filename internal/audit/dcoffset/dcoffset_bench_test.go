@@ -0,0 +1,18 @@
+package dcoffset_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/dcoffset"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkDetect(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := dcoffset.Detect(reader, format)
+
+		return err
+	})
+}
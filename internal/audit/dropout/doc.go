@@ -1,2 +1,2 @@
-// Package dropout detects audio dropouts including zero runs, delta spikes, and DC jumps.
+// Package dropout detects audio dropouts including zero runs, delta spikes, DC jumps, impulses, and stuck values.
 package dropout
@@ -97,6 +97,8 @@ func (s *scannerV2) processSampleV2(channel int, sample float64) {
 					Channel:  channel,
 					Type:     types.EventDCJump,
 					Severity: dcDelta,
+					DCBefore: s.prevDC[channel],
+					DCAfter:  currentDC,
 				})
 				s.result.DCJumpCount++
 			}
@@ -117,6 +119,10 @@ func (s *scannerV2) processSampleV2(channel int, sample float64) {
 		s.sqFilled[channel]++
 	}
 
+	// Impulse and stuck value detection - same as original.
+	s.detectImpulse(channel, sample)
+	s.detectStuck(channel, sample)
+	s.prevPrev[channel] = s.prevSample[channel]
 	s.prevSample[channel] = sample
 }
 
@@ -235,6 +241,10 @@ func (s *scannerV2) finalizeV2() *types.DropoutResult {
 }
 
 func DetectV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.DropoutResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if opts.DeltaThreshold == 0 {
 		opts.DeltaThreshold = 0.6
 	}
@@ -259,6 +269,22 @@ func DetectV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.Dr
 		opts.DCJumpThreshold = 0.1
 	}
 
+	if opts.ImpulseLevel == 0 {
+		opts.ImpulseLevel = 0.9
+	}
+
+	if opts.ImpulseJump == 0 {
+		opts.ImpulseJump = 0.5
+	}
+
+	if opts.StuckRunMinMs == 0 {
+		opts.StuckRunMinMs = 10.0
+	}
+
+	if opts.StuckMinLevel == 0 {
+		opts.StuckMinLevel = 0.001
+	}
+
 	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
 	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * numChannels
@@ -280,11 +306,12 @@ func DetectV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.Dr
 
 	scan := newScannerV2(opts, sampleRate, numChannels)
 
+	reader = shared.NewFrameReader(reader, frameSize)
+
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -20,8 +20,15 @@ type Options struct {
 	ZeroRunQuietDb  float64 // RMS below this around a zero run = not a dropout; default -50
 	DCWindowMs      float64 // window for DC average; default 50ms
 	DCJumpThreshold float64 // DC change threshold; default 0.1
+	ImpulseLevel    float64 // an impulse must reach this normalized level; default 0.9
+	ImpulseJump     float64 // minimum distance of an impulse from both neighbors; default 0.5
+	StuckRunMinMs   float64 // minimum run of an identical non-zero value to report; default 10ms
+	StuckMinLevel   float64 // a stuck value must reach this normalized level; default 0.001 (-60 dBFS)
 }
 
+// stuckMaxLevel bounds stuck values: a flat top near full scale is clipping, the clipping check's.
+const stuckMaxLevel = 0.9
+
 func DefaultOptions() Options {
 	return Options{
 		DeltaThreshold:  0.6,
@@ -30,6 +37,10 @@ func DefaultOptions() Options {
 		ZeroRunQuietDb:  -50.0,
 		DCWindowMs:      50.0,
 		DCJumpThreshold: 0.1,
+		ImpulseLevel:    0.9,
+		ImpulseJump:     0.5,
+		StuckRunMinMs:   10.0,
+		StuckMinLevel:   0.001,
 	}
 }
 
@@ -39,12 +50,14 @@ type scanner struct {
 	sampleRate     float64
 	dcWindowSize   int
 	minZeroSamples int
+	minStuckFrames int
 	result         *types.DropoutResult
 	totalFrames    uint64
 	firstSample    bool
 
 	// Per-channel state.
 	prevSample    []float64
+	prevPrev      []float64
 	zeroStart     []int64
 	zeroStartRms  []float64
 	dcBuf         [][]float64
@@ -57,21 +70,26 @@ type scanner struct {
 	sqPos         []int
 	sqSum         []float64
 	sqFilled      []int
+	stuckStart    []int64 // -1: no run
+	stuckValue    []float64
 }
 
 func newScanner(opts Options, sampleRate float64, numChannels int) *scanner {
 	dcWindowSize := max(int(sampleRate*opts.DCWindowMs/1000), 1)
 	minZeroSamples := max(int(sampleRate*opts.ZeroRunMinMs/1000), 1)
+	minStuckFrames := max(int(sampleRate*opts.StuckRunMinMs/1000), 2)
 
 	scan := &scanner{
 		opts:           opts,
 		sampleRate:     sampleRate,
 		dcWindowSize:   dcWindowSize,
 		minZeroSamples: minZeroSamples,
+		minStuckFrames: minStuckFrames,
 		result:         &types.DropoutResult{},
 		firstSample:    true,
 
 		prevSample:    make([]float64, numChannels),
+		prevPrev:      make([]float64, numChannels),
 		zeroStart:     make([]int64, numChannels),
 		zeroStartRms:  make([]float64, numChannels),
 		dcBuf:         make([][]float64, numChannels),
@@ -84,10 +102,13 @@ func newScanner(opts Options, sampleRate float64, numChannels int) *scanner {
 		sqPos:         make([]int, numChannels),
 		sqSum:         make([]float64, numChannels),
 		sqFilled:      make([]int, numChannels),
+		stuckStart:    make([]int64, numChannels),
+		stuckValue:    make([]float64, numChannels),
 	}
 
 	for i := range scan.zeroStart {
 		scan.zeroStart[i] = -1
+		scan.stuckStart[i] = -1
 	}
 
 	for ch := range numChannels {
@@ -161,6 +182,8 @@ func (s *scanner) processSample(channel int, sample float64) {
 					Channel:  channel,
 					Type:     types.EventDCJump,
 					Severity: dcDelta,
+					DCBefore: s.prevDC[channel],
+					DCAfter:  currentDC,
 				})
 				s.result.DCJumpCount++
 			}
@@ -181,18 +204,132 @@ func (s *scanner) processSample(channel int, sample float64) {
 		s.sqFilled[channel]++
 	}
 
+	s.detectImpulse(channel, sample)
+	s.detectStuck(channel, sample)
+	s.prevPrev[channel] = s.prevSample[channel]
 	s.prevSample[channel] = sample
 }
 
+// detectStuck tracks runs of an identical sample value, away from zero (the zero runs) and from full scale
+// (clipping): a converter stuck at a DC level, whose delta is zero, so that no jump shows. Quantization holds the
+// same value a few samples at most in music; a run as long as StuckRunMinMs is a flatline.
+func (s *scanner) detectStuck(channel int, sample float64) {
+	if s.stuckStart[channel] >= 0 && sample == s.stuckValue[channel] {
+		return
+	}
+
+	s.endStuck(channel)
+
+	if level := math.Abs(sample); level >= s.opts.StuckMinLevel && level < stuckMaxLevel {
+		s.stuckStart[channel] = int64(s.totalFrames) //nolint:gosec // frame count fits in int64
+		s.stuckValue[channel] = sample
+	}
+}
+
+// endStuck closes the channel's stuck run, if any, at the current frame, and reports it if long enough.
+func (s *scanner) endStuck(channel int) {
+	if s.stuckStart[channel] < 0 {
+		return
+	}
+
+	runLength := int64(s.totalFrames) - s.stuckStart[channel] //nolint:gosec // frame count fits in int64
+	if runLength >= int64(s.minStuckFrames) {
+		s.result.Events = append(s.result.Events, types.Event{
+			Frame:      uint64(s.stuckStart[channel]), //nolint:gosec // value is non-negative by construction
+			TimeSec:    float64(s.stuckStart[channel]) / s.sampleRate,
+			Channel:    channel,
+			Type:       types.EventStuck,
+			Severity:   float64(runLength) / s.sampleRate,
+			DurationMs: float64(runLength) / s.sampleRate * 1000,
+			Value:      s.stuckValue[channel],
+		})
+		s.result.StuckCount++
+	}
+
+	s.stuckStart[channel] = -1
+}
+
+// detectImpulse checks whether the previous sample was an isolated spike: near full scale, far from both of
+// its neighbors in the same direction, with the neighbors close to each other (the signal returns immediately).
+// That is the signature of a bit error or bad sector, not of a musical transient.
+func (s *scanner) detectImpulse(channel int, sample float64) {
+	if s.totalFrames < 2 {
+		return
+	}
+
+	spike := s.prevSample[channel]
+	before := s.prevPrev[channel]
+
+	if math.Abs(spike) < s.opts.ImpulseLevel || math.Abs(sample-before) > s.opts.ImpulseJump/2 {
+		return
+	}
+
+	riseBefore := spike - before
+	riseAfter := spike - sample
+
+	if math.Abs(riseBefore) < s.opts.ImpulseJump || math.Abs(riseAfter) < s.opts.ImpulseJump ||
+		(riseBefore > 0) != (riseAfter > 0) {
+		return
+	}
+
+	frame := s.totalFrames - 1
+	s.result.Events = append(s.result.Events, types.Event{
+		Frame:    frame,
+		TimeSec:  float64(frame) / s.sampleRate,
+		Channel:  channel,
+		Type:     types.EventImpulse,
+		Severity: math.Min(math.Abs(riseBefore), math.Abs(riseAfter)),
+	})
+	s.result.ImpulseCount++
+}
+
+// reclassifyImpulses drops the delta events an impulse also triggers on its way up and down, so each
+// corrupted sample is counted once, as an impulse.
+func (s *scanner) reclassifyImpulses() {
+	if s.result.ImpulseCount == 0 {
+		return
+	}
+
+	type key struct {
+		channel int
+		frame   uint64
+	}
+
+	impulses := map[key]bool{}
+
+	for _, e := range s.result.Events {
+		if e.Type == types.EventImpulse {
+			impulses[key{e.Channel, e.Frame}] = true
+		}
+	}
+
+	events := s.result.Events[:0]
+
+	for _, e := range s.result.Events {
+		if e.Type == types.EventDelta &&
+			(impulses[key{e.Channel, e.Frame}] || (e.Frame > 0 && impulses[key{e.Channel, e.Frame - 1}])) {
+			s.result.DeltaCount--
+
+			continue
+		}
+
+		events = append(events, e)
+	}
+
+	s.result.Events = events
+}
+
 // endFrame advances the frame counter and clears the first-sample flag.
 func (s *scanner) endFrame() {
 	s.totalFrames++
 	s.firstSample = false
 }
 
-// flush emits any trailing zero runs still open at EOF.
+// flush emits any trailing zero and stuck runs still open at EOF.
 func (s *scanner) flush() {
 	for channel := range s.zeroStart {
+		s.endStuck(channel)
+
 		if s.zeroStart[channel] >= 0 {
 			runLength := int64(s.totalFrames) - s.zeroStart[channel] //nolint:gosec // frame count fits in int64
 			if runLength >= int64(s.minZeroSamples) && s.zeroStartRms[channel] >= s.opts.ZeroRunQuietDb {
@@ -214,11 +351,12 @@ func (s *scanner) flush() {
 // finalize computes the worst severity and sets the frame count on the result.
 func (s *scanner) finalize() *types.DropoutResult {
 	s.flush()
+	s.reclassifyImpulses()
 
 	var worstSeverity float64
 
 	for _, e := range s.result.Events {
-		if e.Type == types.EventDelta || e.Type == types.EventDCJump {
+		if e.Type == types.EventDelta || e.Type == types.EventDCJump || e.Type == types.EventImpulse {
 			if e.Severity > worstSeverity {
 				worstSeverity = e.Severity
 			}
@@ -231,6 +369,8 @@ func (s *scanner) finalize() *types.DropoutResult {
 		s.result.WorstDb = -120
 	}
 
+	s.result.PeriodicSamples, s.result.PeriodicFraction = detectPeriodicity(s.result.Events)
+	s.result.BufferBoundarySamples, s.result.BufferBoundaryFraction = detectBufferAlignment(s.result.Events)
 	s.result.Frames = s.totalFrames
 
 	return s.result
@@ -259,6 +399,10 @@ func isDeltaDropout(prev, cur, nearZero float64) bool {
 }
 
 func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.DropoutResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if opts.DeltaThreshold == 0 {
 		opts.DeltaThreshold = 0.6
 	}
@@ -283,6 +427,22 @@ func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.DropoutRe
 		opts.DCJumpThreshold = 0.1
 	}
 
+	if opts.ImpulseLevel == 0 {
+		opts.ImpulseLevel = 0.9
+	}
+
+	if opts.ImpulseJump == 0 {
+		opts.ImpulseJump = 0.5
+	}
+
+	if opts.StuckRunMinMs == 0 {
+		opts.StuckRunMinMs = 10.0
+	}
+
+	if opts.StuckMinLevel == 0 {
+		opts.StuckMinLevel = 0.001
+	}
+
 	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
 	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * numChannels
@@ -304,11 +464,12 @@ func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.DropoutRe
 
 	scan := newScanner(opts, sampleRate, numChannels)
 
+	r = shared.NewFrameReader(r, frameSize)
+
 	for {
 		n, err := r.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -0,0 +1,26 @@
+package dropout_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/dropout"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkDetect(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := dropout.Detect(reader, format, dropout.DefaultOptions())
+
+		return err
+	})
+}
+
+func BenchmarkDetectV2(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := dropout.DetectV2(reader, format, dropout.DefaultOptions())
+
+		return err
+	})
+}
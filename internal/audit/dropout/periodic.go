@@ -0,0 +1,147 @@
+package dropout
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	periodicMinEvents   = 8   // fewer events cannot establish a rhythm
+	periodicMinPeriod   = 64  // shorter intervals are waveform features, not codec frames
+	periodicTolerance   = 2   // samples of jitter allowed around the grid
+	periodicMinFraction = 0.8 // share of events that must sit on the grid
+	periodicCandidates  = 16  // most frequent intervals tried as periods
+	periodicDivisors    = 4   // each interval is also tried as 2, 3 and 4 periods
+)
+
+// detectPeriodicity looks for events recurring on a fixed sample grid, the footprint of a codec or concatenation
+// bug (damage every 1024 or 4096 samples, one frame boundary each), as opposed to random physical damage.
+//
+// Events are merged across channels. Candidate periods are the most frequent intervals between consecutive
+// events; a candidate is scored by the fraction of events that fall on its dominant phase (event frame modulo the
+// period, within a few samples), which is a coarse autocorrelation of event times. Because clicks may skip
+// frames, fractions of each interval are tried as well.
+func detectPeriodicity(events []types.Event) (period uint64, fraction float64) {
+	frames := make([]uint64, 0, len(events))
+	for _, event := range events {
+		frames = append(frames, event.Frame)
+	}
+
+	slices.Sort(frames)
+	frames = slices.Compact(frames)
+
+	if len(frames) < periodicMinEvents {
+		return 0, 0
+	}
+
+	intervals := map[uint64]int{}
+
+	for i := 1; i < len(frames); i++ {
+		if interval := frames[i] - frames[i-1]; interval >= periodicMinPeriod {
+			intervals[interval]++
+		}
+	}
+
+	candidates := slices.SortedFunc(maps.Keys(intervals), func(a, b uint64) int {
+		if intervals[a] != intervals[b] {
+			return intervals[b] - intervals[a]
+		}
+
+		if a < b {
+			return -1
+		}
+
+		return 1
+	})
+
+	var bestPeriod uint64
+
+	bestFraction := 0.0
+
+	for _, interval := range candidates[:min(len(candidates), periodicCandidates)] {
+		if intervals[interval] < 2 {
+			break
+		}
+
+		// Skipped frames make multiples of the period the most common interval: try its fractions too.
+		for divisor := uint64(1); divisor <= periodicDivisors && interval/divisor >= periodicMinPeriod; divisor++ {
+			candidate := interval / divisor
+
+			// Any divisor of the true period scores as well as it does: on ties, the longer period wins.
+			score := phaseFraction(frames, candidate)
+			if score > bestFraction || (score == bestFraction && candidate > bestPeriod) {
+				bestPeriod = candidate
+				bestFraction = score
+			}
+		}
+	}
+
+	if bestFraction < periodicMinFraction {
+		return 0, bestFraction
+	}
+
+	return bestPeriod, bestFraction
+}
+
+// phaseFraction returns the fraction of frames sitting on the most populated phase of the period.
+func phaseFraction(frames []uint64, period uint64) float64 {
+	phases := map[uint64]int{}
+	for _, frame := range frames {
+		phases[frame%period]++
+	}
+
+	var best int
+
+	for phase := range phases {
+		count := 0
+
+		for offset := -periodicTolerance; offset <= periodicTolerance; offset++ {
+			count += phases[(phase+period+uint64(offset))%period] //nolint:gosec // offset + period is positive
+		}
+
+		best = max(best, count)
+	}
+
+	return float64(best) / float64(len(frames))
+}
+
+// Buffer sizes that software commonly processes audio in, largest first.
+//
+//nolint:gochecknoglobals // configuration data, effectively const
+var bufferSizes = []uint64{4096, 2048, 1024, 512, 256}
+
+// detectBufferAlignment checks whether events sit on absolute multiples of a common buffer size: a tool that drops
+// or zero-fills whole buffers leaves its damage where buffers start, however irregularly it strikes. Physical damage
+// lands anywhere. The largest size holding 80% of the events (within a few samples) wins, since its boundaries are
+// boundaries of every smaller size too.
+func detectBufferAlignment(events []types.Event) (size uint64, fraction float64) {
+	frames := make([]uint64, 0, len(events))
+	for _, event := range events {
+		frames = append(frames, event.Frame)
+	}
+
+	slices.Sort(frames)
+	frames = slices.Compact(frames)
+
+	if len(frames) < periodicMinEvents {
+		return 0, 0
+	}
+
+	for _, candidate := range bufferSizes {
+		aligned := 0
+
+		for _, frame := range frames {
+			if phase := frame % candidate; phase <= periodicTolerance || candidate-phase <= periodicTolerance {
+				aligned++
+			}
+		}
+
+		if share := float64(aligned) / float64(len(frames)); share >= periodicMinFraction {
+			return candidate, share
+		}
+	}
+
+	return 0, 0
+}
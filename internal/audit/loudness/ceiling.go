@@ -0,0 +1,90 @@
+package loudness
+
+import "math"
+
+const (
+	ceilingBinDb       = 0.05 // histogram resolution
+	ceilingRangeDb     = 12.0 // histogram span under full scale
+	ceilingFullScaleDb = 0.05 // levels within this of full scale are clipping, not a limiter ceiling
+	ceilingBaselineDb  = 1.0  // the baseline is taken from 1 to 2 dB under the spike
+	ceilingSpikeRatio  = 8.0  // spike count over the baseline bin average
+	ceilingAboveRatio  = 0.1  // samples above the spike, at most, relative to the spike: nothing passes a limiter
+	ceilingMinShare    = 1e-4 // spike samples, as a share of all samples
+	ceilingMinSamples  = 100
+)
+
+// ceilingHistogram is the amplitude histogram of the top 12 dB under full scale, in 0.05 dB bins. A hard limiter
+// piles samples up at its ceiling, with nothing above: a narrow spike over the smooth decay of the distribution.
+type ceilingHistogram struct {
+	bins    []uint64
+	sums    []float64 // level sum per bin (dB), for the mean level of the spike
+	floor   float64   // linear level of the histogram's lowest edge
+	top     float64   // linear level above which samples are at full scale
+	samples uint64
+}
+
+func newCeilingHistogram() *ceilingHistogram {
+	bins := int(math.Round((ceilingRangeDb - ceilingFullScaleDb) / ceilingBinDb))
+
+	return &ceilingHistogram{
+		bins:  make([]uint64, bins),
+		sums:  make([]float64, bins),
+		floor: math.Pow(10, -ceilingRangeDb/20),
+		top:   math.Pow(10, -ceilingFullScaleDb/20),
+	}
+}
+
+// add counts one sample magnitude (0-1).
+func (h *ceilingHistogram) add(magnitude float64) {
+	h.samples++
+
+	if magnitude < h.floor || magnitude >= h.top {
+		return
+	}
+
+	level := 20 * math.Log10(magnitude)
+	bin := min(int((level+ceilingRangeDb)/ceilingBinDb), len(h.bins)-1)
+
+	h.bins[bin]++
+	h.sums[bin] += level
+}
+
+// ceiling returns the level (dBFS) of the dominant spike under full scale, or 0 when there is none: the bin
+// holding the most samples, far above the bins 1-2 dB under it, with next to nothing above it.
+func (h *ceilingHistogram) ceiling() float64 {
+	peak := 0
+	for bin := range h.bins {
+		if h.bins[bin] > h.bins[peak] {
+			peak = bin
+		}
+	}
+
+	count := float64(h.bins[peak])
+	if count < ceilingMinSamples || count < ceilingMinShare*float64(h.samples) {
+		return 0
+	}
+
+	offset := int(ceilingBaselineDb / ceilingBinDb)
+	if peak < 2*offset {
+		return 0
+	}
+
+	var baseline float64
+	for bin := peak - 2*offset; bin < peak-offset; bin++ {
+		baseline += float64(h.bins[bin])
+	}
+
+	baseline /= float64(offset)
+
+	// The bin right above the spike may share its rounding: it is left out.
+	var above float64
+	for bin := peak + 2; bin < len(h.bins); bin++ {
+		above += float64(h.bins[bin])
+	}
+
+	if count < ceilingSpikeRatio*baseline || above > ceilingAboveRatio*count {
+		return 0
+	}
+
+	return h.sums[peak] / count
+}
@@ -0,0 +1,65 @@
+package loudness
+
+import "math"
+
+const (
+	lfeChannel      = 3     // LFE position in the ffmpeg/WAV order of 5.1 and 7.1
+	lfeCutoffHz     = 200.0 // LFE streams are low-passed around 120 Hz: energy above this is not LFE content
+	lfeFullRange    = 0.15  // share of the energy above the cutoff over which the "LFE" is a full-range channel
+	lfeMinLevelDb   = -70.0 // quieter LFE channels say nothing about the mapping
+	lfeButterworthQ = math.Sqrt2 / 2
+)
+
+// lfeTracker measures how band-limited the LFE channel is: the share of its energy left after a 200 Hz
+// high-pass. A real LFE keeps a few percent; a full-range channel at the LFE position means the channels are
+// mapped in another order, or the layout is not 5.1/7.1 at all, and the LFE exclusion drops a real channel.
+type lfeTracker struct {
+	highPass biquad
+	state    biquadState
+	energy   float64
+	high     float64
+	samples  uint64
+}
+
+func newLFETracker(sampleRate int) *lfeTracker {
+	// RBJ cookbook high-pass.
+	omega := 2 * math.Pi * lfeCutoffHz / float64(sampleRate)
+	alpha := math.Sin(omega) / (2 * lfeButterworthQ)
+	cos := math.Cos(omega)
+	a0 := 1 + alpha
+
+	return &lfeTracker{
+		highPass: biquad{
+			b0: (1 + cos) / 2 / a0,
+			b1: -(1 + cos) / a0,
+			b2: (1 + cos) / 2 / a0,
+			a1: -2 * cos / a0,
+			a2: (1 - alpha) / a0,
+		},
+	}
+}
+
+func (t *lfeTracker) add(sample float64) {
+	filtered := t.state.process(&t.highPass, sample)
+
+	t.energy += sample * sample
+	t.high += filtered * filtered
+	t.samples++
+}
+
+// measure returns the LFE RMS level (dBFS), the share of its energy above the cutoff (0-1), and whether it is
+// full-range. The share is 0 for a silent or near-silent LFE.
+func (t *lfeTracker) measure() (float64, float64, bool) {
+	if t.samples == 0 || t.energy == 0 {
+		return -120, 0, false
+	}
+
+	level := 10 * math.Log10(t.energy/float64(t.samples))
+	if level < lfeMinLevelDb {
+		return level, 0, false
+	}
+
+	share := t.high / t.energy
+
+	return level, share, share > lfeFullRange
+}
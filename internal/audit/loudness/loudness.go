@@ -73,15 +73,23 @@ func getKWeightingFilters(rate int) (pre, rlb biquad) {
 	return pre, rlb
 }
 
-// Channel weights for surround (we only handle stereo for now).
+// Channel weights per ITU-R BS.1770, assuming the ffmpeg/WAV channel order (FL FR FC LFE BL BR SL SR).
+// Front channels weigh 1.0, surrounds 1.41 (~+1.5dB), and the LFE is excluded.
 func getChannelWeight(channel, numChannels int) float64 {
-	if numChannels <= 2 {
-		return 1.0
-	}
-	// For surround: L, R, C = 1.0; Ls, Rs = 1.41 (~+1.5dB)
-	// LFE is excluded
-	if channel >= 3 && channel <= 4 && numChannels > 4 {
-		return 1.41
+	switch numChannels {
+	case 5: // 5.0: L R C Ls Rs
+		if channel >= 3 {
+			return 1.41
+		}
+	case 6, 8: // 5.1: L R C LFE Ls Rs, 7.1: L R C LFE Lb Rb Ls Rs
+		if channel == 3 {
+			return 0
+		}
+
+		if channel > 3 {
+			return 1.41
+		}
+	default:
 	}
 
 	return 1.0
@@ -93,6 +101,26 @@ type drBlock struct {
 	rms  float64
 }
 
+// Options sets the measurement windows. The defaults are the standard ones: BS.1770 momentary (400 ms) and
+// short-term (3 s) windows on a 100 ms hop, and the 3 s blocks of the DR meter. Any other value makes the
+// results non-standard (loudness no longer comparable to other meters or delivery specs, a DR score no longer
+// a DR score), for research or custom reporting only. Zero fields keep the defaults.
+type Options struct {
+	MomentaryMs float64 // momentary window, gating block of the integrated loudness; default 400
+	ShortTermMs float64 // short-term window, of the loudness range; default 3000
+	DRBlockMs   float64 // DR block; default 3000
+	HopMs       float64 // step of the momentary and short-term windows; default 100
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MomentaryMs: 400,
+		ShortTermMs: 3000,
+		DRBlockMs:   3000,
+		HopMs:       100,
+	}
+}
+
 // meter holds all state for the loudness/DR measurement.
 type meter struct {
 	numChannels int
@@ -129,6 +157,19 @@ type meter struct {
 	momentaryMax    float64
 	shortTermMax    float64
 
+	// Limiting: attack shapes of the transients (unweighted).
+	transients *transientDetector
+	ceiling    *ceilingHistogram
+
+	// Surround: how band-limited the LFE channel is (5.1 and 7.1 only, nil otherwise).
+	lfe *lfeTracker
+
+	// Quietest passage (unweighted).
+	quiet *quietTracker
+
+	// Stereo: the loudness of the mono sum (nil otherwise).
+	mono *monoSumTracker
+
 	// Counters.
 	sampleCount int
 	totalFrames uint64
@@ -137,9 +178,27 @@ type meter struct {
 	frameSamples []float64
 }
 
-func newMeter(sampleRate, numChannels int) *meter {
+func newMeter(sampleRate, numChannels int, opts Options) *meter {
 	pre, rlb := getKWeightingFilters(sampleRate)
 
+	// Window sizes in samples, at least one.
+	samples := func(ms float64) int {
+		return max(int(ms*float64(sampleRate)/1000), 1)
+	}
+
+	momentarySize := samples(opts.MomentaryMs)
+	shortTermSize := samples(opts.ShortTermMs)
+
+	var lfe *lfeTracker
+	if getChannelWeight(lfeChannel, numChannels) == 0 {
+		lfe = newLFETracker(sampleRate)
+	}
+
+	var mono *monoSumTracker
+	if numChannels == 2 {
+		mono = newMonoSumTracker(momentarySize)
+	}
+
 	return &meter{
 		numChannels:   numChannels,
 		sampleRate:    sampleRate,
@@ -147,35 +206,63 @@ func newMeter(sampleRate, numChannels int) *meter {
 		rlb:           rlb,
 		preState:      make([]biquadState, numChannels),
 		rlbState:      make([]biquadState, numChannels),
-		momentarySize: sampleRate * 400 / 1000,
-		shortTermSize: sampleRate * 3,
-		blockSize:     sampleRate * 3,
-		hopSize:       sampleRate * 100 / 1000,
-		momentaryBuf:  make([]float64, sampleRate*400/1000),
-		shortTermBuf:  make([]float64, sampleRate*3),
+		momentarySize: momentarySize,
+		shortTermSize: shortTermSize,
+		blockSize:     samples(opts.DRBlockMs),
+		hopSize:       samples(opts.HopMs),
+		momentaryBuf:  make([]float64, momentarySize),
+		shortTermBuf:  make([]float64, shortTermSize),
 		momentaryMax:  -120,
 		shortTermMax:  -120,
 		frameSamples:  make([]float64, numChannels),
+		transients:    newTransientDetector(sampleRate),
+		ceiling:       newCeilingHistogram(),
+		lfe:           lfe,
+		quiet:         newQuietTracker(sampleRate),
+		mono:          mono,
 	}
 }
 
 // processFrame applies K-weighting, accumulates loudness and DR data for one frame.
 // The caller must fill m.frameSamples before calling this.
 func (m *meter) processFrame() {
-	var framePower, framePeak float64
+	var (
+		framePower, framePeak, rawPower float64
+		weighted                        [2]float64 // K-weighted left and right, for the mono sum
+	)
 
 	for channel, sample := range m.frameSamples {
-		if abs := math.Abs(sample); abs > framePeak {
+		abs := math.Abs(sample)
+		if abs > framePeak {
 			framePeak = abs
 		}
 
+		m.ceiling.add(abs)
+
+		if m.lfe != nil && channel == lfeChannel {
+			m.lfe.add(sample)
+		}
+
+		rawPower += sample * sample
+
 		filtered := m.preState[channel].process(&m.pre, sample)
 		filtered = m.rlbState[channel].process(&m.rlb, filtered)
 
 		weight := getChannelWeight(channel, m.numChannels)
 		framePower += weight * filtered * filtered
+
+		if channel < len(weighted) {
+			weighted[channel] = filtered
+		}
+	}
+
+	if m.mono != nil {
+		m.mono.add(weighted[0], weighted[1])
 	}
 
+	m.transients.add(framePeak, rawPower/float64(m.numChannels))
+	m.quiet.add(rawPower / float64(m.numChannels))
+
 	// Update DR block.
 	m.blockSum += framePower / float64(m.numChannels)
 
@@ -227,6 +314,10 @@ func (m *meter) processFrame() {
 			}
 		}
 
+		if m.mono != nil {
+			m.mono.hop()
+		}
+
 		if m.shortTermFilled == m.shortTermSize {
 			shortTermLoudness := -0.691 + 10*math.Log10(m.shortTermSum/float64(m.shortTermSize))
 			m.shortTermPowers = append(m.shortTermPowers, m.shortTermSum/float64(m.shortTermSize))
@@ -241,29 +332,73 @@ func (m *meter) processFrame() {
 // finalize handles the final partial DR block and computes all results.
 func (m *meter) finalize() *types.LoudnessResult {
 	// Handle final partial DR block.
-	if m.blockSamples > m.sampleRate { // at least 1 second
+	if m.blockSamples > m.blockSize/3 { // at least a third: 1 second of the standard 3
 		rms := math.Sqrt(m.blockSum / float64(m.blockSamples))
 		m.drBlocks = append(m.drBlocks, drBlock{m.blockPeak, rms})
 	}
 
-	integratedLUFS := calculateIntegratedLoudness(m.momentaryPowers)
+	integratedLUFS, coverage := calculateIntegratedLoudness(m.momentaryPowers)
 	lra := calculateLoudnessRange(m.shortTermPowers)
 	drScore, drValue, peakDb, rmsDb := calculateDR(m.drBlocks)
+	flattening, transients := m.transients.flatteningIndex()
+
+	result := &types.LoudnessResult{
+		IntegratedLUFS:            integratedLUFS,
+		GatedCoverage:             coverage,
+		ShortTermMax:              m.shortTermMax,
+		MomentaryMax:              m.momentaryMax,
+		LoudnessRange:             lra,
+		DRScore:                   drScore,
+		DRValue:                   drValue,
+		PeakDb:                    peakDb,
+		RmsDb:                     rmsDb,
+		TransientFlatteningIndex:  flattening,
+		TransientsClippedFraction: m.transients.clippedFraction(),
+		Transients:                transients,
+		LimiterCeilingDb:          m.ceiling.ceiling(),
+		Frames:                    m.totalFrames,
+	}
+
+	quietestDb, quietestFrame := m.quiet.measure()
+	result.QuietestPassageDbFS = quietestDb
+	result.QuietestPassageSec = float64(quietestFrame) / float64(m.sampleRate)
+
+	if m.mono != nil {
+		result.MonoMeasured = true
+		result.MonoLoudnessPenaltyLU = m.mono.penalty(integratedLUFS)
+	}
 
-	return &types.LoudnessResult{
-		IntegratedLUFS: integratedLUFS,
-		ShortTermMax:   m.shortTermMax,
-		MomentaryMax:   m.momentaryMax,
-		LoudnessRange:  lra,
-		DRScore:        drScore,
-		DRValue:        drValue,
-		PeakDb:         peakDb,
-		RmsDb:          rmsDb,
-		Frames:         m.totalFrames,
+	if m.lfe != nil {
+		result.LFEMeasured = true
+		result.LFELevelDb, result.LFEHighBandShare, result.LFEFullRange = m.lfe.measure()
 	}
+
+	return result
 }
 
-func Analyze(reader io.Reader, format types.PCMFormat) (*types.LoudnessResult, error) {
+func Analyze(reader io.Reader, format types.PCMFormat, opts Options) (*types.LoudnessResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultOptions()
+
+	if opts.MomentaryMs <= 0 {
+		opts.MomentaryMs = defaults.MomentaryMs
+	}
+
+	if opts.ShortTermMs <= 0 {
+		opts.ShortTermMs = defaults.ShortTermMs
+	}
+
+	if opts.DRBlockMs <= 0 {
+		opts.DRBlockMs = defaults.DRBlockMs
+	}
+
+	if opts.HopMs <= 0 {
+		opts.HopMs = defaults.HopMs
+	}
+
 	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
 	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * numChannels
@@ -283,13 +418,14 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.LoudnessResult, e
 	default:
 	}
 
-	measurement := newMeter(sampleRate, numChannels)
+	measurement := newMeter(sampleRate, numChannels, opts)
+
+	reader = shared.NewFrameReader(reader, frameSize)
 
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -343,9 +479,11 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.LoudnessResult, e
 	return measurement.finalize(), nil
 }
 
-func calculateIntegratedLoudness(powers []float64) float64 {
+// calculateIntegratedLoudness returns the gated integrated loudness, and the fraction of blocks
+// that survived both gates (the share of the track the figure is actually based on).
+func calculateIntegratedLoudness(powers []float64) (lufs, coverage float64) {
 	if len(powers) == 0 {
-		return -120
+		return -120, 0
 	}
 
 	// First pass: absolute gate at -70 LUFS
@@ -355,15 +493,14 @@ func calculateIntegratedLoudness(powers []float64) float64 {
 	)
 
 	for _, p := range powers {
-		lufs := -0.691 + 10*math.Log10(p)
-		if lufs > -70 {
+		if -0.691+10*math.Log10(p) > -70 {
 			sum += p
 			count++
 		}
 	}
 
 	if count == 0 {
-		return -120
+		return -120, 0
 	}
 
 	// Relative threshold: -10 LU below ungated mean
@@ -375,18 +512,17 @@ func calculateIntegratedLoudness(powers []float64) float64 {
 	count = 0
 
 	for _, p := range powers {
-		lufs := -0.691 + 10*math.Log10(p)
-		if lufs > relativeThreshold {
+		if -0.691+10*math.Log10(p) > relativeThreshold {
 			sum += p
 			count++
 		}
 	}
 
 	if count == 0 {
-		return -120
+		return -120, 0
 	}
 
-	return -0.691 + 10*math.Log10(sum/float64(count))
+	return -0.691 + 10*math.Log10(sum/float64(count)), float64(count) / float64(len(powers))
 }
 
 func calculateLoudnessRange(powers []float64) float64 {
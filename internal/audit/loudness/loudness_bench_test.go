@@ -0,0 +1,18 @@
+package loudness_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/loudness"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkAnalyze(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := loudness.Analyze(reader, format, loudness.DefaultOptions())
+
+		return err
+	})
+}
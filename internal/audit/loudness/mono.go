@@ -0,0 +1,46 @@
+package loudness
+
+// monoSumTracker measures the integrated loudness of a stereo signal folded to mono: (L+R)/2, played on both
+// speakers, as the stereo analysis sums it. K-weighting is linear, so the sum of the K-weighted channels is the
+// K-weighted mono sum: the tracker takes the meter's filtered samples, and gates its own momentary blocks.
+type monoSumTracker struct {
+	buf    []float64
+	pos    int
+	sum    float64
+	filled int
+	powers []float64
+}
+
+func newMonoSumTracker(momentarySize int) *monoSumTracker {
+	return &monoSumTracker{buf: make([]float64, momentarySize)}
+}
+
+// add takes a frame's K-weighted left and right samples.
+func (t *monoSumTracker) add(left, right float64) {
+	mono := (left + right) / 2
+	power := 2 * mono * mono // on both speakers, as the stereo power sums the two channels
+
+	old := t.buf[t.pos]
+	t.buf[t.pos] = power
+	t.sum = t.sum - old + power
+
+	t.pos = (t.pos + 1) % len(t.buf)
+	if t.filled < len(t.buf) {
+		t.filled++
+	}
+}
+
+// hop records the momentary block, every hop of the meter.
+func (t *monoSumTracker) hop() {
+	if t.filled == len(t.buf) {
+		t.powers = append(t.powers, t.sum/float64(len(t.buf)))
+	}
+}
+
+// penalty returns how much quieter (LU) the mono sum is than the stereo integrated loudness: 0 for identical
+// channels, about 3 for uncorrelated ones, more with out-of-phase content.
+func (t *monoSumTracker) penalty(stereoLUFS float64) float64 {
+	monoLUFS, _ := calculateIntegratedLoudness(t.powers)
+
+	return stereoLUFS - monoLUFS
+}
@@ -0,0 +1,51 @@
+package loudness
+
+import "math"
+
+const quietWindowMs = 400
+
+// quietTracker finds the quietest 400 ms passage of the track: its unweighted RMS, across channels, is how quiet
+// the track gets in the time domain. Digital silence (padding, muted gaps) is left out: it is the absence of a
+// signal, not the quietest part of one.
+type quietTracker struct {
+	size    int
+	samples int
+	sum     float64
+	start   uint64
+	frame   uint64
+
+	quietest   float64 // mean power of the quietest window; -1 until one is found
+	quietestAt uint64
+}
+
+func newQuietTracker(sampleRate int) *quietTracker {
+	return &quietTracker{size: max(sampleRate*quietWindowMs/1000, 1), quietest: -1}
+}
+
+// add accumulates the mean power of one frame across channels.
+func (t *quietTracker) add(power float64) {
+	t.sum += power
+	t.frame++
+
+	t.samples++
+	if t.samples < t.size {
+		return
+	}
+
+	mean := t.sum / float64(t.samples)
+	if mean > 0 && (t.quietest < 0 || mean < t.quietest) {
+		t.quietest, t.quietestAt = mean, t.start
+	}
+
+	t.samples, t.sum, t.start = 0, 0, t.frame
+}
+
+// measure returns the RMS level (dBFS) of the quietest passage, and its first frame. The level is -120 when the
+// track has no full window of signal.
+func (t *quietTracker) measure() (float64, uint64) {
+	if t.quietest < 0 {
+		return -120, 0
+	}
+
+	return max(10*math.Log10(t.quietest), -120), t.quietestAt
+}
@@ -0,0 +1,180 @@
+package loudness
+
+import "math"
+
+const (
+	transientSubWindowMs = 5  // envelope resolution
+	transientHistory     = 10 // sub-windows (50 ms) the onset is compared against
+
+	transientRise        = 2.0   // energy ratio (3 dB) over the preceding 50 ms that makes an onset
+	transientMinEnergy   = 1e-4  // -40 dBFS RMS: quieter onsets are ignored
+	transientCeilingDb   = 1.0   // onsets peaking within this of the track peak are at the ceiling
+	transientFlatCrestDb = 6.0   // attack crest (peak/RMS) at or below this is squared off
+	transientMinOnsets   = 20    // fewer onsets than this: no index
+	transientPlateauRun  = 4     // identical consecutive frame peaks that make a flat top, as the clipping plateaus
+	transientSilentPeak  = 1e-12 // guards log10 on digital silence
+)
+
+// onset holds the attack measurement of one transient: the 10 ms starting at the onset.
+type onset struct {
+	peak    float64
+	crestDb float64
+	clipped bool // the attack peaks on a flat top
+}
+
+// transientDetector finds onsets on a 5 ms energy envelope, and measures how squared off their attacks are.
+// A brickwall limiter shaves every attack down to the same ceiling, leaving a near-square waveform (low crest):
+// natural attacks overshoot their body by far more.
+type transientDetector struct {
+	subWindowSize int
+
+	subSum     float64
+	subPeak    float64
+	subSamples int
+
+	// Flat tops: the run of identical frame peaks, and the highest level a run of transientPlateauRun reached in
+	// the sub-window.
+	prevPeak   float64
+	peakRun    int
+	subPlateau float64
+
+	history    []float64
+	historyPos int
+	historyLen int
+	refractory int // sub-windows left before the next onset can fire
+
+	// Attack in progress: the onset sub-window and the next one.
+	attackLeft    int
+	attackSum     float64
+	attackPeak    float64
+	attackPlateau float64
+	attackLen     int
+
+	onsets []onset
+	peak   float64
+}
+
+func newTransientDetector(sampleRate int) *transientDetector {
+	return &transientDetector{
+		subWindowSize: max(sampleRate*transientSubWindowMs/1000, 1),
+		history:       make([]float64, transientHistory),
+	}
+}
+
+// add accumulates one frame: its peak and mean square across channels.
+func (d *transientDetector) add(peak, meanSquare float64) {
+	d.subSum += meanSquare
+	d.subSamples++
+
+	d.subPeak = max(d.subPeak, peak)
+	d.peak = max(d.peak, peak)
+
+	if peak == d.prevPeak && peak > transientSilentPeak {
+		d.peakRun++
+	} else {
+		d.peakRun = 1
+	}
+
+	d.prevPeak = peak
+
+	if d.peakRun >= transientPlateauRun {
+		d.subPlateau = max(d.subPlateau, peak)
+	}
+
+	if d.subSamples < d.subWindowSize {
+		return
+	}
+
+	d.closeSubWindow()
+}
+
+func (d *transientDetector) closeSubWindow() {
+	energy := d.subSum / float64(d.subSamples)
+	peak := d.subPeak
+	plateau := d.subPlateau
+	samples := d.subSamples
+
+	d.subSum, d.subPeak, d.subPlateau, d.subSamples = 0, 0, 0, 0
+
+	if d.attackLeft > 0 {
+		d.accumulateAttack(energy, peak, plateau, samples)
+	} else if d.refractory > 0 {
+		d.refractory--
+	} else if d.historyLen == transientHistory && energy >= transientMinEnergy {
+		var sum float64
+		for _, e := range d.history {
+			sum += e
+		}
+
+		if energy >= transientRise*sum/transientHistory {
+			d.attackLeft = 2
+			d.attackSum, d.attackPeak, d.attackPlateau, d.attackLen = 0, 0, 0, 0
+			d.accumulateAttack(energy, peak, plateau, samples)
+			d.refractory = transientHistory
+		}
+	}
+
+	d.history[d.historyPos] = energy
+	d.historyPos = (d.historyPos + 1) % transientHistory
+	d.historyLen = min(d.historyLen+1, transientHistory)
+}
+
+func (d *transientDetector) accumulateAttack(energy, peak, plateau float64, samples int) {
+	d.attackSum += energy * float64(samples)
+	d.attackLen += samples
+	d.attackPeak = max(d.attackPeak, peak)
+	d.attackPlateau = max(d.attackPlateau, plateau)
+	d.attackLeft--
+
+	if d.attackLeft > 0 {
+		return
+	}
+
+	rms := math.Sqrt(d.attackSum / float64(d.attackLen))
+	d.onsets = append(d.onsets, onset{
+		peak:    d.attackPeak,
+		crestDb: 20 * math.Log10(d.attackPeak/rms),
+		clipped: d.attackPlateau > 0 && d.attackPlateau >= d.attackPeak,
+	})
+}
+
+// flatteningIndex returns the fraction of onsets that reach the track's ceiling with a squared-off attack,
+// and the number of onsets. The index is 0 below transientMinOnsets onsets.
+// One limited peak in a dynamic track scores near 0; a uniformly limited master scores near 1.
+func (d *transientDetector) flatteningIndex() (index float64, count int) {
+	count = len(d.onsets)
+	if count < transientMinOnsets || d.peak < transientSilentPeak {
+		return 0, count
+	}
+
+	ceiling := d.peak * math.Pow(10, -transientCeilingDb/20)
+
+	var flattened int
+
+	for _, o := range d.onsets {
+		if o.peak >= ceiling && o.crestDb <= transientFlatCrestDb {
+			flattened++
+		}
+	}
+
+	return float64(flattened) / float64(count), count
+}
+
+// clippedFraction returns the fraction of onsets whose attack peaks on a flat top: identical consecutive samples,
+// at any level, the mark of a clipper (or of clipping, then a gain reduction). A limiter rounds the peaks it
+// shaves; a clipper cuts them flat. 0 below transientMinOnsets onsets.
+func (d *transientDetector) clippedFraction() float64 {
+	if len(d.onsets) < transientMinOnsets {
+		return 0
+	}
+
+	var clipped int
+
+	for _, o := range d.onsets {
+		if o.clipped {
+			clipped++
+		}
+	}
+
+	return float64(clipped) / float64(len(d.onsets))
+}
@@ -0,0 +1,2 @@
+// Package repeat detects sample-exact repeated blocks: the stutter left by a drive re-reading a damaged CD.
+package repeat
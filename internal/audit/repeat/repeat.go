@@ -0,0 +1,254 @@
+package repeat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/farcloser/primordium/fault"
+
+	"github.com/farcloser/haustorium/internal/audit/shared"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+type Options struct {
+	BlockMs    float64 // shortest repeat reported; default 13.33 ms (one CD sector: 588 frames at 44.1 kHz)
+	MaxGapMs   float64 // farthest a repeat may be from its original; default 500 ms
+	MinLevelDb float64 // blocks quieter than this (mean absolute level, dBFS) are ignored; default -50
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BlockMs:    588.0 / 44.1,
+		MaxGapMs:   500.0,
+		MinLevelDb: -50.0,
+	}
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+
+	rollingBase = 0x100000001b3 // odd: powers never vanish modulo 2^64
+)
+
+// detector matches every block of frames against the blocks ending in the preceding MaxGapMs, through a rolling
+// hash. A run of consecutive matches at the same distance is a stretch of audio equal to what came distance
+// frames before it.
+type detector struct {
+	block  int // frames per block
+	maxGap int // frames
+	minLvl float64
+	rate   float64
+
+	frameHashes []uint64  // ring of the last block frame hashes
+	levels      []float64 // ring of the last block frame levels
+	levelSum    float64
+	rolling     uint64
+	power       uint64 // rollingBase^block
+
+	blockHashes []uint64          // ring of the hashes of the blocks ending in the last maxGap+1 frames
+	seen        map[uint64]uint64 // block hash -> end frame of its latest occurrence
+
+	inRun                      bool
+	runStart, runEnd, distance uint64
+
+	frames uint64
+	result *types.RepeatResult
+}
+
+func newDetector(opts Options, sampleRate float64) *detector {
+	block := max(int(sampleRate*opts.BlockMs/1000), 1)
+	maxGap := max(int(sampleRate*opts.MaxGapMs/1000), block)
+
+	power := uint64(1)
+	for range block {
+		power *= rollingBase
+	}
+
+	return &detector{
+		block:       block,
+		maxGap:      maxGap,
+		minLvl:      math.Pow(10, opts.MinLevelDb/20),
+		rate:        sampleRate,
+		frameHashes: make([]uint64, block),
+		levels:      make([]float64, block),
+		power:       power,
+		blockHashes: make([]uint64, maxGap+1),
+		seen:        make(map[uint64]uint64, maxGap+1),
+		result:      &types.RepeatResult{},
+	}
+}
+
+// process takes one frame: its raw bytes (for hashing) and its level (largest absolute channel value, normalized).
+func (d *detector) process(frame []byte, level float64) {
+	hash := uint64(fnvOffset)
+	for _, b := range frame {
+		hash ^= uint64(b)
+		hash *= fnvPrime
+	}
+
+	slot := int(d.frames % uint64(d.block)) //nolint:gosec // block is positive
+	d.rolling = d.rolling*rollingBase + hash - d.frameHashes[slot]*d.power
+	d.frameHashes[slot] = hash
+	d.levelSum += level - d.levels[slot]
+	d.levels[slot] = level
+
+	end := d.frames
+	d.frames++
+
+	if d.frames < uint64(d.block) { //nolint:gosec // block is positive
+		return
+	}
+
+	// Forget the block now beyond reach.
+	ring := int(end % uint64(len(d.blockHashes))) //nolint:gosec // ring length is positive
+	if end >= uint64(len(d.blockHashes)) {
+		expired := end - uint64(len(d.blockHashes))
+		if at, ok := d.seen[d.blockHashes[ring]]; ok && at == expired {
+			delete(d.seen, d.blockHashes[ring])
+		}
+	}
+
+	prev, found := d.seen[d.rolling]
+	if found && d.levelSum/float64(d.block) >= d.minLvl {
+		d.match(end, end-prev)
+	} else {
+		d.closeRun()
+	}
+
+	d.seen[d.rolling] = end
+	d.blockHashes[ring] = d.rolling
+}
+
+func (d *detector) match(end, distance uint64) {
+	if d.inRun && distance == d.distance && end == d.runEnd+1 {
+		d.runEnd = end
+
+		return
+	}
+
+	d.closeRun()
+
+	d.inRun = true
+	d.runStart, d.runEnd, d.distance = end, end, distance
+}
+
+// closeRun reports the current run if it is a one-off repeat. Audio equal to itself over at least twice the
+// distance is periodic (a sustained synthetic tone, a static loop), not a stutter.
+func (d *detector) closeRun() {
+	if !d.inRun {
+		return
+	}
+
+	d.inRun = false
+
+	span := d.runEnd - d.runStart + uint64(d.block) //nolint:gosec // block is positive
+	if span >= 2*d.distance {
+		return
+	}
+
+	start := d.runStart + 1 - uint64(d.block) //nolint:gosec // block is positive
+	durationMs := float64(span) / d.rate * 1000
+
+	d.result.Events = append(d.result.Events, types.RepeatEvent{
+		Frame:      start,
+		TimeSec:    float64(start) / d.rate,
+		DurationMs: durationMs,
+		DistanceMs: float64(d.distance) / d.rate * 1000,
+	})
+	d.result.LongestMs = max(d.result.LongestMs, durationMs)
+}
+
+func Detect(reader io.Reader, format types.PCMFormat, opts Options) (*types.RepeatResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	defaults := DefaultOptions()
+	if opts.BlockMs == 0 {
+		opts.BlockMs = defaults.BlockMs
+	}
+
+	if opts.MaxGapMs == 0 {
+		opts.MaxGapMs = defaults.MaxGapMs
+	}
+
+	if opts.MinLevelDb == 0 {
+		opts.MinLevelDb = defaults.MinLevelDb
+	}
+
+	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
+	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
+	frameSize := bytesPerSample * numChannels
+
+	buf := make([]byte, frameSize*4096)
+
+	var maxVal float64
+
+	switch format.BitDepth {
+	case types.Depth16:
+		maxVal = shared.MaxValue16
+	case types.Depth24:
+		maxVal = shared.MaxValue24
+	case types.Depth32:
+		maxVal = shared.MaxValue32
+	default:
+	}
+
+	det := newDetector(opts, float64(format.SampleRate))
+
+	reader = shared.NewFrameReader(reader, frameSize)
+
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+
+			for i := 0; i < len(data); i += frameSize {
+				var level float64
+
+				for ch := range numChannels {
+					offset := i + ch*bytesPerSample
+
+					var sample float64
+
+					switch format.BitDepth {
+					case types.Depth16:
+						sample = float64(int16(binary.LittleEndian.Uint16(data[offset:])))
+					case types.Depth24:
+						raw := int32(data[offset]) | int32(data[offset+1])<<8 | int32(data[offset+2])<<16
+						if raw&0x800000 != 0 {
+							raw |= ^0xFFFFFF
+						}
+
+						sample = float64(raw)
+					case types.Depth32:
+						sample = float64(int32(binary.LittleEndian.Uint32(data[offset:])))
+					default:
+					}
+
+					level = max(level, math.Abs(sample)/maxVal)
+				}
+
+				det.process(data[i:i+frameSize], level)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+	}
+
+	det.closeRun()
+
+	det.result.Count = len(det.result.Events)
+	det.result.Frames = det.frames
+
+	return det.result, nil
+}
@@ -0,0 +1,71 @@
+package shared
+
+import (
+	"io"
+	"slices"
+)
+
+// ByteSwapReader wraps a PCM reader, reversing the bytes of every sample: big-endian PCM reads as little-endian.
+// Like FrameReader, a read ending mid-sample carries the partial sample over to the next read.
+type ByteSwapReader struct {
+	reader     io.Reader
+	sampleSize int
+	pending    []byte
+}
+
+// ByteSwapSeeker is a ByteSwapReader over an io.ReadSeeker. Seeks must land on sample boundaries.
+type ByteSwapSeeker struct {
+	ByteSwapReader
+}
+
+// NewByteSwapReader returns a byte-swapping reader for samples of sampleSize bytes. It is a *ByteSwapSeeker,
+// satisfying io.ReadSeeker, when reader is an io.ReadSeeker.
+func NewByteSwapReader(reader io.Reader, sampleSize int) io.Reader {
+	swap := ByteSwapReader{
+		reader:     reader,
+		sampleSize: sampleSize,
+		pending:    make([]byte, 0, sampleSize),
+	}
+
+	if _, ok := reader.(io.ReadSeeker); ok {
+		return &ByteSwapSeeker{ByteSwapReader: swap}
+	}
+
+	return &swap
+}
+
+// Read fills buf with whole, byte-swapped samples. buf must hold at least one sample.
+func (r *ByteSwapReader) Read(buf []byte) (int, error) {
+	for {
+		carried := copy(buf, r.pending)
+		n, err := r.reader.Read(buf[carried:])
+		total := carried + n
+		complete := total / r.sampleSize * r.sampleSize
+
+		r.pending = append(r.pending[:0], buf[complete:total]...)
+
+		for i := 0; i < complete; i += r.sampleSize {
+			slices.Reverse(buf[i : i+r.sampleSize])
+		}
+
+		if complete > 0 || err != nil {
+			return complete, err
+		}
+	}
+}
+
+// Close closes the underlying reader, if it is an io.Closer.
+func (r *ByteSwapReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Seek seeks the underlying reader, dropping any partial sample.
+func (r *ByteSwapSeeker) Seek(offset int64, whence int) (int64, error) {
+	r.pending = r.pending[:0]
+
+	return r.reader.(io.ReadSeeker).Seek(offset, whence) //nolint:forcetypeassert // checked by NewByteSwapReader
+}
@@ -0,0 +1,51 @@
+package shared
+
+import "io"
+
+// maxEmptyReads is how many reads in a row may return nothing before FrameReader gives up, as bufio does.
+const maxEmptyReads = 100
+
+// FrameReader wraps a PCM reader so that every read returns whole frames. A read ending mid-frame keeps the
+// partial frame's bytes and hands them back at the start of the next read: nothing is dropped, and channel
+// interleaving stays in sync whatever the underlying reader's chunk sizes. A partial frame left at EOF is
+// discarded, as it cannot be decoded.
+type FrameReader struct {
+	reader    io.Reader
+	frameSize int
+	pending   []byte
+}
+
+// NewFrameReader returns a FrameReader over reader, for frames of frameSize bytes.
+func NewFrameReader(reader io.Reader, frameSize int) *FrameReader {
+	return &FrameReader{
+		reader:    reader,
+		frameSize: frameSize,
+		pending:   make([]byte, 0, frameSize),
+	}
+}
+
+// Read fills buf with whole frames. buf must hold at least one frame. It fails with io.ErrNoProgress when the
+// underlying reader returns no data and no error maxEmptyReads times in a row.
+func (r *FrameReader) Read(buf []byte) (int, error) {
+	for empty := 0; empty < maxEmptyReads; {
+		carried := copy(buf, r.pending)
+		n, err := r.reader.Read(buf[carried:])
+		total := carried + n
+		complete := total / r.frameSize * r.frameSize
+
+		r.pending = append(r.pending[:0], buf[complete:total]...)
+
+		// Keep reading until a frame completes, so callers never see a spurious empty read.
+		if complete > 0 || err != nil {
+			return complete, err
+		}
+
+		if n == 0 {
+			empty++
+		} else {
+			empty = 0
+		}
+	}
+
+	return 0, io.ErrNoProgress
+}
@@ -0,0 +1,70 @@
+package shared_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/farcloser/haustorium/internal/audit/shared"
+)
+
+// TestFrameReaderSplitFrames reads 4-byte frames one byte at a time: every read returns whole frames, in order,
+// and the partial frame left at EOF is dropped.
+func TestFrameReaderSplitFrames(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("0123456789abcdefXY")
+	reader := shared.NewFrameReader(iotest.OneByteReader(bytes.NewReader(data)), 4)
+
+	var got []byte
+
+	buf := make([]byte, 8)
+
+	for {
+		n, err := reader.Read(buf)
+		if n%4 != 0 {
+			t.Fatalf("expected whole frames, got %d bytes", n)
+		}
+
+		got = append(got, buf[:n]...)
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+	}
+
+	if want := data[:16]; !bytes.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+// emptyReader returns no data and no error, forever.
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) {
+	return 0, nil
+}
+
+// TestFrameReaderNoProgress checks that a reader that never returns data fails the read instead of spinning.
+func TestFrameReaderNoProgress(t *testing.T) {
+	t.Parallel()
+
+	reader := shared.NewFrameReader(emptyReader{}, 4)
+
+	if n, err := reader.Read(make([]byte, 8)); n != 0 || !errors.Is(err, io.ErrNoProgress) {
+		t.Fatalf("expected io.ErrNoProgress, got %d bytes and %v", n, err)
+	}
+
+	// A partial frame followed by empty reads fails too, the partial frame kept.
+	reader = shared.NewFrameReader(io.MultiReader(bytes.NewReader([]byte("01")), emptyReader{}), 4)
+
+	if n, err := reader.Read(make([]byte, 8)); n != 0 || !errors.Is(err, io.ErrNoProgress) {
+		t.Fatalf("expected io.ErrNoProgress after a partial frame, got %d bytes and %v", n, err)
+	}
+}
@@ -0,0 +1,55 @@
+package shared
+
+import "io"
+
+// PaddingReader clears the padding byte of 24-bit samples left-justified in 32-bit little-endian containers (the
+// low byte, first of every 4), so that analyzers only see the 24 bits of the samples. It records whether any
+// padding byte was set: the samples are then likely full 32-bit, and the layout wrong.
+type PaddingReader struct {
+	reader io.Reader
+	offset int64
+	used   *bool
+}
+
+// PaddingSeeker is a PaddingReader over an io.ReadSeeker.
+type PaddingSeeker struct {
+	PaddingReader
+}
+
+// NewPaddingReader returns a padding-clearing reader, setting *used when a padding byte was set. It is a
+// *PaddingSeeker, satisfying io.ReadSeeker, when reader is an io.ReadSeeker.
+func NewPaddingReader(reader io.Reader, used *bool) io.Reader {
+	padding := PaddingReader{reader: reader, used: used}
+
+	if _, ok := reader.(io.ReadSeeker); ok {
+		return &PaddingSeeker{PaddingReader: padding}
+	}
+
+	return &padding
+}
+
+// Read reads from the underlying reader, clearing the padding bytes read.
+func (r *PaddingReader) Read(buf []byte) (int, error) {
+	n, err := r.reader.Read(buf)
+
+	for i := (4 - r.offset%4) % 4; i < int64(n); i += 4 {
+		if buf[i] != 0 {
+			buf[i] = 0
+			*r.used = true
+		}
+	}
+
+	r.offset += int64(n)
+
+	return n, err
+}
+
+// Seek seeks the underlying reader, keeping track of the sample boundaries.
+func (r *PaddingSeeker) Seek(offset int64, whence int) (int64, error) {
+	pos, err := r.reader.(io.ReadSeeker).Seek(offset, whence) //nolint:forcetypeassert // checked by NewPaddingReader
+	if err == nil {
+		r.offset = pos
+	}
+
+	return pos, err
+}
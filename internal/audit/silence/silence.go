@@ -28,6 +28,10 @@ func DefaultOptions() Options {
 }
 
 func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.SilenceResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if opts.ThresholdDb == 0 {
 		opts.ThresholdDb = -60.0
 	}
@@ -116,12 +120,13 @@ func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.SilenceRe
 				}
 
 				segments = append(segments, types.SilenceSegment{
-					StartSample: silenceStart,
-					EndSample:   silenceEnd,
-					StartSec:    float64(silenceStart) / float64(format.SampleRate),
-					EndSec:      float64(silenceEnd) / float64(format.SampleRate),
-					DurationSec: float64(silenceFrames) / float64(format.SampleRate),
-					RmsDb:       silenceDb,
+					StartSample:   silenceStart,
+					EndSample:     silenceEnd,
+					StartSec:      float64(silenceStart) / float64(format.SampleRate),
+					EndSec:        float64(silenceEnd) / float64(format.SampleRate),
+					DurationSec:   float64(silenceFrames) / float64(format.SampleRate),
+					RmsDb:         silenceDb,
+					IsDigitalZero: silenceSumSq == 0,
 				})
 			}
 
@@ -133,11 +138,12 @@ func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.SilenceRe
 		windowCount = 0
 	}
 
+	r = shared.NewFrameReader(r, frameSize)
+
 	for {
 		n, err := r.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -232,12 +238,13 @@ func Detect(r io.Reader, format types.PCMFormat, opts Options) (*types.SilenceRe
 			}
 
 			segments = append(segments, types.SilenceSegment{
-				StartSample: silenceStart,
-				EndSample:   currentFrame,
-				StartSec:    float64(silenceStart) / float64(format.SampleRate),
-				EndSec:      float64(currentFrame) / float64(format.SampleRate),
-				DurationSec: float64(silenceFrames) / float64(format.SampleRate),
-				RmsDb:       silenceDb,
+				StartSample:   silenceStart,
+				EndSample:     currentFrame,
+				StartSec:      float64(silenceStart) / float64(format.SampleRate),
+				EndSec:        float64(currentFrame) / float64(format.SampleRate),
+				DurationSec:   float64(silenceFrames) / float64(format.SampleRate),
+				RmsDb:         silenceDb,
+				IsDigitalZero: silenceSumSq == 0,
 			})
 		}
 	}
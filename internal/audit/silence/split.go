@@ -0,0 +1,33 @@
+package silence
+
+import "github.com/farcloser/haustorium/internal/types"
+
+// Split proposes track boundaries for a single continuous transfer (e.g. a vinyl side ripped as one file),
+// at every mid-track silence lasting at least minGapSec.
+//
+// The first track starts at the beginning of the file, with leading silence as its pregap. Trailing silence
+// belongs to the last track. Each later track gets the gap that precedes it as its pregap.
+func Split(result *types.SilenceResult, minGapSec float64) []types.TrackSplit {
+	tracks := []types.TrackSplit{{Number: 1}}
+
+	for idx, seg := range result.Segments {
+		trailing := idx == len(result.Segments)-1 && result.TrailingSec > 0
+
+		switch {
+		case seg.StartSample == 0:
+			tracks[0].StartSample = seg.EndSample
+			tracks[0].StartSec = seg.EndSec
+		case trailing, seg.DurationSec < minGapSec:
+		default:
+			tracks = append(tracks, types.TrackSplit{
+				Number:       len(tracks) + 1,
+				PregapSample: seg.StartSample,
+				StartSample:  seg.EndSample,
+				PregapSec:    seg.StartSec,
+				StartSec:     seg.EndSec,
+			})
+		}
+	}
+
+	return tracks
+}
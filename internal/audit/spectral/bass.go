@@ -0,0 +1,77 @@
+package spectral
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	bassPlateauLow     = 80.0 // bass band the cutoff is measured against
+	bassPlateauHigh    = 250.0
+	bassMinContentDb   = -30.0 // bass band relative to the reference level; below this, there is no bass to lose
+	bassCutoffDropDb   = 6.0   // the cutoff is where the level falls this far below the bass band
+	bassFloorDropDb    = 30.0  // an octave under the cutoff, the level must be at least this far below the bass band
+	bassMinCutoffHz    = 40.0  // high-pass filters at 20-30 Hz are routine mastering hygiene, not a defect
+	bassMinSharpness   = 24.0  // dB/octave (4th order and up); natural low-end rolloff is far gentler
+	bassMaxBinHz       = 12.0  // coarser FFT bins cannot resolve the low end
+	bassLowestAnalyzed = 15.0
+)
+
+// detectBassRolloff looks for a brick-wall high-pass filter: the low-end counterpart of the transcode cutoff.
+//
+// Walking down from the 80-250 Hz bass band, the cutoff is the first frequency where the averaged spectrum falls
+// 6 dB under it. The steepness is the level drop over the following octave. A steep drop (24 dB/octave or more)
+// to well under the bass band, at 40 Hz or above, means intended bass was filtered out. Material without
+// substantial bass is not assessed.
+func detectBassRolloff(result *types.SpectralResult, magDb []float64, binHz, refLevel float64) {
+	if binHz > bassMaxBinHz || len(magDb) == 0 {
+		return
+	}
+
+	plateau := bandAverage(magDb, bassPlateauLow, bassPlateauHigh, binHz)
+	if plateau-refLevel < bassMinContentDb {
+		return
+	}
+
+	level := func(freq float64) float64 {
+		bin := int(math.Round(freq / binHz))
+		low, high := max(bin-1, 1), min(bin+1, len(magDb)-1)
+
+		var sum float64
+		for i := low; i <= high; i++ {
+			sum += magDb[i]
+		}
+
+		return sum / float64(high-low+1)
+	}
+
+	var cutoff float64
+
+	for freq := bassPlateauLow; freq >= bassLowestAnalyzed; freq -= binHz {
+		if level(freq) < plateau-bassCutoffDropDb {
+			cutoff = freq
+
+			break
+		}
+	}
+
+	if cutoff == 0 {
+		return
+	}
+
+	below := max(cutoff/2, 1.5*binHz)
+
+	octaves := math.Log2(cutoff / below)
+	if octaves < 0.5 {
+		return
+	}
+
+	floor := level(below)
+	sharpness := (level(cutoff) - floor) / octaves
+
+	result.BassCutoffHz = cutoff
+	result.BassRolloffDbPerOct = sharpness
+	result.HasBassRolloff = cutoff >= bassMinCutoffHz && sharpness >= bassMinSharpness &&
+		plateau-floor >= bassFloorDropDb
+}
@@ -0,0 +1,100 @@
+package spectral
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	birdieLowHz        = 10000.0 // below, tonal peaks are mostly music
+	birdieHighHz       = 20000.0
+	birdieGapBins      = 8    // bins skipped on each side of a peak (~40 Hz): a tone lasting tens of ms is that wide
+	birdieSideBins     = 12   // bins averaged on each side, past the gap
+	birdieProminenceDb = 20.0 // over the louder side
+	birdieEmptyDb      = 50.0 // sides this far under the reference band: the encoder emptied them
+	birdieFloorDb      = 80.0 // peaks this far under the reference band are noise
+	birdieStableShare  = 0.1  // peaks recurring at the same frequency in more of the windows are stable tones
+	birdiePerWindow    = 3    // unstable peaks a window needs to count
+	birdieMinShare     = 0.25 // share of the windows with birdies over which the track has them
+	birdieMinWindows   = 20
+)
+
+// detectBirdies looks for "birdies": the brief, unstable tones low-bitrate codecs leave in the high band, when
+// the bits run out and the encoder keeps a few isolated spectral lines, different from one frame to the next.
+// In each window, between 10 and 20 kHz, a birdie is a peak 20 dB over the louder of its two sides, themselves
+// 50 dB under the reference band: an island in an emptied band. A cymbal or a string harmonic stands on a
+// broadband bed instead, and a brick-wall edge has a loud side. Peaks at a frequency that recurs in more than 10%
+// of the windows are stable tones (hum harmonics, pilot tones, watermarks), not birdies. The track has birdies
+// when at least a quarter of the windows hold three or more.
+func detectBirdies(result *types.SpectralResult, windowMagnitudes [][]float64, binHz, nyquist, refLevel float64) {
+	if len(windowMagnitudes) < birdieMinWindows {
+		return
+	}
+
+	binCount := len(windowMagnitudes[0])
+	margin := birdieGapBins + birdieSideBins
+	lowBin := max(int(birdieLowHz/binHz), margin)
+	highBin := min(int(min(birdieHighHz, nyquist-500)/binHz), binCount-margin-1)
+
+	if highBin <= lowBin {
+		return
+	}
+
+	floor := refLevel - birdieFloorDb
+	empty := refLevel - birdieEmptyDb
+	peaks := make([][]int, len(windowMagnitudes))
+	counts := make([]int, binCount)
+	decibels := make([]float64, binCount)
+	prefix := make([]float64, binCount+1)
+
+	for w, magnitudes := range windowMagnitudes {
+		for i := lowBin - margin; i <= highBin+margin; i++ {
+			decibels[i] = -120
+			if magnitudes[i] > 0 {
+				decibels[i] = 20 * math.Log10(magnitudes[i])
+			}
+
+			prefix[i+1] = prefix[i] + decibels[i]
+		}
+
+		// Side averages, in dB, from the running sum.
+		side := func(from int) float64 {
+			return (prefix[from+birdieSideBins] - prefix[from]) / birdieSideBins
+		}
+
+		for i := lowBin; i <= highBin; i++ {
+			level := decibels[i]
+			if level < floor || level <= decibels[i-1] || level < decibels[i+1] {
+				continue
+			}
+
+			below, above := side(i-margin), side(i+birdieGapBins+1)
+			if louder := max(below, above); louder < empty && level-louder >= birdieProminenceDb {
+				peaks[w] = append(peaks[w], i)
+				counts[i]++
+			}
+		}
+	}
+
+	stable := max(int(birdieStableShare*float64(len(windowMagnitudes))), 1)
+
+	var withBirdies int
+
+	for _, bins := range peaks {
+		unstable := 0
+
+		for _, i := range bins {
+			if counts[i-1]+counts[i]+counts[i+1] <= stable {
+				unstable++
+			}
+		}
+
+		if unstable >= birdiePerWindow {
+			withBirdies++
+		}
+	}
+
+	result.BirdieWindowShare = float64(withBirdies) / float64(len(windowMagnitudes))
+	result.HasBirdies = result.BirdieWindowShare >= birdieMinShare
+}
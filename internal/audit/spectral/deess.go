@@ -0,0 +1,104 @@
+package spectral
+
+import (
+	"math"
+	"slices"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	deEssSubFrame     = 1024 // ~23 ms at 44.1 kHz: short enough to resolve a sibilant
+	deEssSibilantLow  = 5000
+	deEssSibilantHigh = 9000
+	deEssAirLow       = 9000
+	deEssAirHigh      = 14000
+	deEssOnsetDb      = 6.0 // air band rise over the window median that marks a sibilant
+	deEssDeficitDb    = 6.0 // sibilance band rising this much less than the air band is a duck
+)
+
+// detectDeEssing looks for the sibilance band being pulled down exactly when sibilants hit.
+//
+// Each spectral window is split into short sub-frames, and the 5-9 kHz (sibilance) and 9-14 kHz (air) band
+// energies are tracked relative to their window median. A natural sibilant is broadband noise: both bands rise
+// together. Behind an aggressive split-band de-esser, the air band still rises while 5-9 kHz is ducked.
+// DeEssPumpingIndex is the fraction of sibilant sub-frames where that happens.
+func detectDeEssing(result *types.SpectralResult, samples []float64, positions []int, fftSize, sampleRate int) {
+	nyquist := float64(sampleRate) / 2
+	if nyquist < deEssAirHigh || fftSize < deEssSubFrame {
+		return
+	}
+
+	window := makeHannWindow(deEssSubFrame)
+	fft := fourier.NewFFT(deEssSubFrame)
+	fftIn := make([]float64, deEssSubFrame)
+	binHz := float64(sampleRate) / float64(deEssSubFrame)
+
+	subFrames := fftSize / deEssSubFrame
+	sibilance := make([]float64, subFrames)
+	air := make([]float64, subFrames)
+
+	var coeffs []complex128
+
+	var sibilantFrames, duckedFrames int
+
+	for _, pos := range positions {
+		for sub := range subFrames {
+			offset := pos + sub*deEssSubFrame
+
+			for i := range deEssSubFrame {
+				fftIn[i] = samples[offset+i] * window[i]
+			}
+
+			coeffs = fft.Coefficients(coeffs, fftIn)
+			sibilance[sub] = bandPowerDb(coeffs, deEssSibilantLow, deEssSibilantHigh, binHz)
+			air[sub] = bandPowerDb(coeffs, deEssAirLow, deEssAirHigh, binHz)
+		}
+
+		sibilanceBase := median(sibilance)
+		airBase := median(air)
+
+		for sub := range subFrames {
+			airRise := air[sub] - airBase
+			if airRise < deEssOnsetDb {
+				continue
+			}
+
+			sibilantFrames++
+
+			if airRise-(sibilance[sub]-sibilanceBase) >= deEssDeficitDb {
+				duckedFrames++
+			}
+		}
+	}
+
+	result.SibilantEvents = sibilantFrames
+	if sibilantFrames > 0 {
+		result.DeEssPumpingIndex = float64(duckedFrames) / float64(sibilantFrames)
+	}
+}
+
+func bandPowerDb(coeffs []complex128, lowHz, highHz, binHz float64) float64 {
+	startBin := int(lowHz / binHz)
+	endBin := min(int(highHz/binHz), len(coeffs)-1)
+
+	var power float64
+	for i := startBin; i <= endBin; i++ {
+		power += real(coeffs[i])*real(coeffs[i]) + imag(coeffs[i])*imag(coeffs[i])
+	}
+
+	if power <= 0 {
+		return -120
+	}
+
+	return 10 * math.Log10(power)
+}
+
+func median(values []float64) float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,74 @@
+package spectral
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// detectEdgeNoiseFloor measures the noise floor of the leading and trailing silence alone: with no music to mask
+// it, the HF level there is the transfer chain itself (stylus and groove, tape hiss, the ADC). It uses the same
+// scale as NoiseFloorDb (14-18 kHz, relative to the full-track reference band), over every whole FFT window that
+// fits inside the silent edges.
+func detectEdgeNoiseFloor(
+	result *types.SpectralResult,
+	samples []float64,
+	fftSize, leadIn, leadOut int,
+	sampleRate int,
+	refLevel float64,
+) {
+	binHz := float64(sampleRate) / float64(fftSize)
+	hfStart := int(14000 / binHz)
+	hfEnd := int(min(18000, float64(sampleRate)/2-500) / binHz)
+
+	if hfEnd <= hfStart {
+		return
+	}
+
+	var starts []int
+
+	for pos := 0; pos+fftSize <= min(leadIn, len(samples)); pos += fftSize {
+		starts = append(starts, pos)
+	}
+
+	for pos := max(len(samples)-leadOut, leadIn); pos+fftSize <= len(samples); pos += fftSize {
+		starts = append(starts, pos)
+	}
+
+	if len(starts) == 0 {
+		return
+	}
+
+	window := makeHannWindow(fftSize)
+	fft := fourier.NewFFT(fftSize)
+	fftIn := make([]float64, fftSize)
+
+	var (
+		coeffs []complex128
+		hfSum  float64
+	)
+
+	for _, pos := range starts {
+		for i := range fftSize {
+			fftIn[i] = samples[pos+i] * window[i]
+		}
+
+		coeffs = fft.Coefficients(coeffs, fftIn)
+
+		var bandSum float64
+		for i := hfStart; i < hfEnd; i++ {
+			bandSum += math.Hypot(real(coeffs[i]), imag(coeffs[i]))
+		}
+
+		hfSum += bandSum / float64(hfEnd-hfStart)
+	}
+
+	result.LeadInNoiseSec = float64(len(starts)*fftSize) / float64(sampleRate)
+	result.LeadInNoiseFloorDb = -120
+
+	if avg := hfSum / float64(len(starts)); avg > 0 {
+		result.LeadInNoiseFloorDb = 20*math.Log10(avg) - refLevel
+	}
+}
@@ -0,0 +1,59 @@
+package spectral
+
+import (
+	"math"
+	"sort"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	toneLowestHz      = 20.0
+	toneHighestHz     = 5000.0 // the 5th harmonic must stay well under Nyquist
+	toneMinProminence = 40.0   // dB over the median spectrum: the material is a single dominant tone
+	toneSearchBins    = 2      // harmonic peaks are searched within this many bins of k * f0
+)
+
+// detectDominantTone finds the strongest spectral peak and, when it dominates the spectrum (a test tone, a sustained
+// note), measures its odd harmonics. Hard clipping of a tone generates odd harmonics; a clean full-scale tone has none.
+// On complex material, harmonics cannot be told apart from the music, and only the prominence is reported.
+func detectDominantTone(result *types.SpectralResult, magDb []float64, binHz, nyquist float64) {
+	lowBin := int(math.Ceil(toneLowestHz / binHz))
+	highBin := min(int(toneHighestHz/binHz), int(nyquist/5/binHz), len(magDb)-1)
+
+	if lowBin >= highBin {
+		return
+	}
+
+	peakBin := lowBin
+	for bin := lowBin; bin <= highBin; bin++ {
+		if magDb[bin] > magDb[peakBin] {
+			peakBin = bin
+		}
+	}
+
+	sorted := append([]float64(nil), magDb[lowBin:]...)
+	sort.Float64s(sorted)
+
+	result.DominantToneHz = float64(peakBin) * binHz
+	result.DominantToneProminenceDb = magDb[peakBin] - sorted[len(sorted)/2]
+
+	if result.DominantToneProminenceDb < toneMinProminence {
+		return
+	}
+
+	result.ToneDominated = true
+
+	harmonic := func(order int) float64 {
+		center := peakBin * order
+		level := -math.MaxFloat64
+
+		for bin := max(center-toneSearchBins, 0); bin <= min(center+toneSearchBins, len(magDb)-1); bin++ {
+			level = max(level, magDb[bin])
+		}
+
+		return level - magDb[peakBin]
+	}
+
+	result.OddHarmonicsDb = max(harmonic(3), harmonic(5))
+}
@@ -0,0 +1,75 @@
+package spectral
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	hfLowestHz       = 5000.0  // the roll-off is searched above this
+	hfHighestHz      = 20000.0 // content reaching this is full bandwidth, whatever the sample rate
+	hfStepHz         = 250.0
+	hfEdgeDropDb     = 30.0 // the roll-off point is where the level falls this far under the reference band
+	hfBrickSharpness = 60.0 // dB/octave across the edge (detectBrickWall); filters and codecs, not sources
+	hfMatchHz        = 1500.0
+
+	HfFullBandwidth = "full-bandwidth"
+	HfBrickWall     = "brick-wall"
+	HfGentle        = "gentle"
+)
+
+// detectHfRolloff describes how the high end ends: informational context for the transcode and sample rate
+// checks, which only report brick walls.
+//
+// Walking up from 5 kHz, the roll-off point is the first frequency where the averaged spectrum falls 30 dB under
+// the reference band. Content that holds up to 20 kHz (or near Nyquist) is full bandwidth. Otherwise, the edge is
+// a brick wall if it is steep (an anti-alias or lowpass filter, a codec), and gentle if it is not: the
+// bandwidth of the source itself (an old recording, tape, a dull microphone), not something done to the file.
+func detectHfRolloff(result *types.SpectralResult, magDb []float64, binHz, nyquist, refLevel float64) {
+	if len(magDb) == 0 {
+		return
+	}
+
+	level := func(freq float64) float64 {
+		return bandAverage(magDb, freq-hfStepHz, freq+hfStepHz, binHz)
+	}
+
+	highest := min(hfHighestHz, nyquist-2*hfStepHz)
+
+	var edge float64
+
+	for freq := hfLowestHz; freq <= highest; freq += hfStepHz {
+		if level(freq) < refLevel-hfEdgeDropDb {
+			edge = freq
+
+			break
+		}
+	}
+
+	if edge == 0 {
+		result.HfRolloffCharacter = HfFullBandwidth
+
+		return
+	}
+
+	result.HfRolloffHz = edge
+
+	below := edge / math.Sqrt2
+	above := min(edge*math.Sqrt2, nyquist-2*hfStepHz)
+
+	if octaves := math.Log2(above / below); octaves > 0 {
+		result.HfRolloffDbPerOct = (level(below) - level(above)) / octaves
+	}
+
+	_, sharpness := detectBrickWall(magDb, edge, binHz)
+
+	switch {
+	case sharpness >= hfBrickSharpness,
+		result.IsTranscode && math.Abs(result.TranscodeCutoff-edge) < hfMatchHz,
+		result.IsUpsampled && math.Abs(result.UpsampleCutoff-edge) < hfMatchHz:
+		result.HfRolloffCharacter = HfBrickWall
+	default:
+		result.HfRolloffCharacter = HfGentle
+	}
+}
@@ -0,0 +1,79 @@
+package spectral
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	hfRegenHighHz       = 17000.0 // the high band, to 20 kHz: what lossy encodes drop, then refill
+	hfRegenBelowLowHz   = 8000.0  // the band under it carries the music the high band should follow
+	hfRegenBelowHighHz  = 15000.0 // leaving out the transition band of a 16 kHz cutoff
+	hfRegenSilentDb     = 70.0    // windows whose lower band is this far under the reference band are left out
+	hfRegenMinLevelDb   = -35.0   // high band level over the lower band: quieter, it is no refill worth the name
+	hfRegenMinFlatness  = 0.8     // Wiener entropy of the high band magnitudes; white noise is ~0.88
+	hfRegenMaxCorrelate = 0.3     // high band level against the lower band, across the windows
+	hfRegenMinWindows   = 20
+)
+
+// detectHfRegeneration looks for a high band refilled after a lossy encode: spectral band replication or noise
+// filling over the cutoff, which defeat the brick wall search. A recorded high band carries the music that
+// extends into it, cymbals, breath, the air of the room, and its level follows the band under it, window after
+// window. A refill is noise laid at a plausible level: flat (Wiener entropy close to white noise), and unrelated
+// to the music under it (level correlation under 0.3 across the windows). A noise floor is flat and unrelated
+// too, but much quieter: the high band must reach 35 dB under the 8-16 kHz band, in the median window.
+func detectHfRegeneration(
+	result *types.SpectralResult,
+	windowMagnitudes [][]float64,
+	binHz, nyquist, refLevel float64,
+) {
+	highEnd := min(20000, nyquist-500)
+	if highEnd <= hfRegenHighHz+1000 {
+		return
+	}
+
+	belowLow, belowHigh := int(hfRegenBelowLowHz/binHz), int(hfRegenBelowHighHz/binHz)
+	highLow, highHigh := int(hfRegenHighHz/binHz), int(highEnd/binHz)
+	if len(windowMagnitudes) == 0 || highHigh >= len(windowMagnitudes[0]) {
+		return
+	}
+
+	var highLevels, belowLevels, gaps []float64
+
+	var flatness float64
+
+	for _, magnitudes := range windowMagnitudes {
+		below := bandPowerDbMagnitudes(magnitudes[belowLow:belowHigh])
+		if below < refLevel-hfRegenSilentDb {
+			continue
+		}
+
+		high := bandPowerDbMagnitudes(magnitudes[highLow:highHigh])
+		highLevels = append(highLevels, high)
+		belowLevels = append(belowLevels, below)
+		gaps = append(gaps, high-below)
+		flatness += spectralFlatness(magnitudes[highLow:highHigh])
+	}
+
+	if len(highLevels) < hfRegenMinWindows {
+		return
+	}
+
+	result.HfFlatness = flatness / float64(len(highLevels))
+	result.HfEnvelopeCorrelation = pearson(highLevels, belowLevels)
+	result.HfRegenerationLikely = median(gaps) >= hfRegenMinLevelDb &&
+		result.HfFlatness >= hfRegenMinFlatness &&
+		result.HfEnvelopeCorrelation < hfRegenMaxCorrelate
+}
+
+// bandPowerDbMagnitudes returns the mean power of magnitudes, in dB.
+func bandPowerDbMagnitudes(magnitudes []float64) float64 {
+	var power float64
+
+	for _, magnitude := range magnitudes {
+		power += magnitude * magnitude
+	}
+
+	return 10 * math.Log10(max(power/float64(len(magnitudes)), 1e-20))
+}
@@ -0,0 +1,145 @@
+package spectral
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	notchLowHz         = 200.0   // below, the room modes and the bass arrangement dig their own holes
+	notchHighHz        = 16000.0 // above, lossy cutoffs and anti-alias filters bend the spectrum
+	notchContextOctave = 1.0 / 3 // the median over this much on each side is the spectrum around a notch
+	notchStepOctave    = 1.0 / 24
+	notchMinDepthDb    = 12.0
+	notchEdgeDb        = 6.0    // the notch spans the bins this far under the spectrum around it
+	notchMaxWidthOct   = 0.25   // wider, it is a broad dip of the balance, not a notch
+	notchDenseDb       = 6.0    // lower quartile of the context within this of its median: a dense spectrum
+	notchFloorDb       = 80.0   // contexts this far under the reference band hold no music
+	notchMaxReported   = 8      // deepest kept
+	notchMinBins       = 2      // narrower than a Hann main lobe: a sidelobe null, not a filter
+	notchHighFraction  = 0.9    // keep clear of the Nyquist frequency's anti-alias slope
+	notchZeroDb        = -120.0 // toDb's floor
+)
+
+// detectNotches looks for narrow holes in the average spectrum, between 200 Hz and 16 kHz: a notch filter, the
+// crossover of a playback chain, a comb of a processing chain. The spectrum around every frequency is the median
+// of the bins a third of an octave on each side; a notch is a run of bins 6 dB or more under it, at most a quarter
+// of an octave wide, and 12 dB deep at its deepest. Line spectra (a solo instrument, an organ drone), whose
+// median is the floor between the lines, are left out: the lower quartile of the context must sit within 6 dB of
+// its median. The deepest 8 are reported, by frequency: along with their depth, they are the chain's signature.
+func detectNotches(result *types.SpectralResult, magDb []float64, binHz, nyquist, refLevel float64) {
+	highHz := min(notchHighHz, nyquist*notchHighFraction)
+	if highHz <= notchLowHz*math.Pow(2, notchContextOctave) {
+		return
+	}
+
+	// The spectrum around each frequency, on a 1/24 octave grid, and whether it is dense there.
+	var gridOct, gridMedian []float64
+
+	var gridDense []bool
+
+	for octave := math.Log2(notchLowHz); octave <= math.Log2(highHz); octave += notchStepOctave {
+		center := math.Exp2(octave)
+		low := max(int(center/math.Exp2(notchContextOctave)/binHz), 1)
+		high := min(int(center*math.Exp2(notchContextOctave)/binHz), len(magDb)-1)
+
+		if high-low < 8 {
+			continue
+		}
+
+		context := slices.Clone(magDb[low : high+1])
+		slices.Sort(context)
+
+		median := context[len(context)/2]
+		quartile := context[len(context)/4]
+
+		gridOct = append(gridOct, octave)
+		gridMedian = append(gridMedian, median)
+		gridDense = append(gridDense, median-quartile <= notchDenseDb && median >= refLevel-notchFloorDb)
+	}
+
+	if len(gridOct) < 2 {
+		return
+	}
+
+	// around interpolates the grid at a bin, in log frequency; dense tells whether the nearest point is dense.
+	around := func(bin int) (float64, bool) {
+		octave := math.Log2(float64(bin) * binHz)
+
+		idx, _ := slices.BinarySearch(gridOct, octave)
+		switch {
+		case idx == 0:
+			return gridMedian[0], gridDense[0]
+		case idx >= len(gridOct):
+			return gridMedian[len(gridOct)-1], gridDense[len(gridOct)-1]
+		default:
+		}
+
+		frac := (octave - gridOct[idx-1]) / (gridOct[idx] - gridOct[idx-1])
+		nearest := idx
+		if frac < 0.5 {
+			nearest = idx - 1
+		}
+
+		return gridMedian[idx-1] + frac*(gridMedian[idx]-gridMedian[idx-1]), gridDense[nearest]
+	}
+
+	var notches []types.SpectralNotch
+
+	lowBin := int(notchLowHz / binHz)
+	highBin := min(int(highHz/binHz), len(magDb)-1)
+
+	for bin := lowBin; bin <= highBin; bin++ {
+		baseline, dense := around(bin)
+		if !dense || magDb[bin] > baseline-notchEdgeDb {
+			continue
+		}
+
+		// A run of bins under the edge: its deepest bin against the spectrum around it.
+		start := bin
+		deepest, depth := bin, baseline-magDb[bin]
+
+		for bin+1 <= highBin {
+			next, _ := around(bin + 1)
+			if magDb[bin+1] > next-notchEdgeDb {
+				break
+			}
+
+			bin++
+
+			if next-magDb[bin] > depth {
+				deepest, depth = bin, next-magDb[bin]
+			}
+		}
+
+		startHz := (float64(start) - 0.5) * binHz
+		endHz := (float64(bin) + 0.5) * binHz
+
+		if depth < notchMinDepthDb || bin-start+1 < notchMinBins || math.Log2(endHz/startHz) > notchMaxWidthOct {
+			continue
+		}
+
+		if magDb[deepest] <= notchZeroDb {
+			continue // digital zero in a bin: a synthetic signal, not a chain
+		}
+
+		notches = append(notches, types.SpectralNotch{
+			Hz:      float64(deepest) * binHz,
+			DepthDb: depth,
+			WidthHz: endHz - startHz,
+		})
+	}
+
+	if len(notches) > notchMaxReported {
+		slices.SortStableFunc(notches, func(a, b types.SpectralNotch) int { return cmp.Compare(b.DepthDb, a.DepthDb) })
+
+		notches = notches[:notchMaxReported]
+	}
+
+	slices.SortFunc(notches, func(a, b types.SpectralNotch) int { return cmp.Compare(a.Hz, b.Hz) })
+
+	result.Notches = notches
+}
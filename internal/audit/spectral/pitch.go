@@ -0,0 +1,97 @@
+package spectral
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	pitchLowestHz      = 250.0  // below this, a bin spans too many cents to place a peak precisely
+	pitchHighestHz     = 4000.0 // above this, partials thin out and inharmonicity takes over
+	pitchMinProminence = 20.0   // dB over the window's median spectrum in the searched range
+	pitchPeaksPerWin   = 10     // strongest peaks kept per window
+	pitchMinPeaks      = 50     // peaks needed across the track for an estimate
+	pitchMinCoherence  = 0.5    // peaks must agree on the offset (mean resultant length, 0-1)
+
+	// PitchReferenceHz is the default tuning reference, A4.
+	PitchReferenceHz = 440.0
+)
+
+type pitchPeak struct {
+	hz     float64
+	weight float64
+}
+
+// detectPitchOffset estimates the global tuning offset of the tonal content from equal temperament. Each
+// window's prominent peaks are placed precisely (parabolic interpolation), and their deviations from the nearest
+// equal-tempered note are averaged on the circle: deviations wrap at ±50 cents, where a peak is as far from
+// the note below as from the note above. A coherent offset means the whole track is shifted: a speed error
+// on a transfer, or an instrument tuned off the reference.
+func detectPitchOffset(result *types.SpectralResult, windowMagnitudes [][]float64, binHz, referenceHz float64) {
+	lowBin := int(math.Ceil(pitchLowestHz / binHz))
+
+	var sumSin, sumCos, sumWeight float64
+
+	peaks := 0
+
+	for _, magnitudes := range windowMagnitudes {
+		highBin := min(int(pitchHighestHz/binHz), len(magnitudes)-2)
+		if highBin-lowBin < 8 {
+			return
+		}
+
+		magDb := toDb(magnitudes[lowBin-1 : highBin+2])
+
+		sorted := slices.Clone(magDb)
+		slices.Sort(sorted)
+		median := sorted[len(sorted)/2]
+
+		var found []pitchPeak
+
+		for i := 2; i < len(magDb)-2; i++ {
+			level := magDb[i]
+			if level-median < pitchMinProminence ||
+				level <= magDb[i-1] || level < magDb[i+1] || level <= magDb[i-2] || level < magDb[i+2] {
+				continue
+			}
+
+			offset := 0.0
+			if curvature := magDb[i-1] - 2*level + magDb[i+1]; curvature < 0 {
+				offset = 0.5 * (magDb[i-1] - magDb[i+1]) / curvature
+			}
+
+			found = append(found, pitchPeak{
+				hz:     (float64(lowBin-1+i) + offset) * binHz,
+				weight: level - median,
+			})
+		}
+
+		slices.SortFunc(found, func(a, b pitchPeak) int { return cmp.Compare(b.weight, a.weight) })
+
+		for _, peak := range found[:min(len(found), pitchPeaksPerWin)] {
+			cents := 1200 * math.Log2(peak.hz/referenceHz)
+			angle := 2 * math.Pi * cents / 100
+
+			sumSin += peak.weight * math.Sin(angle)
+			sumCos += peak.weight * math.Cos(angle)
+			sumWeight += peak.weight
+			peaks++
+		}
+	}
+
+	result.PitchPeaks = peaks
+	if peaks < pitchMinPeaks || sumWeight == 0 {
+		return
+	}
+
+	result.PitchCoherence = math.Hypot(sumSin, sumCos) / sumWeight
+	if result.PitchCoherence < pitchMinCoherence {
+		return
+	}
+
+	result.PitchOffsetCents = math.Atan2(sumSin, sumCos) * 100 / (2 * math.Pi)
+	result.HasPitchReference = true
+}
@@ -4,6 +4,8 @@ package spectral
 import (
 	"io"
 	"math"
+	"runtime"
+	"sync"
 
 	"gonum.org/v1/gonum/dsp/fourier"
 
@@ -13,6 +15,10 @@ import (
 // AnalyzeV2 adds temporal variance analysis to reduce false positives for hum
 // and noise floor detection on legitimately dark or bass-heavy recordings.
 func AnalyzeV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.SpectralResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if opts.FFTSize == 0 {
 		opts.FFTSize = 8192
 	}
@@ -51,31 +57,14 @@ func AnalyzeV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.S
 	// Phase 3: Process FFT windows, keeping per-window data for variance analysis.
 	window := makeHannWindow(fftSize)
 	binCount := fftSize/2 + 1
-	magnitudeSum := make([]float64, binCount)
-	fft := fourier.NewFFT(fftSize)
-	fftIn := make([]float64, fftSize)
-
-	// Per-window storage for variance analysis.
-	windowMagnitudes := make([][]float64, len(positions))
-	windowRMS := make([]float64, len(positions)) // overall RMS per window for quiet detection
-
-	for windowIdx, pos := range positions {
-		var rmsSum float64
-
-		for i := range fftSize {
-			fftIn[i] = samples[pos+i] * window[i]
-			rmsSum += samples[pos+i] * samples[pos+i]
-		}
-
-		windowRMS[windowIdx] = math.Sqrt(rmsSum / float64(fftSize))
 
-		coeffs := fft.Coefficients(nil, fftIn)
+	windowMagnitudes, windowRMS := processWindows(samples, positions, window, opts.Workers)
 
-		windowMagnitudes[windowIdx] = make([]float64, binCount)
+	// Summed in window order, whatever the worker count: results are identical to a sequential run.
+	magnitudeSum := make([]float64, binCount)
 
-		for i, c := range coeffs {
-			mag := math.Sqrt(real(c)*real(c) + imag(c)*imag(c))
-			windowMagnitudes[windowIdx][i] = mag
+	for _, magnitudes := range windowMagnitudes {
+		for i, mag := range magnitudes {
 			magnitudeSum[i] += mag
 		}
 	}
@@ -93,8 +82,9 @@ func AnalyzeV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.S
 
 	magDb := toDb(avgMagnitude)
 
-	// Reference level: 1-10 kHz average.
-	refLevel := bandAverage(magDb, 1000, 10000, binHz)
+	// Reference level: reference band average (1-10 kHz by default).
+	refLow, refHigh := opts.referenceBand(nyquist)
+	refLevel := bandAverage(magDb, refLow, refHigh, binHz)
 
 	result := &types.SpectralResult{
 		ClaimedRate: format.SampleRate,
@@ -102,22 +92,61 @@ func AnalyzeV2(reader io.Reader, format types.PCMFormat, opts Options) (*types.S
 	}
 
 	// === Sample rate authenticity ===
-	if format.SampleRate > 44100 {
-		detectUpsampling(result, magDb, binHz, nyquist, refLevel)
-	}
+	detectUpsampling(result, magDb, binHz, nyquist, refLevel)
+
+	// === Codec birdies (corroborate lossy transcodes) ===
+	detectBirdies(result, windowMagnitudes, binHz, nyquist, refLevel)
+
+	// === Regenerated high band (corroborates lossy transcodes) ===
+	detectHfRegeneration(result, windowMagnitudes, binHz, nyquist, refLevel)
 
 	// === Lossy transcode detection V2 (with consistency analysis) ===
-	detectTranscodeV2(result, windowMagnitudes, magDb, binHz, nyquist, refLevel)
+	detectTranscodeV2(result, windowMagnitudes, windowRMS, magDb, binHz, nyquist, refLevel)
 
 	// === Hum detection V2 (with variance) ===
 	detectHumV2(result, windowMagnitudes, binHz, refLevel)
 
+	// === Constant tones away from the mains (same variance test, every bin) ===
+	detectConstantTones(result, windowMagnitudes, binHz, nyquist, refLevel)
+
 	// === Noise floor V2 (quiet-window HF + full-track reference + RMS gate) ===
 	detectNoiseFloorV2(result, windowMagnitudes, windowRMS, magDb, binHz, nyquist, refLevel, opts)
 
+	if opts.LeadInFrames > 0 || opts.LeadOutFrames > 0 {
+		detectEdgeNoiseFloor(
+			result, samples, fftSize, opts.LeadInFrames, opts.LeadOutFrames, format.SampleRate, refLevel,
+		)
+	}
+
+	// === Low-end brick wall ===
+	detectBassRolloff(result, magDb, binHz, refLevel)
+
+	// === High-end character (brick wall vs natural roll-off) ===
+	detectHfRolloff(result, magDb, binHz, nyquist, refLevel)
+
+	// === Dominant tone and its distortion products (corroborates clipping) ===
+	detectDominantTone(result, magDb, binHz, nyquist)
+
+	// === Tuning offset from equal temperament (speed errors) ===
+	referenceHz := opts.PitchReferenceHz
+	if referenceHz <= 0 {
+		referenceHz = PitchReferenceHz
+	}
+
+	detectPitchOffset(result, windowMagnitudes, binHz, referenceHz)
+
+	// === De-essing (sub-frame band envelopes) ===
+	detectDeEssing(result, samples, positions, fftSize, format.SampleRate)
+
 	// === Spectral centroid ===
 	result.SpectralCentroid = calculateCentroid(avgMagnitude, binHz)
 
+	// === Spectral tilt (tonal balance against pink noise) ===
+	detectSpectralTilt(result, magDb, binHz, nyquist, refLevel)
+
+	// === Notches (chain signatures) ===
+	detectNotches(result, magDb, binHz, nyquist, refLevel)
+
 	// === Band energy for debugging ===
 	result.BandEnergy, result.BandFreqs = calculateBandEnergy(magDb, binHz, nyquist, refLevel)
 
@@ -238,11 +267,12 @@ func detectHumFrequencyV2(windowMagnitudes [][]float64, fundamental, binHz float
 // gated by an absolute RMS threshold on the quiet windows.
 //
 // Strategy:
-//   - HF energy (14-18 kHz) is measured from the quietest 20% of windows to expose the true
-//     noise floor without signal masking.
-//   - Reference level (1-10 kHz) comes from the full-track average for a stable baseline.
-//   - RMS gate: if the quiet windows are not actually quiet (above -40 dBFS), they contain
-//     signal, not noise. In that case, fall back to full-track HF measurement.
+//   - HF energy (14-18 kHz) is measured from the quietest windows (Options.QuietWindowsFraction, 20% by
+//     default) to expose the true noise floor without signal masking.
+//   - Reference level (Options reference band, 1-10 kHz by default) comes from the full-track average for a
+//     stable baseline.
+//   - RMS gate (Options.QuietGateDbFS, -50 dBFS by default): quiet windows under it hold the medium's own
+//     noise; in that case, fall back to full-track HF measurement.
 //   - Spectral flatness guard: suppress detection when HF energy is tonal (music, not noise).
 func detectNoiseFloorV2(
 	result *types.SpectralResult,
@@ -269,16 +299,25 @@ func detectNoiseFloorV2(
 		return
 	}
 
-	// Find the quietest 20% of windows (or at least 1).
-	quietCount := max(len(windowRMS)/5, 1)
+	fraction := opts.QuietWindowsFraction
+	if fraction <= 0 {
+		fraction = quietWindowsFraction
+	}
+
+	gateDbFS := opts.QuietGateDbFS
+	if gateDbFS == 0 {
+		gateDbFS = quietGateDbFS
+	}
+
+	// Find the quietest 20% of windows by default (or at least 1).
+	quietCount := max(int(float64(len(windowRMS))*min(fraction, 1)), 1)
 	quietIndices := findQuietestWindows(windowRMS, quietCount)
 
 	// RMS gate: check if quiet windows have enough signal for meaningful measurement.
-	// Below -50 dBFS, we're in recording-medium noise territory (dither, ADC noise)
+	// Below the gate (-50 dBFS by default), we're in recording-medium noise territory (dither, ADC noise)
 	// where the HF measurement reflects the medium, not a quality problem.
-	// Above -50 dBFS, quiet passages still contain enough musical signal for the
+	// Above it, quiet passages still contain enough musical signal for the
 	// quiet-window HF measurement to be more accurate than the full-track average.
-	const quietGateDbFS = -50.0
 
 	var quietRMSSum float64
 	for _, wi := range quietIndices {
@@ -292,7 +331,7 @@ func detectNoiseFloorV2(
 		quietRMSDb = 20 * math.Log10(avgQuietRMS)
 	}
 
-	useQuietWindows := quietRMSDb > quietGateDbFS
+	useQuietWindows := quietRMSDb > gateDbFS
 
 	var hfDb float64
 
@@ -450,6 +489,8 @@ func spectralFlatness(magnitudes []float64) float64 {
 // Key improvements over V1:
 //   - Measures cutoff consistency across windows (mastering LPFs are rock-solid, codecs may vary)
 //   - Checks for ultrasonic content above the cutoff (mastering may leave some, codecs don't)
+//   - Correlates the cutoff with loudness (VBR encoders raise it on louder, denser passages)
+//   - Takes codec birdies (detectBirdies) and a regenerated high band (detectHfRegeneration) as corroboration
 //   - Adjusts confidence based on these factors
 //
 // A 20-21 kHz cutoff on 44.1kHz content is ambiguous: it could be a legitimate mastering
@@ -458,6 +499,7 @@ func spectralFlatness(magnitudes []float64) float64 {
 func detectTranscodeV2(
 	result *types.SpectralResult,
 	windowMagnitudes [][]float64,
+	windowRMS []float64,
 	magDb []float64,
 	binHz, nyquist, refLevel float64,
 ) {
@@ -472,14 +514,26 @@ func detectTranscodeV2(
 	}
 
 	// Start with high confidence, reduce based on evidence.
+	// Each adjustment is retained, so that the final confidence can be explained.
 	confidence := 0.95
 	cutoffFreq := result.TranscodeCutoff
+	evidence := map[string]float64{
+		"base":              confidence,
+		"consistency":       0,
+		"ultrasonic":        0,
+		"frequency_penalty": 0,
+		"sharpness":         0,
+		"vbr":               0,
+		"birdies":           0,
+		"hf_regeneration":   0,
+	}
 
 	// === Check 1: Cutoff consistency across windows ===
 	// A mastering LPF creates identical cutoffs in every window.
 	// A codec's psychoacoustic model may cause slight variations.
-	cutoffStdDev := measureCutoffConsistency(windowMagnitudes, cutoffFreq, binHz)
+	cutoffStdDev, loudnessCorrelation := measureCutoffConsistency(windowMagnitudes, windowRMS, cutoffFreq, binHz)
 	result.CutoffConsistency = cutoffStdDev
+	result.CutoffLoudnessCorrelation = loudnessCorrelation
 
 	// Very low stddev (< 50 Hz) suggests mastering filter, not codec.
 	// Reduce confidence proportionally.
@@ -487,6 +541,7 @@ func detectTranscodeV2(
 		// Linear reduction: 0 Hz stddev -> -0.20 confidence, 50 Hz -> 0
 		reduction := 0.20 * (1 - cutoffStdDev/50)
 		confidence -= reduction
+		evidence["consistency"] = -reduction
 	}
 
 	// === Check 2: Ultrasonic content above cutoff ===
@@ -501,6 +556,7 @@ func detectTranscodeV2(
 		// Codecs cannot leave ultrasonic content - they completely eliminate it.
 		// This is the strongest signal we have.
 		confidence -= 0.40
+		evidence["ultrasonic"] = -0.40
 	}
 
 	// === Check 3: Cutoff frequency penalty for high frequencies ===
@@ -508,8 +564,9 @@ func detectTranscodeV2(
 	// Lower cutoffs (15-18 kHz) are more clearly codec-related.
 	if cutoffFreq >= 20000 {
 		// 20 kHz: -0.10, 20.5 kHz: -0.15, 21 kHz: -0.20
-		reduction := 0.10 + (cutoffFreq-20000)/5000*0.10
-		confidence -= min(reduction, 0.20)
+		reduction := min(0.10+(cutoffFreq-20000)/5000*0.10, 0.20)
+		confidence -= reduction
+		evidence["frequency_penalty"] = -reduction
 	}
 
 	// === Check 4: Sharpness analysis ===
@@ -520,8 +577,34 @@ func detectTranscodeV2(
 	if sharpness < 40 {
 		// Moderate slope is more consistent with mastering filter.
 		confidence -= 0.10
+		evidence["sharpness"] = -0.10
+	}
+
+	// === Check 5: Cutoff tracking loudness ===
+	// A VBR encoder spends more bits, and keeps more bandwidth, on loud dense passages: its cutoff moves with the
+	// level. Neither a mastering filter (constant) nor random measurement noise (uncorrelated) does that.
+	if cutoffStdDev >= vbrMinStdDevHz && loudnessCorrelation >= vbrMinCorrelation {
+		result.VbrLossySignature = true
+		confidence += 0.15
+		evidence["vbr"] = 0.15
+	}
+
+	// === Check 6: Birdies ===
+	// Unstable tonal islands in the high band are the encoder's, whatever the cutoff looks like.
+	if result.HasBirdies {
+		confidence += 0.15
+		evidence["birdies"] = 0.15
+	}
+
+	// === Check 7: Regenerated high band ===
+	// Noise laid over the cutoff, unrelated to the music under it, was put there after the encode.
+	if result.HfRegenerationLikely {
+		confidence += 0.15
+		evidence["hf_regeneration"] = 0.15
 	}
 
+	result.TranscodeEvidence = evidence
+
 	// Clamp confidence to valid range.
 	confidence = max(0.0, min(1.0, confidence))
 
@@ -536,12 +619,22 @@ func detectTranscodeV2(
 	result.TranscodeConfidence = confidence
 }
 
-// measureCutoffConsistency measures how consistent the cutoff frequency is across windows.
+const (
+	vbrMinStdDevHz    = 50.0 // below this, the cutoff is constant (CBR or mastering filter)
+	vbrMinCorrelation = 0.5  // cutoff vs window level (dB)
+)
+
+// measureCutoffConsistency measures how consistent the cutoff frequency is across windows, and how much it follows
+// the window level (Pearson correlation of the cutoff with the window RMS in dB, 0 if not measurable).
 // Returns the standard deviation of detected cutoff frequencies.
 // Low stddev = consistent (mastering filter), high stddev = variable (possibly codec).
-func measureCutoffConsistency(windowMagnitudes [][]float64, targetCutoff, binHz float64) float64 {
+func measureCutoffConsistency(
+	windowMagnitudes [][]float64,
+	windowRMS []float64,
+	targetCutoff, binHz float64,
+) (stddev, loudnessCorrelation float64) {
 	if len(windowMagnitudes) < 3 {
-		return 0 // not enough windows to measure consistency
+		return 0, 0 // not enough windows to measure consistency
 	}
 
 	// For each window, find the frequency where energy drops most sharply
@@ -550,9 +643,9 @@ func measureCutoffConsistency(windowMagnitudes [][]float64, targetCutoff, binHz
 	searchEnd := targetCutoff + 2000
 	startBin := max(1, int(searchStart/binHz))
 
-	var cutoffs []float64
+	var cutoffs, levels []float64
 
-	for _, mag := range windowMagnitudes {
+	for windowIdx, mag := range windowMagnitudes {
 		magDb := toDb(mag)
 		endBin := min(len(magDb)-2, int(searchEnd/binHz))
 
@@ -577,11 +670,12 @@ func measureCutoffConsistency(windowMagnitudes [][]float64, targetCutoff, binHz
 
 		if maxDrop > 5 { // only count if there's a meaningful drop
 			cutoffs = append(cutoffs, float64(maxDropBin)*binHz)
+			levels = append(levels, 20*math.Log10(max(windowRMS[windowIdx], 1e-10)))
 		}
 	}
 
 	if len(cutoffs) < 3 {
-		return 0
+		return 0, 0
 	}
 
 	// Calculate standard deviation.
@@ -599,7 +693,35 @@ func measureCutoffConsistency(windowMagnitudes [][]float64, targetCutoff, binHz
 		varianceSum += d * d
 	}
 
-	return math.Sqrt(varianceSum / float64(len(cutoffs)))
+	return math.Sqrt(varianceSum / float64(len(cutoffs))), pearson(cutoffs, levels)
+}
+
+// pearson returns the correlation coefficient of two samples, 0 when either is constant.
+func pearson(xs, ys []float64) float64 {
+	var meanX, meanY float64
+
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var sumXY, sumXX, sumYY float64
+
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sumXY += dx * dy
+		sumXX += dx * dx
+		sumYY += dy * dy
+	}
+
+	if sumXX == 0 || sumYY == 0 {
+		return 0
+	}
+
+	return sumXY / math.Sqrt(sumXX*sumYY)
 }
 
 // checkUltrasonicContent checks if there's any meaningful content above the cutoff.
@@ -648,3 +770,60 @@ func checkUltrasonicContent(magDb []float64, cutoffFreq, binHz, nyquist, refLeve
 	// Threshold: -50 dB relative to reference indicates some content.
 	return relativeLevel > -50
 }
+
+// processWindows computes the magnitude spectrum and the RMS of every window, on up to workers goroutines
+// (0 = GOMAXPROCS), each with its own FFT plan and scratch. Workers take contiguous runs of windows; every window
+// is computed the same way on any worker.
+func processWindows(samples []float64, positions []int, window []float64, workers int) ([][]float64, []float64) {
+	fftSize := len(window)
+	binCount := fftSize/2 + 1
+
+	windowMagnitudes := make([][]float64, len(positions))
+	windowRMS := make([]float64, len(positions)) // overall RMS per window for quiet detection
+
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	workers = min(workers, len(positions))
+	chunk := (len(positions) + workers - 1) / workers
+
+	var group sync.WaitGroup
+
+	for start := 0; start < len(positions); start += chunk {
+		end := min(start+chunk, len(positions))
+
+		group.Go(func() {
+			fft := fourier.NewFFT(fftSize)
+			fftIn := make([]float64, fftSize)
+
+			var coeffs []complex128
+
+			for windowIdx := start; windowIdx < end; windowIdx++ {
+				pos := positions[windowIdx]
+
+				var rmsSum float64
+
+				for i := range fftSize {
+					fftIn[i] = samples[pos+i] * window[i]
+					rmsSum += samples[pos+i] * samples[pos+i]
+				}
+
+				windowRMS[windowIdx] = math.Sqrt(rmsSum / float64(fftSize))
+
+				coeffs = fft.Coefficients(coeffs, fftIn)
+
+				magnitudes := make([]float64, binCount)
+				for i, c := range coeffs {
+					magnitudes[i] = math.Sqrt(real(c)*real(c) + imag(c)*imag(c))
+				}
+
+				windowMagnitudes[windowIdx] = magnitudes
+			}
+		})
+	}
+
+	group.Wait()
+
+	return windowMagnitudes, windowRMS
+}
@@ -17,7 +17,11 @@ import (
 
 type Options struct {
 	FFTSize    int // default 8192
-	WindowsMax int // max windows to analyze; 0 = all (default 100)
+	WindowsMax int // max windows to analyze; 0 = default 100, WindowsAll = every hop
+
+	// Workers bounds the goroutines computing the window spectra: 0 = GOMAXPROCS, 1 = sequential. Results do not
+	// depend on it. Used only by AnalyzeV2.
+	Workers int
 
 	// NoiseFlatnessCutoff is the spectral flatness threshold below which HF energy
 	// is considered tonal content rather than noise. Flatness is the Wiener entropy
@@ -25,14 +29,71 @@ type Options struct {
 	// Below this cutoff, the noise floor level is capped at -40 dB to avoid false
 	// positives on dark recordings. Default 0.4. Used only by AnalyzeV2.
 	NoiseFlatnessCutoff float64
+
+	// QuietWindowsFraction is the share of the quietest windows the noise floor is measured on, where music masks
+	// it least. Default 0.2. Loud tracks with only brief quiet moments need less, for the quiet windows not to take
+	// in signal; mostly-quiet ambient tracks also isolate the floor better with less.
+	// QuietGateDbFS is the average RMS those windows must exceed for their measurement to be used: below, they
+	// hold the medium's own noise (dither, ADC), and the full-track average is used instead. Default -50.
+	// Used only by AnalyzeV2.
+	QuietWindowsFraction float64
+	QuietGateDbFS        float64
+
+	// ReferenceLowHz and ReferenceHighHz bound the reference band whose average level is the 0 dB point of
+	// the relative measurements: NoiseFloorDb, BandEnergy, and the upsampling, transcode and ultrasonic
+	// content thresholds. Default 1-10 kHz. On material with little midrange energy (sub-bass-heavy
+	// electronic music, solo bass) a wider or lower band keeps those measurements meaningful; a quieter
+	// reference raises NoiseFloorDb and BandEnergy by the same amount. Hum is measured against neighboring
+	// bins and is not affected.
+	ReferenceLowHz  float64
+	ReferenceHighHz float64
+
+	// PitchReferenceHz is the tuning reference (A4) pitch offsets are measured against. Default 440.
+	// Used only by AnalyzeV2.
+	PitchReferenceHz float64
+
+	// LeadInFrames and LeadOutFrames are the lengths of the leading and trailing silence (from silence.Detect).
+	// When set, the noise floor of those regions is measured on its own (LeadInNoiseFloorDb). Used only by
+	// AnalyzeV2.
+	LeadInFrames  int
+	LeadOutFrames int
 }
 
+// Noise floor measurement defaults. See Options.
+const (
+	quietWindowsFraction = 0.2
+	quietGateDbFS        = -50.0
+)
+
+// WindowsAll analyzes every hop of the track (half an FFT), instead of at most WindowsMax windows spread across it:
+// a short defect between sampled windows is then never missed, at the cost of CPU and memory linear in the length.
+const WindowsAll = -1
+
 func DefaultOptions() Options {
 	return Options{
-		FFTSize:             8192,
-		WindowsMax:          100,
-		NoiseFlatnessCutoff: 0.4,
+		FFTSize:              8192,
+		WindowsMax:           100,
+		NoiseFlatnessCutoff:  0.4,
+		QuietWindowsFraction: quietWindowsFraction,
+		QuietGateDbFS:        quietGateDbFS,
+		ReferenceLowHz:       1000,
+		ReferenceHighHz:      10000,
+		PitchReferenceHz:     PitchReferenceHz,
+	}
+}
+
+// referenceBand returns the configured reference band, defaulting to 1-10 kHz and capped below nyquist.
+func (o Options) referenceBand(nyquist float64) (low, high float64) {
+	low, high = o.ReferenceLowHz, o.ReferenceHighHz
+	if low <= 0 {
+		low = 1000
 	}
+
+	if high <= low {
+		high = 10000
+	}
+
+	return low, min(high, nyquist)
 }
 
 var transcodeCutoffs = []struct {
@@ -48,10 +109,16 @@ var transcodeCutoffs = []struct {
 	{20500, "Opus 128"},
 }
 
+// Below CD rates, only the rates old voice, telephony and game audio actually used.
+// 32 kHz is left out: its 16 kHz Nyquist is indistinguishable from an MP3 128 cutoff.
 var upsampleNyquists = []struct {
 	rate    int
 	nyquist float64
 }{
+	{8000, 4000},
+	{11025, 5512.5},
+	{16000, 8000},
+	{22050, 11025},
 	{44100, 22050},
 	{48000, 24000},
 	{88200, 44100},
@@ -59,6 +126,10 @@ var upsampleNyquists = []struct {
 }
 
 func Analyze(reader io.Reader, format types.PCMFormat, opts Options) (*types.SpectralResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if opts.FFTSize == 0 {
 		opts.FFTSize = 8192
 	}
@@ -126,8 +197,9 @@ func Analyze(reader io.Reader, format types.PCMFormat, opts Options) (*types.Spe
 
 	magDb := toDb(avgMagnitude)
 
-	// Reference level: 1-10 kHz average.
-	refLevel := bandAverage(magDb, 1000, 10000, binHz)
+	// Reference level: reference band average (1-10 kHz by default).
+	refLow, refHigh := opts.referenceBand(nyquist)
+	refLevel := bandAverage(magDb, refLow, refHigh, binHz)
 
 	result := &types.SpectralResult{
 		ClaimedRate: format.SampleRate,
@@ -135,9 +207,7 @@ func Analyze(reader io.Reader, format types.PCMFormat, opts Options) (*types.Spe
 	}
 
 	// === Sample rate authenticity ===
-	if format.SampleRate > 44100 {
-		detectUpsampling(result, magDb, binHz, nyquist, refLevel)
-	}
+	detectUpsampling(result, magDb, binHz, nyquist, refLevel)
 
 	// === Lossy transcode detection ===
 	detectTranscode(result, magDb, binHz, nyquist, refLevel)
@@ -179,11 +249,12 @@ func readMonoMixed(reader io.Reader, format types.PCMFormat) ([]float64, error)
 
 	var samples []float64
 
+	reader = shared.NewFrameReader(reader, frameSize)
+
 	for {
 		n, err := reader.Read(readBuf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := readBuf[:completeFrames]
+			data := readBuf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -242,7 +313,7 @@ func readMonoMixed(reader io.Reader, format types.PCMFormat) ([]float64, error)
 }
 
 // windowPositions returns evenly spaced FFT window start positions.
-// If the track has fewer possible windows than maxWindows, all are returned.
+// If the track has fewer possible windows than maxWindows, or maxWindows is WindowsAll, all are returned.
 // Otherwise, maxWindows positions are distributed evenly across the track.
 func windowPositions(totalSamples, fftSize, maxWindows int) []int {
 	available := totalSamples - fftSize
@@ -253,7 +324,7 @@ func windowPositions(totalSamples, fftSize, maxWindows int) []int {
 	hopSize := fftSize / 2
 	totalPossible := available/hopSize + 1
 
-	if totalPossible <= maxWindows {
+	if maxWindows < 0 || totalPossible <= maxWindows {
 		positions := make([]int, 0, totalPossible)
 		for pos := 0; pos+fftSize <= totalSamples; pos += hopSize {
 			positions = append(positions, pos)
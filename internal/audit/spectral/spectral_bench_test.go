@@ -0,0 +1,26 @@
+package spectral_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/audit/spectral"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func BenchmarkAnalyze(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := spectral.Analyze(reader, format, spectral.DefaultOptions())
+
+		return err
+	})
+}
+
+func BenchmarkAnalyzeV2(b *testing.B) {
+	testutil.Bench(b, func(reader io.ReadSeeker, format types.PCMFormat) error {
+		_, err := spectral.AnalyzeV2(reader, format, spectral.DefaultOptions())
+
+		return err
+	})
+}
@@ -0,0 +1,61 @@
+package spectral
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	tiltLowestHz    = 125.0  // lowest octave band center
+	tiltHighestHz   = 8000.0 // highest: above, lossy cutoffs and anti-alias filters bend the slope
+	tiltMinBands    = 4
+	tiltEmptyDb     = 80.0 // bands this far under the reference band hold no music (a high-pass, a low-pass)
+	pinkDbPerOctave = -3.0 // slope of pink noise on a per-bin spectrum: equal energy per octave
+)
+
+// detectSpectralTilt fits the slope of the average spectrum, over the octave bands from 125 Hz to 8 kHz, by least
+// squares of the band levels against their octave. The slope is reported relative to pink noise: 0 is equal
+// energy per octave, positive is brighter, negative is duller. Bands empty of music (80 dB under the reference
+// band), and those past the Nyquist frequency, are left out of the fit; fewer than 4 leave the tilt unmeasured.
+func detectSpectralTilt(result *types.SpectralResult, magDb []float64, binHz, nyquist, refLevel float64) {
+	var octaves, levels []float64
+
+	for center := tiltLowestHz; center <= tiltHighestHz; center *= 2 {
+		if center*math.Sqrt2 >= nyquist {
+			break
+		}
+
+		level := bandAverage(magDb, center/math.Sqrt2, center*math.Sqrt2, binHz)
+		if level < refLevel-tiltEmptyDb {
+			continue
+		}
+
+		octaves = append(octaves, math.Log2(center))
+		levels = append(levels, level)
+	}
+
+	if len(octaves) < tiltMinBands {
+		return
+	}
+
+	var meanOctave, meanLevel float64
+
+	for i := range octaves {
+		meanOctave += octaves[i]
+		meanLevel += levels[i]
+	}
+
+	meanOctave /= float64(len(octaves))
+	meanLevel /= float64(len(octaves))
+
+	var covariance, variance float64
+
+	for i := range octaves {
+		covariance += (octaves[i] - meanOctave) * (levels[i] - meanLevel)
+		variance += (octaves[i] - meanOctave) * (octaves[i] - meanOctave)
+	}
+
+	result.SpectralTiltDbPerOct = covariance/variance - pinkDbPerOctave
+	result.SpectralTiltBands = len(octaves)
+}
@@ -0,0 +1,145 @@
+package spectral
+
+import (
+	"math"
+	"sort"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	constantToneLowHz       = 100.0   // below, bass lines and the mains fundamentals
+	constantToneHighHz      = 20000.0 // up to the top of the audible band, where CRT flyback whine lives
+	constantToneMainsHighHz = 1000.0  // mains harmonics up to this are hum (and its buzz), not tones
+	constantToneNarrowBins  = 3       // a line stands over the bins this far away: the Hann main lobe is narrower
+	constantToneNarrowDb    = 6.0
+	constantToneSideBins    = 8  // bins averaged on each side, past the narrow gap
+	constantToneMinSpikeDb  = 12 // mean level over its sides, across the windows
+	constantTonePresence    = 0.8
+	constantToneMaxCV       = 0.3 // coefficient of variation of the spike across the windows, as for hum
+	constantToneFloorDb     = 90.0
+	constantToneMaxReported = 8
+	constantToneMinWindows  = 20
+)
+
+// detectConstantTones looks for steady tones at arbitrary frequencies: CRT flyback whine (15.7 kHz), a ventilation
+// resonance, a whistle in the chain. It is the hum detection's variance test, run on every bin from 100 Hz to
+// 20 kHz, the mains harmonics excepted: a bin that is a narrow peak (6 dB over the bins 3 away) in 80% of the
+// windows is a candidate, kept when its spike over the neighboring bins averages 12 dB or more and hardly varies
+// across the windows (coefficient of variation under 0.3). A held note varies with the performance and moves
+// with the melody; a tone in the chain does neither. The strongest 8 are reported.
+func detectConstantTones(result *types.SpectralResult, windowMagnitudes [][]float64, binHz, nyquist, refLevel float64) {
+	if len(windowMagnitudes) < constantToneMinWindows {
+		return
+	}
+
+	binCount := len(windowMagnitudes[0])
+	margin := constantToneNarrowBins + constantToneSideBins
+	lowBin := max(int(constantToneLowHz/binHz), margin)
+	highBin := min(int(min(constantToneHighHz, nyquist-500)/binHz), binCount-margin-1)
+
+	if highBin <= lowBin {
+		return
+	}
+
+	windowDb := make([][]float64, len(windowMagnitudes))
+	counts := make([]int, binCount)
+	floor := refLevel - constantToneFloorDb
+
+	// Narrow peaks, window by window.
+	for w, magnitudes := range windowMagnitudes {
+		decibels := toDb(magnitudes)
+		windowDb[w] = decibels
+
+		for i := lowBin; i <= highBin; i++ {
+			level := decibels[i]
+			if level < floor || level <= decibels[i-1] || level < decibels[i+1] {
+				continue
+			}
+
+			narrow := max(decibels[i-constantToneNarrowBins], decibels[i+constantToneNarrowBins])
+			if level-narrow >= constantToneNarrowDb {
+				counts[i]++
+			}
+		}
+	}
+
+	presence := int(math.Ceil(constantTonePresence * float64(len(windowMagnitudes))))
+
+	var tones []types.ConstantTone
+
+	for i := lowBin; i <= highBin; i++ {
+		// One candidate per line: a tone between two bins peaks in either, from one window to the next.
+		if counts[i] <= counts[i-1] || counts[i] < counts[i+1] || counts[i-1]+counts[i]+counts[i+1] < presence {
+			continue
+		}
+
+		if isMainsHarmonic(float64(i)*binHz, binHz) {
+			continue
+		}
+
+		spike, level, coeffVar := toneSpike(windowDb, i)
+		if spike >= constantToneMinSpikeDb && coeffVar < constantToneMaxCV {
+			tones = append(tones, types.ConstantTone{Hz: float64(i) * binHz, Db: level - refLevel})
+		}
+	}
+
+	sort.SliceStable(tones, func(a, b int) bool { return tones[a].Db > tones[b].Db })
+
+	if len(tones) > constantToneMaxReported {
+		tones = tones[:constantToneMaxReported]
+	}
+
+	result.ConstantTones = tones
+}
+
+// toneSpike returns, across the windows, the mean level of a line (the loudest of its bin and the two around it)
+// and its mean spike over the bins on each side, with the spike's coefficient of variation.
+func toneSpike(windowDb [][]float64, bin int) (spike, level, coeffVar float64) {
+	spikes := make([]float64, len(windowDb))
+
+	for w, decibels := range windowDb {
+		peak := max(decibels[bin-1], decibels[bin], decibels[bin+1])
+
+		var sides float64
+
+		for offset := constantToneNarrowBins; offset < constantToneNarrowBins+constantToneSideBins; offset++ {
+			sides += decibels[bin-offset] + decibels[bin+offset]
+		}
+
+		spikes[w] = peak - sides/(2*constantToneSideBins)
+		spike += spikes[w]
+		level += peak
+	}
+
+	spike /= float64(len(windowDb))
+	level /= float64(len(windowDb))
+
+	if spike <= 0 {
+		return spike, level, 1
+	}
+
+	var varianceSum float64
+
+	for _, s := range spikes {
+		varianceSum += (s - spike) * (s - spike)
+	}
+
+	return spike, level, math.Sqrt(varianceSum/float64(len(spikes))) / spike
+}
+
+// isMainsHarmonic tells whether a frequency is within a bin of a 50 or 60 Hz harmonic, up to 1 kHz.
+func isMainsHarmonic(hz, binHz float64) bool {
+	if hz > constantToneMainsHighHz {
+		return false
+	}
+
+	for _, fundamental := range []float64{50, 60} {
+		nearest := math.Round(hz/fundamental) * fundamental
+		if math.Abs(hz-nearest) <= binHz {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,115 @@
+package spectral
+
+import (
+	"bytes"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// TestProcessWindowsWorkers checks that the window spectra do not depend on how the windows are split across
+// workers: one, an uneven split, and more workers than windows.
+func TestProcessWindowsWorkers(t *testing.T) {
+	t.Parallel()
+
+	const fftSize = 1024
+
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	samples := make([]float64, 44100)
+	for i := range samples {
+		samples[i] = 2*rng.Float64() - 1
+	}
+
+	positions := windowPositions(len(samples), fftSize, WindowsAll)
+	window := makeHannWindow(fftSize)
+
+	magnitudes, rms := processWindows(samples, positions, window, 1)
+
+	for _, workers := range []int{3, 8, len(positions) + 1} {
+		gotMagnitudes, gotRMS := processWindows(samples, positions, window, workers)
+
+		if !reflect.DeepEqual(gotMagnitudes, magnitudes) || !reflect.DeepEqual(gotRMS, rms) {
+			t.Errorf("%d workers: expected the windows of a sequential run", workers)
+		}
+	}
+}
+
+// TestWindowPositionsAll checks that WindowsAll visits every hop of the track, half an FFT apart, to its end,
+// where the default spreads 100 windows across it.
+func TestWindowPositionsAll(t *testing.T) {
+	t.Parallel()
+
+	const (
+		fftSize = 8192
+		total   = 60 * 44100
+	)
+
+	positions := windowPositions(total, fftSize, WindowsAll)
+	if want := (total-fftSize)/(fftSize/2) + 1; len(positions) != want {
+		t.Fatalf("expected %d windows, got %d", want, len(positions))
+	}
+
+	for i, pos := range positions {
+		if pos != i*fftSize/2 {
+			t.Fatalf("expected window %d at %d, got %d", i, i*fftSize/2, pos)
+		}
+	}
+
+	if last := positions[len(positions)-1]; total-(last+fftSize) >= fftSize/2 {
+		t.Fatalf("expected the windows to reach the end of the track, the last one ends at %d of %d", last+fftSize,
+			total)
+	}
+
+	if sampled := windowPositions(total, fftSize, DefaultOptions().WindowsMax); len(sampled) != 100 {
+		t.Fatalf("expected 100 windows by default, got %d", len(sampled))
+	}
+}
+
+// TestWindowsAllLocalizedDefect puts a noise burst between two of the default windows of a tone: the default
+// spread does not see it, every window does.
+func TestWindowsAllLocalizedDefect(t *testing.T) {
+	t.Parallel()
+
+	const seconds = 60
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	opts := DefaultOptions()
+
+	positions := windowPositions(seconds*format.SampleRate, opts.FFTSize, opts.WindowsMax)
+	burstAt := (positions[50] + opts.FFTSize + positions[51]) / 2
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	data := testutil.Synthesize(format, seconds, func(frame, _ int) float64 {
+		value := 0.25 * math.Sin(2*math.Pi*1000*float64(frame)/float64(format.SampleRate))
+		if frame >= burstAt-500 && frame < burstAt+500 {
+			value += 0.5 * (2*rng.Float64() - 1)
+		}
+
+		return value
+	})
+
+	sampled, err := AnalyzeV2(bytes.NewReader(data), format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	opts.WindowsMax = WindowsAll
+
+	thorough, err := AnalyzeV2(bytes.NewReader(data), format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	if sampled.SibilantEvents != 0 {
+		t.Fatalf("expected the default windows to miss the burst, got %d sibilant events", sampled.SibilantEvents)
+	}
+
+	if thorough.SibilantEvents == 0 {
+		t.Fatal("expected every window to catch the burst as a sibilant event")
+	}
+}
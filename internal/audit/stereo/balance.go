@@ -0,0 +1,66 @@
+package stereo
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	balanceWindowMs   = 500
+	balanceMinPower   = 1e-5 // -50 dBFS RMS on the louder channel: quieter windows say nothing about balance
+	balanceMaxDb      = 40.0 // per-window imbalance cap: a channel dropping out entirely would be infinite
+	balanceMinWindows = 10
+)
+
+// balanceTracker measures the L/R imbalance of each 500 ms window, to tell a steady offset (a gain error, fixable)
+// from an erratic one (a channel cutting in and out: a cable or connector fault during the transfer).
+type balanceTracker struct {
+	size    int
+	samples int
+	sumLL   float64
+	sumRR   float64
+
+	windows int
+	sum     float64
+	sumSq   float64
+}
+
+func newBalanceTracker(sampleRate int) *balanceTracker {
+	return &balanceTracker{size: max(sampleRate*balanceWindowMs/1000, 1)}
+}
+
+func (t *balanceTracker) add(left, right float64) {
+	t.sumLL += left * left
+	t.sumRR += right * right
+
+	t.samples++
+	if t.samples < t.size {
+		return
+	}
+
+	powerL, powerR := t.sumLL/float64(t.samples), t.sumRR/float64(t.samples)
+	t.samples, t.sumLL, t.sumRR = 0, 0, 0
+
+	if max(powerL, powerR) < balanceMinPower {
+		return
+	}
+
+	// Power ratio in dB, guarded against a silent channel.
+	imbalance := 10 * math.Log10(max(powerL, 1e-12)/max(powerR, 1e-12))
+	imbalance = max(-balanceMaxDb, min(balanceMaxDb, imbalance))
+
+	t.windows++
+	t.sum += imbalance
+	t.sumSq += imbalance * imbalance
+}
+
+// fill sets the standard deviation of the per-window imbalance. It stays 0 with fewer than 10 loud windows.
+func (t *balanceTracker) fill(result *types.StereoResult) {
+	if t.windows < balanceMinWindows {
+		return
+	}
+
+	mean := t.sum / float64(t.windows)
+	result.ImbalanceVarianceDb = math.Sqrt(max(t.sumSq/float64(t.windows)-mean*mean, 0))
+}
@@ -0,0 +1,74 @@
+package stereo
+
+import (
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	cutSafetyFilterStages = 4 // 24 dB/octave skirts: a loud tone leaks about -28 dB into the adjacent band
+
+	cutSafetyMinLevelDb     = -24.0 // bands quieter than this (relative to the full band) are not judged
+	cutSafetyMinCorrelation = 0.5   // below this, bass is too wide for a lathe; below 0, it cancels in mono
+	cutSafetySubsonicDb     = -30.0 // subsonic power at or above this (relative to the full band) wastes groove
+
+	cutSafetySubsonicLowHz  = 5
+	cutSafetySubsonicHighHz = 20
+)
+
+//nolint:gochecknoglobals
+var cutSafetyBands = [][2]float64{{20, 40}, {40, 80}, {80, 150}}
+
+// cutSafetyDetector measures what a cutting engineer checks below 150 Hz: L/R correlation and width per band
+// (out-of-phase bass is vertical groove modulation, which makes the stylus jump), plus subsonic content.
+type cutSafetyDetector struct {
+	bands    []*bandCorrelation
+	subsonic *bandCorrelation
+	total    float64
+}
+
+func newCutSafetyDetector(sampleRate int) *cutSafetyDetector {
+	detector := &cutSafetyDetector{
+		subsonic: newBandCorrelation(
+			cutSafetySubsonicLowHz, cutSafetySubsonicHighHz, sampleRate, cutSafetyFilterStages,
+		),
+	}
+
+	for _, band := range cutSafetyBands {
+		detector.bands = append(detector.bands, newBandCorrelation(band[0], band[1], sampleRate, cutSafetyFilterStages))
+	}
+
+	return detector
+}
+
+func (d *cutSafetyDetector) add(left, right float64) {
+	for _, band := range d.bands {
+		band.add(left, right)
+	}
+
+	d.subsonic.add(left, right)
+	d.total += (left*left + right*right) / 2
+}
+
+// fill stores the per-band measurements and the cutting verdict on the result.
+func (d *cutSafetyDetector) fill(result *types.StereoResult) {
+	result.SubsonicDb = d.subsonic.levelDb(d.total)
+	result.SubsonicExcess = result.SubsonicDb >= cutSafetySubsonicDb
+
+	unsafe := result.SubsonicExcess
+
+	for idx, band := range d.bands {
+		low := types.LowBand{
+			LowHz:       cutSafetyBands[idx][0],
+			HighHz:      cutSafetyBands[idx][1],
+			Correlation: band.value(),
+			SideToMidDb: band.sideToMidDb(),
+			LevelDb:     band.levelDb(d.total),
+		}
+		low.Unsafe = low.LevelDb >= cutSafetyMinLevelDb && low.Correlation < cutSafetyMinCorrelation
+
+		unsafe = unsafe || low.Unsafe
+		result.LowBands = append(result.LowBands, low)
+	}
+
+	result.VinylCutUnsafe = unsafe
+}
@@ -0,0 +1,134 @@
+package stereo
+
+import "math"
+
+const (
+	downmixMidLowHz   = 300
+	downmixMidHighHz  = 3000
+	downmixSideLowHz  = 4000
+	downmixSideHighHz = 12000
+
+	downmixFilterStages = 2 // cascaded band-pass stages: 12 dB/octave skirts keep the bands apart
+
+	downmixMidCorrelation  = 0.8  // center channel buildup: dialog/vocals identical in both channels
+	downmixSideCorrelation = -0.2 // surrounds folded in anti-phase
+)
+
+// bandpass is an RBJ constant-peak band-pass biquad (direct form I).
+type bandpass struct {
+	b0, b2, a1, a2 float64
+	x1, x2, y1, y2 float64
+}
+
+func newBandpass(lowHz, highHz float64, sampleRate int) *bandpass {
+	center := math.Sqrt(lowHz * highHz)
+	quality := center / (highHz - lowHz)
+	omega := 2 * math.Pi * center / float64(sampleRate)
+	alpha := math.Sin(omega) / (2 * quality)
+	norm := 1 + alpha
+
+	return &bandpass{
+		b0: alpha / norm,
+		b2: -alpha / norm,
+		a1: -2 * math.Cos(omega) / norm,
+		a2: (1 - alpha) / norm,
+	}
+}
+
+func (f *bandpass) process(in float64) float64 {
+	out := f.b0*in + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, in
+	f.y2, f.y1 = f.y1, out
+
+	return out
+}
+
+// bandCorrelation accumulates the L/R correlation of one frequency band.
+type bandCorrelation struct {
+	left, right  []*bandpass
+	sumLL, sumRR float64
+	sumLR        float64
+}
+
+func newBandCorrelation(lowHz, highHz float64, sampleRate, stages int) *bandCorrelation {
+	corr := &bandCorrelation{}
+
+	for range stages {
+		corr.left = append(corr.left, newBandpass(lowHz, highHz, sampleRate))
+		corr.right = append(corr.right, newBandpass(lowHz, highHz, sampleRate))
+	}
+
+	return corr
+}
+
+func (c *bandCorrelation) add(left, right float64) {
+	l, r := left, right
+	for stage := range c.left {
+		l = c.left[stage].process(l)
+		r = c.right[stage].process(r)
+	}
+
+	c.sumLL += l * l
+	c.sumRR += r * r
+	c.sumLR += l * r
+}
+
+// levelDb returns the band's mean channel power in dB, relative to total (the full-band mean channel power).
+func (c *bandCorrelation) levelDb(total float64) float64 {
+	if c.sumLL+c.sumRR == 0 || total == 0 {
+		return -120
+	}
+
+	return 10 * math.Log10((c.sumLL+c.sumRR)/2/total)
+}
+
+// sideToMidDb returns the band's side (L-R) power relative to its mid (L+R) power, in dB.
+func (c *bandCorrelation) sideToMidDb() float64 {
+	mid := c.sumLL + c.sumRR + 2*c.sumLR
+	side := c.sumLL + c.sumRR - 2*c.sumLR
+
+	switch {
+	case side <= 0:
+		return -120
+	case mid <= 0:
+		return 120
+	default:
+		return 10 * math.Log10(side/mid)
+	}
+}
+
+// value returns the correlation (band-passed signals are zero-mean); 0 when the band is empty.
+func (c *bandCorrelation) value() float64 {
+	denominator := math.Sqrt(c.sumLL * c.sumRR)
+	if denominator == 0 {
+		return 0
+	}
+
+	return c.sumLR / denominator
+}
+
+// downmixDetector looks for a badly folded multichannel source: a center channel summed equally into both
+// sides makes the midrange (dialog, vocals) nearly mono, while surrounds matrixed in anti-phase drag the
+// upper band correlation negative.
+type downmixDetector struct {
+	mid  *bandCorrelation
+	side *bandCorrelation
+}
+
+func newDownmixDetector(sampleRate int) *downmixDetector {
+	return &downmixDetector{
+		mid: newBandCorrelation(downmixMidLowHz, downmixMidHighHz, sampleRate, downmixFilterStages),
+		side: newBandCorrelation(
+			downmixSideLowHz, min(downmixSideHighHz, float64(sampleRate)*0.45), sampleRate, downmixFilterStages,
+		),
+	}
+}
+
+func (d *downmixDetector) add(left, right float64) {
+	d.mid.add(left, right)
+	d.side.add(left, right)
+}
+
+func (d *downmixDetector) suspected(midCorrelation, sideCorrelation float64) bool {
+	return midCorrelation > downmixMidCorrelation && sideCorrelation < downmixSideCorrelation
+}
@@ -13,6 +13,10 @@ import (
 )
 
 func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if format.Channels != 2 {
 		return &types.StereoResult{
 			Correlation:    0,
@@ -39,6 +43,12 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 
 	var maxVal float64
 
+	downmix := newDownmixDetector(format.SampleRate)
+	cutSafety := newCutSafetyDetector(format.SampleRate)
+	width := newWidthDetector(format.SampleRate)
+	balance := newBalanceTracker(format.SampleRate)
+	transitions := newTransitionTracker(format.SampleRate)
+
 	switch format.BitDepth {
 	case types.Depth16:
 		maxVal = shared.MaxValue16
@@ -49,11 +59,12 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 	default:
 	}
 
+	reader = shared.NewFrameReader(reader, frameSize)
+
 	for {
 		n, err := reader.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -77,6 +88,11 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 					mono := (left + right) / 2
 					sumMonoSq += mono * mono
 					sumStereoSq += (left*left + right*right) / 2
+					downmix.add(left, right)
+					cutSafety.add(left, right)
+					width.add(left, right)
+					balance.add(left, right)
+					transitions.add(left, right)
 					frames++
 				}
 			case types.Depth24:
@@ -106,6 +122,11 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 					mono := (left + right) / 2
 					sumMonoSq += mono * mono
 					sumStereoSq += (left*left + right*right) / 2
+					downmix.add(left, right)
+					cutSafety.add(left, right)
+					width.add(left, right)
+					balance.add(left, right)
+					transitions.add(left, right)
 					frames++
 				}
 			case types.Depth32:
@@ -129,6 +150,11 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 					mono := (left + right) / 2
 					sumMonoSq += mono * mono
 					sumStereoSq += (left*left + right*right) / 2
+					downmix.add(left, right)
+					cutSafety.add(left, right)
+					width.add(left, right)
+					balance.add(left, right)
+					transitions.add(left, right)
 					frames++
 				}
 			default:
@@ -202,15 +228,28 @@ func Analyze(reader io.Reader, format types.PCMFormat) (*types.StereoResult, err
 		rightDb = -120.0
 	}
 
-	return &types.StereoResult{
-		Correlation:    correlation,
-		DifferenceDb:   diffDb,
-		MonoSumDb:      monoDb,
-		StereoRmsDb:    stereoDb,
-		CancellationDb: stereoDb - monoDb,
-		LeftRmsDb:      leftDb,
-		RightRmsDb:     rightDb,
-		ImbalanceDb:    leftDb - rightDb,
-		Frames:         frames,
-	}, nil
+	midCorrelation := downmix.mid.value()
+	sideCorrelation := downmix.side.value()
+
+	result := &types.StereoResult{
+		Correlation:      correlation,
+		DifferenceDb:     diffDb,
+		MonoSumDb:        monoDb,
+		StereoRmsDb:      stereoDb,
+		CancellationDb:   stereoDb - monoDb,
+		LeftRmsDb:        leftDb,
+		RightRmsDb:       rightDb,
+		ImbalanceDb:      leftDb - rightDb,
+		MidCorrelation:   midCorrelation,
+		SideCorrelation:  sideCorrelation,
+		SuspectedDownmix: downmix.suspected(midCorrelation, sideCorrelation),
+		Frames:           frames,
+	}
+
+	cutSafety.fill(result)
+	width.fill(result)
+	balance.fill(result)
+	transitions.fill(result)
+
+	return result, nil
 }
@@ -0,0 +1,116 @@
+package stereo
+
+import (
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	transitionWindowMs   = 1000
+	transitionMinPower   = 1e-5  // -50 dBFS RMS: quieter windows say nothing about width
+	transitionMonoDb     = -30.0 // side/mid power under which a window is mono
+	transitionStereoDb   = -20.0 // side/mid power over which a window is stereo; in between, it keeps its state
+	transitionMinWindows = 5     // consecutive windows a new state must hold to count as a transition
+)
+
+type widthState int
+
+const (
+	widthUnknown widthState = iota
+	widthMono
+	widthStereo
+)
+
+// transitionTracker follows the stereo width window by window, to find where a track switches between mono and
+// stereo: a mono intro spliced onto a stereo take, or a compilation assembled from different sources. The single
+// whole-track correlation averages such sections away. A change only counts once the new state holds for five
+// seconds, so that a centered solo or a mono sound effect does not.
+type transitionTracker struct {
+	size    int
+	samples int
+	sumMid  float64
+	sumSide float64
+	frame   uint64
+
+	state     widthState
+	candidate widthState
+	since     uint64 // first frame of the candidate state
+	run       int
+
+	transitions []types.WidthTransition
+	sampleRate  int
+}
+
+func newTransitionTracker(sampleRate int) *transitionTracker {
+	return &transitionTracker{size: max(sampleRate*transitionWindowMs/1000, 1), sampleRate: sampleRate}
+}
+
+func (t *transitionTracker) add(left, right float64) {
+	mid := (left + right) / 2
+	side := (left - right) / 2
+	t.sumMid += mid * mid
+	t.sumSide += side * side
+
+	t.samples++
+	if t.samples < t.size {
+		return
+	}
+
+	start := t.frame
+	powerMid, powerSide := t.sumMid/float64(t.samples), t.sumSide/float64(t.samples)
+	t.frame += uint64(t.samples) //nolint:gosec // non-negative
+	t.samples, t.sumMid, t.sumSide = 0, 0, 0
+
+	if powerMid+powerSide < transitionMinPower {
+		return
+	}
+
+	// Side power relative to mid, guarded against digital silence on either.
+	ratioDb := 10 * math.Log10(max(powerSide, 1e-12)/max(powerMid, 1e-12))
+
+	var state widthState
+
+	switch {
+	case ratioDb < transitionMonoDb:
+		state = widthMono
+	case ratioDb > transitionStereoDb:
+		state = widthStereo
+	default:
+		return
+	}
+
+	t.observe(state, start)
+}
+
+func (t *transitionTracker) observe(state widthState, start uint64) {
+	if state == t.state {
+		t.candidate, t.run = widthUnknown, 0
+
+		return
+	}
+
+	if state != t.candidate {
+		t.candidate, t.since, t.run = state, start, 0
+	}
+
+	t.run++
+	if t.run < transitionMinWindows {
+		return
+	}
+
+	// The first sustained state is where the track starts from, not a transition.
+	if t.state != widthUnknown {
+		t.transitions = append(t.transitions, types.WidthTransition{
+			Frame:   t.since,
+			TimeSec: float64(t.since) / float64(t.sampleRate),
+			ToMono:  state == widthMono,
+		})
+	}
+
+	t.state, t.candidate, t.run = state, widthUnknown, 0
+}
+
+func (t *transitionTracker) fill(result *types.StereoResult) {
+	result.WidthTransitions = t.transitions
+}
@@ -0,0 +1,249 @@
+package stereo
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	widthBlockSize = 8192 // ~5.4 Hz bins at 44.1 kHz: fine enough to resolve combs from delays up to ~50 ms
+
+	widthLowHz  = 200   // below this, rooms and bass management dominate the stereo field
+	widthHighHz = 16000 // capped at 45% of the sample rate
+
+	// Comb periods searched, in Hz. A pseudo-stereo comb comes from a delay of a few to tens of milliseconds
+	// (period = 1 / delay). Shorter delays are what a spaced microphone pair produces.
+	widthMinPeriodHz = 20
+	widthMaxPeriodHz = 350
+
+	widthMinSwing       = 0.2 // standard deviation the per-bin curve must show across frequency
+	widthMinPeriodicity = 0.5 // normalized autocorrelation at the comb period
+	widthMinBlocks      = 8   // blocks needed before judging
+
+	// Decorrelation (all-pass or Haas wideners): uncorrelated channels with the same spectrum, octave by octave.
+	decorrelatedMaxCorrelation = 0.05 // a spaced pair keeps more: the bass stays coherent between microphones
+	decorrelatedMaxMismatchDb  = 0.5  // spread of the per-octave L/R level differences
+)
+
+// widthDetector looks for algorithmic widening of a mono source. Delaying one channel, or adding and subtracting
+// a delayed copy (Lauridsen), comb-filters the stereo field: across frequency, the L/R correlation (or the L/R
+// balance) swings periodically, every 1/delay Hz. Genuine stereo, with many sources at many positions, shows no
+// such regular pattern.
+type widthDetector struct {
+	fft    *fourier.FFT
+	window []float64
+	left   []float64
+	right  []float64
+	pos    int
+	blocks int
+	binHz  float64
+
+	lowBin, highBin int
+	crossRe         []float64 // per-bin sum of Re(L conj(R))
+	powerL, powerR  []float64
+}
+
+func newWidthDetector(sampleRate int) *widthDetector {
+	window := make([]float64, widthBlockSize)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(widthBlockSize-1)))
+	}
+
+	binHz := float64(sampleRate) / widthBlockSize
+	bins := widthBlockSize/2 + 1
+
+	return &widthDetector{
+		fft:     fourier.NewFFT(widthBlockSize),
+		window:  window,
+		left:    make([]float64, widthBlockSize),
+		right:   make([]float64, widthBlockSize),
+		binHz:   binHz,
+		lowBin:  int(widthLowHz / binHz),
+		highBin: min(int(widthHighHz/binHz), int(float64(sampleRate)*0.45/binHz), bins-1),
+		crossRe: make([]float64, bins),
+		powerL:  make([]float64, bins),
+		powerR:  make([]float64, bins),
+	}
+}
+
+func (d *widthDetector) add(left, right float64) {
+	d.left[d.pos] = left * d.window[d.pos]
+	d.right[d.pos] = right * d.window[d.pos]
+
+	d.pos++
+	if d.pos < widthBlockSize {
+		return
+	}
+
+	d.pos = 0
+	d.blocks++
+
+	coeffsL := d.fft.Coefficients(nil, d.left)
+	coeffsR := d.fft.Coefficients(nil, d.right)
+
+	for bin := d.lowBin; bin <= d.highBin; bin++ {
+		d.crossRe[bin] += real(coeffsL[bin] * cmplx.Conj(coeffsR[bin]))
+		d.powerL[bin] += real(coeffsL[bin] * cmplx.Conj(coeffsL[bin]))
+		d.powerR[bin] += real(coeffsR[bin] * cmplx.Conj(coeffsR[bin]))
+	}
+}
+
+// fill stores the comb verdict on the result: the stronger of the correlation and balance patterns wins.
+func (d *widthDetector) fill(result *types.StereoResult) {
+	if d.blocks < widthMinBlocks || d.highBin-d.lowBin < 4*int(widthMaxPeriodHz/d.binHz) {
+		return
+	}
+
+	correlation := make([]float64, 0, d.highBin-d.lowBin+1)
+	balance := make([]float64, 0, d.highBin-d.lowBin+1)
+
+	for bin := d.lowBin; bin <= d.highBin; bin++ {
+		power := math.Sqrt(d.powerL[bin] * d.powerR[bin])
+		if power == 0 {
+			correlation = append(correlation, 0)
+			balance = append(balance, 0)
+
+			continue
+		}
+
+		correlation = append(correlation, d.crossRe[bin]/power)
+		balance = append(balance, (d.powerL[bin]-d.powerR[bin])/(d.powerL[bin]+d.powerR[bin]))
+	}
+
+	minLag := max(int(widthMinPeriodHz/d.binHz), 2)
+	maxLag := int(widthMaxPeriodHz / d.binHz)
+
+	bestLag, bestStrength := 0.0, 0.0
+
+	for _, curve := range [][]float64{correlation, balance} {
+		lag, strength := combPeriod(curve, minLag, maxLag)
+		if strength > bestStrength {
+			bestLag, bestStrength = lag, strength
+		}
+	}
+
+	result.CombStrength = bestStrength
+	if bestStrength >= widthMinPeriodicity {
+		result.CombPeriodHz = bestLag * d.binHz
+		result.SyntheticWidthLikely = true
+	}
+
+	d.fillDecorrelation(result)
+}
+
+// fillDecorrelation compares the two channels' spectra, octave by octave from 200 Hz. A decorrelator (all-pass
+// filters, a Haas delay) turns a mono source into channels with a flat zero correlation, but leaves their
+// magnitude spectra alike: the level differences between the channels are the same in every octave (a plain
+// gain offset at most). Genuine wide stereo puts different sources on each side, and their spectra part.
+// Needs the broadband correlation on the result.
+func (d *widthDetector) fillDecorrelation(result *types.StereoResult) {
+	var differences []float64
+
+	for low := d.lowBin; low < d.highBin; low *= 2 {
+		var powerL, powerR float64
+
+		for bin := low; bin < min(2*low, d.highBin+1); bin++ {
+			powerL += d.powerL[bin]
+			powerR += d.powerR[bin]
+		}
+
+		if powerL == 0 || powerR == 0 {
+			return
+		}
+
+		differences = append(differences, 10*math.Log10(powerL/powerR))
+	}
+
+	var mean float64
+	for _, difference := range differences {
+		mean += difference
+	}
+
+	mean /= float64(len(differences))
+
+	var variance float64
+	for _, difference := range differences {
+		variance += (difference - mean) * (difference - mean)
+	}
+
+	result.SpectralMismatchDb = math.Sqrt(variance / float64(len(differences)))
+	result.DecorrelatedLikely = math.Abs(result.Correlation) < decorrelatedMaxCorrelation &&
+		result.SpectralMismatchDb < decorrelatedMaxMismatchDb
+}
+
+// combPeriod finds the dominant period of a curve, in (fractional) bins, from its normalized autocorrelation: the
+// highest peak past the first negative lobe (which skips the main lobe around lag 0). Curves that barely move
+// across frequency have no period.
+func combPeriod(curve []float64, minLag, maxLag int) (float64, float64) {
+	var mean float64
+	for _, value := range curve {
+		mean += value
+	}
+
+	mean /= float64(len(curve))
+
+	centered := make([]float64, len(curve))
+
+	var variance float64
+
+	for i, value := range curve {
+		centered[i] = value - mean
+		variance += centered[i] * centered[i]
+	}
+
+	if variance == 0 || math.Sqrt(variance/float64(len(curve))) < widthMinSwing {
+		return 0, 0
+	}
+
+	autocorrelation := func(lag int) float64 {
+		var sum float64
+		for i := lag; i < len(centered); i++ {
+			sum += centered[i] * centered[i-lag]
+		}
+
+		// Unbiased: compensate for the shrinking overlap.
+		return sum / variance * float64(len(centered)) / float64(len(centered)-lag)
+	}
+
+	// Multiples of the period correlate as well as the period itself: take the first peak close to the best.
+	values := make([]float64, maxLag+1)
+	pastMainLobe := false
+	best := 0.0
+
+	for lag := 1; lag <= maxLag; lag++ {
+		values[lag] = autocorrelation(lag)
+		if values[lag] < 0 {
+			pastMainLobe = true
+		}
+
+		if !pastMainLobe || lag < minLag {
+			values[lag] = 0
+		}
+
+		best = max(best, values[lag])
+	}
+
+	bestLag, bestStrength := 0.0, 0.0
+
+	for lag := minLag; lag <= maxLag && best > 0; lag++ {
+		if values[lag] >= 0.9*best && (lag == maxLag || values[lag] >= values[lag+1]) {
+			bestLag, bestStrength = float64(lag), values[lag]
+
+			// Parabolic interpolation: the period rarely falls on a whole bin.
+			if lag > minLag && lag < maxLag {
+				curvature := values[lag-1] - 2*values[lag] + values[lag+1]
+				if curvature < 0 {
+					bestLag += 0.5 * (values[lag-1] - values[lag+1]) / curvature
+				}
+			}
+
+			break
+		}
+	}
+
+	return bestLag, bestStrength
+}
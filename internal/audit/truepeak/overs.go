@@ -0,0 +1,68 @@
+package truepeak
+
+import "math"
+
+const (
+	oversBlockMs = 50.0
+	oversRatioDb = 0.3   // a block "overs" when its true peak exceeds its sample peak by more than this
+	oversFloorDb = -60.0 // blocks with a sample peak below this are silence, and not counted
+)
+
+// oversTracker measures how pervasive inter-sample overshoots are, independently of the absolute level: the
+// fraction of blocks whose true peak exceeds their own sample peak by more than 0.3 dB. Well-behaved material
+// reaches its true peaks on (or next to) samples; material pushed into a limiter or clipper has flattened tops
+// whose hard corners ring between samples, in quiet sections as much as in loud ones.
+type oversTracker struct {
+	blockFrames uint64
+	ratio       float64
+	floor       float64
+
+	frames     uint64
+	samplePeak float64
+	truePeak   float64
+
+	blocks     uint64
+	overBlocks uint64
+}
+
+func newOversTracker(sampleRate int) *oversTracker {
+	return &oversTracker{
+		blockFrames: max(uint64(float64(sampleRate)*oversBlockMs/1000), 1),
+		ratio:       math.Pow(10, oversRatioDb/20),
+		floor:       math.Pow(10, oversFloorDb/20),
+	}
+}
+
+func (t *oversTracker) sample(absSample float64) {
+	t.samplePeak = max(t.samplePeak, absSample)
+}
+
+func (t *oversTracker) interpolated(absInterp float64) {
+	t.truePeak = max(t.truePeak, absInterp)
+}
+
+func (t *oversTracker) endFrame() {
+	t.frames++
+	if t.frames < t.blockFrames {
+		return
+	}
+
+	if t.samplePeak >= t.floor {
+		t.blocks++
+
+		if t.truePeak > t.samplePeak*t.ratio {
+			t.overBlocks++
+		}
+	}
+
+	t.frames, t.samplePeak, t.truePeak = 0, 0, 0
+}
+
+// fraction returns the share of non-silent blocks with overs (0-1).
+func (t *oversTracker) fraction() float64 {
+	if t.blocks == 0 {
+		return 0
+	}
+
+	return float64(t.overBlocks) / float64(t.blocks)
+}
@@ -0,0 +1,137 @@
+package truepeak
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+const (
+	regionBlockSize   = 128  // ~345 Hz bins at 44.1 kHz, ~3 ms around each ISP
+	regionLowestHz    = 1000 // inter-sample overshoot comes from the upper spectrum: lower bins are ignored
+	regionMaxAnalyses = 4000 // spectra taken, at most; later ISPs are counted but not analyzed
+	regionMinAnalyses = 20   // spectra needed to locate a region
+
+	// The filter delay: an ISP computed from the history sits this many samples before the newest one.
+	regionFilterDelay = tapsPerPhase / 2
+)
+
+// regionTracker locates the frequency region where ISPs concentrate. Around each ISP (one per block, per channel),
+// it takes a short windowed spectrum of the surrounding samples, centered on the ISP, normalizes it to unit energy
+// above 1 kHz, and accumulates it. A narrow EQ boost that drives the overs shows as one region holding much of the
+// accumulated energy; a hot master overshoots across the upper spectrum.
+type regionTracker struct {
+	fft    *fourier.FFT
+	window []float64
+	binHz  float64
+	lowBin int
+
+	rings   [][]float64 // last regionBlockSize samples, per channel
+	pos     []int
+	pending []int // samples to wait before analyzing (0 = nothing pending), per channel
+
+	block    []float64
+	coeffs   []complex128
+	spectrum []float64 // accumulated normalized power, per bin
+	analyses int
+}
+
+func newRegionTracker(sampleRate, numChannels int) *regionTracker {
+	window := make([]float64, regionBlockSize)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(regionBlockSize-1)))
+	}
+
+	rings := make([][]float64, numChannels)
+	for channel := range rings {
+		rings[channel] = make([]float64, regionBlockSize)
+	}
+
+	binHz := float64(sampleRate) / regionBlockSize
+
+	return &regionTracker{
+		fft:      fourier.NewFFT(regionBlockSize),
+		window:   window,
+		binHz:    binHz,
+		lowBin:   int(math.Ceil(regionLowestHz / binHz)),
+		rings:    rings,
+		pos:      make([]int, numChannels),
+		pending:  make([]int, numChannels),
+		block:    make([]float64, regionBlockSize),
+		spectrum: make([]float64, regionBlockSize/2+1),
+	}
+}
+
+func (t *regionTracker) sample(channel int, sample float64) {
+	t.rings[channel][t.pos[channel]] = sample
+	t.pos[channel] = (t.pos[channel] + 1) % regionBlockSize
+
+	if t.pending[channel] == 0 {
+		return
+	}
+
+	t.pending[channel]--
+	if t.pending[channel] == 0 {
+		t.analyze(channel)
+	}
+}
+
+// isp marks an ISP on the channel: its spectrum is taken once the ISP sits in the middle of the ring.
+// ISPs while one is pending belong to the same block.
+func (t *regionTracker) isp(channel int) {
+	if t.pending[channel] > 0 || t.analyses >= regionMaxAnalyses {
+		return
+	}
+
+	t.pending[channel] = max(regionBlockSize/2-regionFilterDelay, 1)
+}
+
+func (t *regionTracker) analyze(channel int) {
+	ring := t.rings[channel]
+	for i := range t.block {
+		t.block[i] = ring[(t.pos[channel]+i)%regionBlockSize] * t.window[i]
+	}
+
+	t.coeffs = t.fft.Coefficients(t.coeffs, t.block)
+
+	var total float64
+
+	for bin := t.lowBin; bin < len(t.coeffs); bin++ {
+		total += real(t.coeffs[bin] * cmplx.Conj(t.coeffs[bin]))
+	}
+
+	if total == 0 {
+		return
+	}
+
+	for bin := t.lowBin; bin < len(t.coeffs); bin++ {
+		t.spectrum[bin] += real(t.coeffs[bin]*cmplx.Conj(t.coeffs[bin])) / total
+	}
+
+	t.analyses++
+}
+
+// region returns the center of the strongest region (Hz), and the share of the accumulated energy it holds
+// (the peak bin and its neighbors, 0-1). Both are 0 with too few ISPs.
+func (t *regionTracker) region() (float64, float64) {
+	if t.analyses < regionMinAnalyses {
+		return 0, 0
+	}
+
+	peak := t.lowBin
+	for bin := t.lowBin; bin < len(t.spectrum); bin++ {
+		if t.spectrum[bin] > t.spectrum[peak] {
+			peak = bin
+		}
+	}
+
+	var share, moment float64
+
+	for bin := max(peak-1, t.lowBin); bin <= min(peak+1, len(t.spectrum)-1); bin++ {
+		share += t.spectrum[bin]
+		moment += t.spectrum[bin] * float64(bin)
+	}
+
+	return moment / share * t.binHz, share / float64(t.analyses)
+}
@@ -83,6 +83,10 @@ func bessel0(x float64) float64 {
 }
 
 func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
 	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
 	frameSize := bytesPerSample * numChannels
@@ -126,11 +130,15 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 	currentWindowISPs := uint64(0)  // ISPs in current window
 	currentWindowStart := uint64(0) // frame where current window started
 
+	overs := newOversTracker(format.SampleRate)
+	region := newRegionTracker(format.SampleRate, numChannels)
+
+	r = shared.NewFrameReader(r, frameSize)
+
 	for {
 		n, err := r.Read(buf)
 		if n > 0 {
-			completeFrames := (n / frameSize) * frameSize
-			data := buf[:completeFrames]
+			data := buf[:n]
 
 			switch format.BitDepth {
 			case types.Depth16:
@@ -142,6 +150,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 
 						// Track sample peak
 						absSample := math.Abs(sample)
+						overs.sample(absSample)
 						if absSample > samplePeak {
 							samplePeak = absSample
 						}
@@ -149,6 +158,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 						// Shift history and add new sample
 						copy(history[channel][0:], history[channel][1:])
 						history[channel][tapsPerPhase-1] = sample
+						region.sample(channel, sample)
 
 						// Compute interpolated samples at each phase
 						for phase := range oversample {
@@ -158,6 +168,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 							}
 
 							absInterp := math.Abs(interp)
+							overs.interpolated(absInterp)
 							if absInterp > truePeak {
 								truePeak = absInterp
 							}
@@ -165,6 +176,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 							// Count ISPs (peaks exceeding 0 dBFS)
 							if absInterp > 1.0 {
 								ispCount++
+								region.isp(channel)
 								currentWindowISPs++
 
 								overshoot := 20 * math.Log10(absInterp)
@@ -189,6 +201,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 					}
 
 					totalFrames++
+					overs.endFrame()
 
 					// Check if we've completed a 1-second window
 					if totalFrames-currentWindowStart >= uint64(samplesPerSecond) {
@@ -210,12 +223,14 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 						sample := float64(raw) / maxVal
 
 						absSample := math.Abs(sample)
+						overs.sample(absSample)
 						if absSample > samplePeak {
 							samplePeak = absSample
 						}
 
 						copy(history[channel][0:], history[channel][1:])
 						history[channel][tapsPerPhase-1] = sample
+						region.sample(channel, sample)
 
 						for phase := range oversample {
 							var interp float64
@@ -224,12 +239,14 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 							}
 
 							absInterp := math.Abs(interp)
+							overs.interpolated(absInterp)
 							if absInterp > truePeak {
 								truePeak = absInterp
 							}
 
 							if absInterp > 1.0 {
 								ispCount++
+								region.isp(channel)
 								currentWindowISPs++
 
 								overshoot := 20 * math.Log10(absInterp)
@@ -253,6 +270,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 					}
 
 					totalFrames++
+					overs.endFrame()
 
 					if totalFrames-currentWindowStart >= uint64(samplesPerSecond) {
 						windowISPCounts = append(windowISPCounts, currentWindowISPs)
@@ -268,12 +286,14 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 						) / maxVal
 
 						absSample := math.Abs(sample)
+						overs.sample(absSample)
 						if absSample > samplePeak {
 							samplePeak = absSample
 						}
 
 						copy(history[channel][0:], history[channel][1:])
 						history[channel][tapsPerPhase-1] = sample
+						region.sample(channel, sample)
 
 						for phase := range oversample {
 							var interp float64
@@ -282,12 +302,14 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 							}
 
 							absInterp := math.Abs(interp)
+							overs.interpolated(absInterp)
 							if absInterp > truePeak {
 								truePeak = absInterp
 							}
 
 							if absInterp > 1.0 {
 								ispCount++
+								region.isp(channel)
 								currentWindowISPs++
 
 								overshoot := 20 * math.Log10(absInterp)
@@ -311,6 +333,7 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 					}
 
 					totalFrames++
+					overs.endFrame()
 
 					if totalFrames-currentWindowStart >= uint64(samplesPerSecond) {
 						windowISPCounts = append(windowISPCounts, currentWindowISPs)
@@ -362,6 +385,8 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 
 	var ispDensityAvg float64
 
+	regionHz, regionShare := region.region()
+
 	if totalFrames > 0 {
 		durationSec := float64(totalFrames) / float64(samplesPerSecond)
 		if durationSec > 0 {
@@ -382,5 +407,10 @@ func Detect(r io.Reader, format types.PCMFormat) (*types.TruePeakResult, error)
 		ISPsAbove1dB:    ispsAbove1dB,
 		ISPsAbove2dB:    ispsAbove2dB,
 		WorstDensitySec: worstDensitySec,
+
+		ISPRegionHz:    regionHz,
+		ISPRegionShare: regionShare,
+
+		OversPervasiveFraction: overs.fraction(),
 	}, nil
 }
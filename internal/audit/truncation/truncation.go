@@ -2,6 +2,7 @@ package truncation
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -17,6 +18,10 @@ const (
 )
 
 func Detect(r io.ReadSeeker, format types.PCMFormat, windowMs uint) (*types.TruncationDetection, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
 	if windowMs == 0 {
 		windowMs = defaultWindowMs
 	}
@@ -24,6 +29,24 @@ func Detect(r io.ReadSeeker, format types.PCMFormat, windowMs uint) (*types.Trun
 	bytesPerSample := int(
 		format.BitDepth / 8,
 	)
+	channels := int(format.Channels) //nolint:gosec // validated small value
+
+	// The head: first, the lead-in.
+	headBytes := format.SampleRate * int(windowMs) / 1000 * channels * bytesPerSample
+
+	head := make([]byte, headBytes)
+
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+	}
+
+	firstFrameDb, leadRmsDb, headPeakDb := measureHead(
+		normalize(head[:n], format.BitDepth),
+		channels,
+		format.SampleRate*leadInMs/1000,
+	)
+
 	tailSamples := format.SampleRate * int(
 		windowMs,
 	) / 1000 * int(
@@ -32,7 +55,7 @@ func Detect(r io.ReadSeeker, format types.PCMFormat, windowMs uint) (*types.Trun
 	tailBytes := int64(tailSamples * bytesPerSample)
 
 	// Seek to end minus tail size
-	_, err := r.Seek(-tailBytes, io.SeekEnd)
+	_, err = r.Seek(-tailBytes, io.SeekEnd)
 	if err != nil {
 		// File shorter than tail window, seek to start
 		_, err = r.Seek(0, io.SeekStart)
@@ -46,41 +69,89 @@ func Detect(r io.ReadSeeker, format types.PCMFormat, windowMs uint) (*types.Trun
 		return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
 	}
 
-	var maxVal float64
-
-	switch format.BitDepth {
-	case types.Depth16:
-		maxVal = shared.MaxValue16
-	case types.Depth24:
-		maxVal = shared.MaxValue24
-	case types.Depth32:
-		maxVal = shared.MaxValue32
-	default:
-	}
-
 	var (
 		sumSquares float64
 		peak       float64
 		count      uint64
 	)
 
-	completeSamples := (len(buf) / bytesPerSample) * bytesPerSample
-	data := buf[:completeSamples]
+	for _, normalized := range normalize(buf, format.BitDepth) {
+		sumSquares += normalized * normalized
+		if abs := math.Abs(normalized); abs > peak {
+			peak = abs
+		}
+
+		count++
+	}
+
+	if count == 0 {
+		return &types.TruncationDetection{
+			IsTruncated:   false,
+			FinalRmsDb:    -120.0,
+			FinalPeakDb:   -120.0,
+			SamplesInTail: 0,
+			FirstFrameDb:  firstFrameDb,
+			LeadInRmsDb:   leadRmsDb,
+			HeadPeakDb:    headPeakDb,
+		}, nil
+	}
+
+	rms := math.Sqrt(sumSquares / float64(count))
+
+	return &types.TruncationDetection{
+		FinalRmsDb:    toDb(rms),
+		FinalPeakDb:   toDb(peak),
+		SamplesInTail: count,
+		FirstFrameDb:  firstFrameDb,
+		LeadInRmsDb:   leadRmsDb,
+		HeadPeakDb:    headPeakDb,
+	}, nil
+}
+
+// leadInMs is the span of the lead-in, at the very start of the head window: a natural onset is still rising
+// there, from silence or the room, far under the level the first notes reach.
+const leadInMs = 5
+
+// measureHead returns the level of the first frame (its loudest channel), the RMS of the lead-in (leadFrames
+// frames) and the peak of the whole head, in dB.
+func measureHead(samples []float64, channels, leadFrames int) (firstFrameDb, leadRmsDb, headPeakDb float64) {
+	var first, peak, sumSquares float64
 
-	switch format.BitDepth {
+	leadSamples := min(max(leadFrames, 1)*channels, len(samples))
+
+	for i, sample := range samples {
+		abs := math.Abs(sample)
+		if i < channels {
+			first = max(first, abs)
+		}
+
+		if i < leadSamples {
+			sumSquares += sample * sample
+		}
+
+		peak = max(peak, abs)
+	}
+
+	if leadSamples == 0 {
+		return -120, -120, -120
+	}
+
+	return toDb(first), toDb(math.Sqrt(sumSquares / float64(leadSamples))), toDb(peak)
+}
+
+// normalize decodes little-endian PCM into samples in [-1, 1]. A trailing partial sample is ignored.
+func normalize(buf []byte, depth types.BitDepth) []float64 {
+	bytesPerSample := int(depth / 8)
+	data := buf[:(len(buf)/bytesPerSample)*bytesPerSample]
+	samples := make([]float64, 0, len(data)/bytesPerSample)
+
+	switch depth {
 	case types.Depth16:
 		for i := 0; i < len(data); i += 2 {
 			sample := int16(
 				binary.LittleEndian.Uint16(data[i:]),
 			)
-			normalized := float64(sample) / maxVal
-
-			sumSquares += normalized * normalized
-			if abs := math.Abs(normalized); abs > peak {
-				peak = abs
-			}
-
-			count++
+			samples = append(samples, float64(sample)/shared.MaxValue16)
 		}
 	case types.Depth24:
 		for i := 0; i < len(data); i += 3 {
@@ -89,56 +160,26 @@ func Detect(r io.ReadSeeker, format types.PCMFormat, windowMs uint) (*types.Trun
 				sample |= ^0xFFFFFF
 			}
 
-			normalized := float64(sample) / maxVal
-
-			sumSquares += normalized * normalized
-			if abs := math.Abs(normalized); abs > peak {
-				peak = abs
-			}
-
-			count++
+			samples = append(samples, float64(sample)/shared.MaxValue24)
 		}
 	case types.Depth32:
 		for i := 0; i < len(data); i += 4 {
 			sample := int32(
 				binary.LittleEndian.Uint32(data[i:]),
 			)
-			normalized := float64(sample) / maxVal
-
-			sumSquares += normalized * normalized
-			if abs := math.Abs(normalized); abs > peak {
-				peak = abs
-			}
-
-			count++
+			samples = append(samples, float64(sample)/shared.MaxValue32)
 		}
 	default:
 	}
 
-	if count == 0 {
-		return &types.TruncationDetection{
-			IsTruncated:   false,
-			FinalRmsDb:    -120.0,
-			FinalPeakDb:   -120.0,
-			SamplesInTail: 0,
-		}, nil
-	}
-
-	rms := math.Sqrt(sumSquares / float64(count))
-	rmsDb := 20 * math.Log10(rms)
-	peakDb := 20 * math.Log10(peak)
-
-	if math.IsInf(rmsDb, -1) {
-		rmsDb = -120.0
-	}
+	return samples
+}
 
-	if math.IsInf(peakDb, -1) {
-		peakDb = -120.0
+// toDb converts a linear level to dB, with digital silence at -120.
+func toDb(level float64) float64 {
+	if level <= 0 {
+		return -120.0
 	}
 
-	return &types.TruncationDetection{
-		FinalRmsDb:    rmsDb,
-		FinalPeakDb:   peakDb,
-		SamplesInTail: count,
-	}, nil
+	return 20 * math.Log10(level)
 }
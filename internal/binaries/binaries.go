@@ -0,0 +1,34 @@
+// Package binaries resolves the ffprobe and ffmpeg executables the haustorium and hau-report commands run.
+package binaries
+
+import "github.com/urfave/cli/v3"
+
+// Binaries are the ffprobe and ffmpeg executables to run. An empty path is looked up in the system PATH.
+type Binaries struct {
+	FFprobe string
+	FFmpeg  string
+}
+
+// FromCommand returns the binaries the Flags of cmd name.
+func FromCommand(cmd *cli.Command) Binaries {
+	return Binaries{
+		FFprobe: cmd.String("ffprobe"),
+		FFmpeg:  cmd.String("ffmpeg"),
+	}
+}
+
+// Flags are the flags FromCommand reads.
+func Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "ffprobe",
+			Usage:   "Path to the ffprobe binary (default: ffprobe from PATH)",
+			Sources: cli.EnvVars("HAUSTORIUM_FFPROBE"),
+		},
+		&cli.StringFlag{
+			Name:    "ffmpeg",
+			Usage:   "Path to the ffmpeg binary (default: ffmpeg from PATH)",
+			Sources: cli.EnvVars("HAUSTORIUM_FFMPEG"),
+		},
+	}
+}
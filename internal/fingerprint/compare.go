@@ -0,0 +1,92 @@
+package fingerprint
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/bits"
+)
+
+const (
+	maxShiftFrames = 10  // alignments tried either way (~3.7 s): leading silence trimmed or padded differently
+	minOverlap     = 0.8 // of the shorter fingerprint, for an alignment to count
+
+	// MatchBitErrorRate is the bit error rate under which two fingerprints are the same recording.
+	// Re-encodes stay well under it; unrelated material sits around 0.5.
+	MatchBitErrorRate = 0.2
+)
+
+var errInvalidSubFingerprints = errors.New("acoustic fingerprint is not a whole number of 32-bit words")
+
+// SubFingerprints are the per-frame sub-fingerprints of a track. They marshal to base64 (little-endian words),
+// a quarter the size of a JSON number array.
+type SubFingerprints []uint32
+
+// MarshalJSON encodes the sub-fingerprints as a base64 string.
+func (s SubFingerprints) MarshalJSON() ([]byte, error) {
+	data := make([]byte, 4*len(s))
+	for i, word := range s {
+		binary.LittleEndian.PutUint32(data[4*i:], word)
+	}
+
+	return json.Marshal(base64.StdEncoding.EncodeToString(data))
+}
+
+// UnmarshalJSON decodes sub-fingerprints from a base64 string.
+func (s *SubFingerprints) UnmarshalJSON(raw []byte) error {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	if len(data)%4 != 0 {
+		return errInvalidSubFingerprints
+	}
+
+	words := make(SubFingerprints, len(data)/4)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(data[4*i:])
+	}
+
+	*s = words
+
+	return nil
+}
+
+// BitErrorRate compares two acoustic fingerprints: the share of differing bits at the best alignment (0-1).
+// It returns 1 when they cannot be aligned over most of the shorter one.
+func BitErrorRate(first, second SubFingerprints) float64 {
+	shorter := min(len(first), len(second))
+	if shorter == 0 {
+		return 1
+	}
+
+	best := 1.0
+
+	for shift := -maxShiftFrames; shift <= maxShiftFrames; shift++ {
+		var differing, compared int
+
+		for i := max(0, -shift); i < len(first) && i+shift < len(second); i++ {
+			if i+shift < 0 {
+				continue
+			}
+
+			differing += bits.OnesCount32(first[i] ^ second[i+shift])
+			compared++
+		}
+
+		if float64(compared) < minOverlap*float64(shorter) {
+			continue
+		}
+
+		best = min(best, float64(differing)/float64(32*compared))
+	}
+
+	return best
+}
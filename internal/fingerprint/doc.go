@@ -0,0 +1,3 @@
+// Package fingerprint identifies tracks by content, to find duplicates across a collection: an exact hash of the
+// decoded samples, and an acoustic fingerprint that survives re-encoding.
+package fingerprint
@@ -0,0 +1,193 @@
+//nolint:tagliatelle
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+
+	"github.com/farcloser/primordium/fault"
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium/internal/audit/shared"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+const (
+	frameSec = 0.371  // analysis frame, at every sample rate; sub-fingerprints do not overlap
+	bandLow  = 300.0  // lower edge of the bands (Hz)
+	bandHigh = 2000.0 // upper edge: the range survives every codec and bitrate
+	bands    = 33     // 32 energy differences: one 32-bit sub-fingerprint per frame
+)
+
+// Fingerprint identifies a track by its content.
+type Fingerprint struct {
+	// SHA-256 of the PCM samples: equal for the same decoded audio, whatever the container or lossless codec.
+	PCMHash string `json:"pcm_sha256"`
+	// One 32-bit sub-fingerprint per frame: the signs of the band energy differences, across bands and time
+	// (Haitsma-Kalker). Re-encodes of the same recording differ in a few bits; other recordings in about half.
+	Acoustic    SubFingerprints `json:"acoustic"`
+	FrameSec    float64         `json:"frame_sec"`
+	DurationSec float64         `json:"duration_sec"`
+}
+
+// Compute fingerprints the PCM stream. Channels are mixed down to mono for the acoustic fingerprint.
+func Compute(reader io.Reader, format types.PCMFormat) (*Fingerprint, error) {
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	bytesPerSample := int(format.BitDepth / 8) //nolint:gosec // bit depth and channel count are small constants
+	numChannels := int(format.Channels)        //nolint:gosec // bit depth and channel count are small constants
+	frameSize := bytesPerSample * numChannels
+
+	buf := make([]byte, frameSize*4096)
+
+	var maxVal float64
+
+	switch format.BitDepth {
+	case types.Depth16:
+		maxVal = shared.MaxValue16
+	case types.Depth24:
+		maxVal = shared.MaxValue24
+	case types.Depth32:
+		maxVal = shared.MaxValue32
+	default:
+	}
+
+	hash := sha256.New()
+	acoustic := newAcoustic(format.SampleRate)
+
+	var frames uint64
+
+	reader = shared.NewFrameReader(reader, frameSize)
+
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			data := buf[:n]
+			hash.Write(data)
+
+			for i := 0; i < len(data); i += frameSize {
+				var mono float64
+
+				for ch := range numChannels {
+					offset := i + ch*bytesPerSample
+
+					switch format.BitDepth {
+					case types.Depth16:
+						mono += float64(int16(binary.LittleEndian.Uint16(data[offset:])))
+					case types.Depth24:
+						raw := int32(data[offset]) | int32(data[offset+1])<<8 | int32(data[offset+2])<<16
+						if raw&0x800000 != 0 {
+							raw |= ^0xFFFFFF
+						}
+
+						mono += float64(raw)
+					case types.Depth32:
+						mono += float64(int32(binary.LittleEndian.Uint32(data[offset:])))
+					default:
+					}
+				}
+
+				acoustic.add(mono / maxVal / float64(numChannels))
+
+				frames++
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+	}
+
+	return &Fingerprint{
+		PCMHash:     hex.EncodeToString(hash.Sum(nil)),
+		Acoustic:    acoustic.subFingerprints,
+		FrameSec:    float64(acoustic.frame) / float64(format.SampleRate),
+		DurationSec: float64(frames) / float64(format.SampleRate),
+	}, nil
+}
+
+type acousticState struct {
+	fft      *fourier.FFT
+	window   []float64
+	block    []float64 // the frame, zero-padded to the FFT size
+	coeffs   []complex128
+	frame    int // samples per frame
+	pos      int
+	edges    []int // FFT bin where each band starts, plus the end of the last one
+	previous []float64
+
+	subFingerprints SubFingerprints
+}
+
+func newAcoustic(sampleRate int) *acousticState {
+	// The frame is frameSec long whatever the sample rate, so that the sub-fingerprints of a track at 44.1 and 48 kHz
+	// cover the same stretches of it. It is zero-padded to a power of two for the FFT; the band edges are in Hz.
+	frame := int(math.Round(frameSec * float64(sampleRate)))
+	size := 1 << int(math.Ceil(math.Log2(float64(frame))))
+
+	window := make([]float64, frame)
+	for i := range window {
+		window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(frame-1)))
+	}
+
+	binHz := float64(sampleRate) / float64(size)
+	edges := make([]int, bands+1)
+
+	for band := range edges {
+		hz := bandLow * math.Pow(bandHigh/bandLow, float64(band)/bands)
+		edges[band] = int(math.Round(hz / binHz))
+	}
+
+	return &acousticState{
+		fft:    fourier.NewFFT(size),
+		window: window,
+		block:  make([]float64, size),
+		frame:  frame,
+		edges:  edges,
+	}
+}
+
+func (a *acousticState) add(sample float64) {
+	a.block[a.pos] = sample * a.window[a.pos]
+
+	a.pos++
+	if a.pos < a.frame {
+		return
+	}
+
+	a.pos = 0
+	a.coeffs = a.fft.Coefficients(a.coeffs, a.block)
+
+	energies := make([]float64, bands)
+	for band := range energies {
+		for bin := a.edges[band]; bin < max(a.edges[band+1], a.edges[band]+1); bin++ {
+			energies[band] += real(a.coeffs[bin] * cmplx.Conj(a.coeffs[bin]))
+		}
+	}
+
+	if a.previous != nil {
+		var bits uint32
+
+		for band := range bands - 1 {
+			diff := (energies[band] - energies[band+1]) - (a.previous[band] - a.previous[band+1])
+			if diff > 0 {
+				bits |= 1 << band
+			}
+		}
+
+		a.subFingerprints = append(a.subFingerprints, bits)
+	}
+
+	a.previous = energies
+}
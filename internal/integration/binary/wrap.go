@@ -10,3 +10,15 @@ func Available(binName string) (string, bool) {
 
 	return path, err == nil
 }
+
+// Resolve returns binPath when it is set and executable, and otherwise looks binName up in the system PATH.
+// An explicit binPath that is not executable is not found: there is no fallback to PATH.
+func Resolve(binName, binPath string) (string, bool) {
+	if binPath == "" {
+		return Available(binName)
+	}
+
+	path, err := exec.LookPath(binPath)
+
+	return path, err == nil
+}
@@ -2,6 +2,7 @@ package ffmpeg
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -17,8 +18,10 @@ import (
 )
 
 // ExtractStream extracts a specific audio stream from a container.
+// binPath is the ffmpeg binary to run; empty looks it up in the system PATH.
 func ExtractStream(
 	ctx context.Context,
+	binPath string,
 	input io.Reader,
 	output io.Writer,
 	streamIndex int,
@@ -26,9 +29,9 @@ func ExtractStream(
 ) error {
 	slog.Debug("ffmpeg.ExtractStream", "stream index", streamIndex, "stage", "start")
 
-	ffmpegPath, found := binary.Available(name)
+	ffmpegPath, found := binary.Resolve(name, binPath)
 	if !found {
-		return fmt.Errorf("%w: %s", fault.ErrMissingRequirements, name)
+		return fmt.Errorf("%w: %s", fault.ErrMissingRequirements, cmp.Or(binPath, name))
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -3,12 +3,16 @@ package ffprobe
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/farcloser/primordium/fault"
 
@@ -53,7 +57,7 @@ type BaseStream struct {
 	Duration         string              `json:"duration,omitempty"`            // 310.666667
 	StartTime        string              `json:"start_time,omitempty"`          // 0.00000123
 	BitRate          string              `json:"bit_rate,omitempty"`            // 956821
-	Tags             map[string][]string `json:"-"`                             // Metadata tags (from dhowden/tag library, not ffprobe)
+	Tags             map[string][]string `json:"-"`                             // Metadata tags, stream's then container's, lowercase keys
 	BitsPerRawSample string              `json:"bits_per_raw_sample,omitempty"` // see above table - this is shitshow territory
 }
 
@@ -154,14 +158,27 @@ type Format struct {
 	Size           string `json:"size,omitempty"`     // File size in bytes as string, e.g. "37189284"
 }
 
+// Load reads previously captured ffprobe output (-print_format json -show_format -show_streams) from a file,
+// in place of running Probe.
+func Load(path string) (*Result, error) {
+	slog.Debug("ffprobe.Load", "path", path)
+
+	data, err := os.ReadFile(path) //nolint:gosec // path is intentionally user-provided
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+	}
+
+	return parse(data)
+}
+
 // Probe runs ffprobe on the given file path and returns parsed metadata.
-// It requires ffprobe to be available in the system PATH.
-func Probe(ctx context.Context, filePath string) (*Result, error) {
+// binPath is the ffprobe binary to run; empty requires ffprobe to be available in the system PATH.
+func Probe(ctx context.Context, binPath, filePath string) (*Result, error) {
 	slog.Debug("ffprobe.Probe", "file path", filePath)
 
-	ffprobePath, found := binary.Available(name)
+	ffprobePath, found := binary.Resolve(name, binPath)
 	if !found {
-		return nil, fmt.Errorf("%w: %s", fault.ErrMissingRequirements, name)
+		return nil, fmt.Errorf("%w: %s", fault.ErrMissingRequirements, cmp.Or(binPath, name))
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -189,10 +206,74 @@ func Probe(ctx context.Context, filePath string) (*Result, error) {
 		return nil, fmt.Errorf("%w: %s: %w", fault.ErrCommandFailure, stderr.String(), err)
 	}
 
+	return parse(output)
+}
+
+// BitDepthTagKeys are the tags, lowercase, that some encoders and DAW exports use to record the original bit depth.
+var BitDepthTagKeys = []string{"bits_per_sample", "bitspersample", "bit_depth", "bitdepth", "original_bit_depth"}
+
+// TaggedBitDepth returns the bit depth recorded in the stream's tags (BitDepthTagKeys, in order), read from the
+// leading digits of the value ("24", "24-bit"). ok is false when no such tag holds a number.
+func (stream *BaseStream) TaggedBitDepth() (bits int, ok bool) {
+	for _, key := range BitDepthTagKeys {
+		for _, value := range stream.Tags[key] {
+			digits := strings.TrimSpace(value)
+			if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+				digits = digits[:end]
+			}
+
+			if bits, err := strconv.Atoi(digits); err == nil && bits > 0 {
+				return bits, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// parse decodes ffprobe output. The tags, which the structs leave out of their JSON (they are user metadata, and
+// redacted reports must not carry them), are collected into each stream's Tags: its own, then the container's,
+// where ffprobe puts the Vorbis comments of a FLAC file.
+func parse(data []byte) (*Result, error) {
 	var result Result
-	if err = json.Unmarshal(output, &result); err != nil {
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("%w: %w", fault.ErrInvalidJSON, err)
 	}
 
+	var tagged struct {
+		Streams []struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"streams"`
+		Format struct {
+			Tags map[string]string `json:"tags"`
+		} `json:"format"`
+	}
+
+	if err := json.Unmarshal(data, &tagged); err != nil {
+		return nil, fmt.Errorf("%w: %w", fault.ErrInvalidJSON, err)
+	}
+
+	for i := range result.Streams {
+		var own map[string]string
+		if i < len(tagged.Streams) {
+			own = tagged.Streams[i].Tags
+		}
+
+		if len(own) == 0 && len(tagged.Format.Tags) == 0 {
+			continue
+		}
+
+		tags := map[string][]string{}
+
+		for _, source := range []map[string]string{own, tagged.Format.Tags} {
+			for key, value := range source {
+				key = strings.ToLower(key)
+				tags[key] = append(tags[key], value)
+			}
+		}
+
+		result.Streams[i].Tags = tags
+	}
+
 	return &result, nil
 }
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"github.com/farcloser/haustorium"
+)
+
+// OptionsToMap converts the options an analysis ran with into the "config" map: source, genre, checks, the
+// severity bands (by check name) and the analyzer thresholds.
+func OptionsToMap(opts haustorium.Options) map[string]any {
+	checks := []any{}
+
+	for bit := range 32 {
+		check := haustorium.Check(1 << bit)
+		if opts.Checks&check != 0 && check.String() != "unknown" {
+			checks = append(checks, check.String())
+		}
+	}
+
+	bands := map[string]any{
+		"clipping":           bandsToMap(opts.Clipping),
+		"truncation":         bandsToMap(opts.Truncation),
+		"dc-offset":          bandsToMap(opts.DCOffset),
+		"channel-imbalance":  bandsToMap(opts.ChannelImbalance),
+		"phase-issues":       bandsToMap(opts.PhaseIssues),
+		"silence-padding":    bandsToMap(opts.SilencePadding),
+		"hum":                bandsToMap(opts.Hum),
+		"noise-floor":        bandsToMap(opts.NoiseFloor),
+		"inter-sample-peaks": bandsToMap(opts.ISP),
+		"dynamic-range":      bandsToMap(opts.DynamicRange),
+		"dropouts":           bandsToMap(opts.Dropouts),
+		"repeats":            bandsToMap(opts.Repeats),
+		"pitch-offset":       bandsToMap(opts.PitchOffset),
+		"bass-rolloff":       bandsToMap(opts.BassRolloff),
+		"spectral-tilt":      bandsToMap(opts.SpectralTilt),
+		"loudness":           bandsToMap(opts.Loudness),
+	}
+
+	meta := map[string]any{
+		"source": opts.Source.String(),
+		"checks": checks,
+		"bands":  bands,
+		"thresholds": map[string]any{
+			"transcode_sharpness_db":  opts.TranscodeSharpnessDb,
+			"upsample_sharpness_db":   opts.UpsampleSharpnessDb,
+			"dropout_delta_threshold": opts.DropoutDeltaThreshold,
+			"dropout_dc_window_ms":    opts.DropoutDCWindowMs,
+			"loudness_momentary_ms":   opts.LoudnessMomentaryMs,
+			"loudness_short_term_ms":  opts.LoudnessShortTermMs,
+			"loudness_hop_ms":         opts.LoudnessHopMs,
+			"loudness_dr_block_ms":    opts.LoudnessDRBlockMs,
+			"pitch_reference_hz":      opts.PitchReferenceHz,
+			"noise_quiet_fraction":    opts.NoiseQuietFraction,
+			"noise_quiet_gate_dbfs":   opts.NoiseQuietGateDbFS,
+			"min_confidence":          opts.MinConfidence,
+			"true_peak_ceiling_db":    opts.TruePeakCeilingDb,
+			"short_term_allowance_lu": opts.ShortTermAllowanceLU,
+		},
+		"edge_noise_floor":   opts.EdgeNoiseFloor,
+		"spectral_thorough":  opts.SpectralThorough,
+		"bit_depth_sections": opts.BitDepthSections,
+	}
+
+	if opts.Genre != haustorium.GenreUnspecified {
+		meta["genre"] = opts.Genre.String()
+	}
+
+	if opts.Profile != haustorium.ProfileNone {
+		meta["profile"] = opts.Profile.String()
+	}
+
+	if opts.TrimEdgesSec > 0 {
+		meta["trim_edges_sec"] = opts.TrimEdgesSec
+	}
+
+	if opts.SpectralReferenceLowHz > 0 || opts.SpectralReferenceHighHz > 0 {
+		meta["spectral_reference_hz"] = []any{opts.SpectralReferenceLowHz, opts.SpectralReferenceHighHz}
+	}
+
+	if opts.LoudnessTargetLUFS != 0 {
+		meta["loudness_target_lufs"] = opts.LoudnessTargetLUFS
+	}
+
+	if opts.SourceCodec != "" {
+		meta["source_codec"] = opts.SourceCodec
+	}
+
+	if opts.StartOffsetFrames > 0 {
+		meta["start_offset_frames"] = opts.StartOffsetFrames
+	}
+
+	// The verdicts may have been adjusted by the caller's own scoring.
+	if opts.PostProcess != nil {
+		meta["post_process"] = true
+	}
+
+	return meta
+}
+
+func bandsToMap(bands haustorium.Bands) map[string]any {
+	return map[string]any{
+		"mild":     bands.Mild,
+		"moderate": bands.Moderate,
+		"severe":   bands.Severe,
+	}
+}
@@ -0,0 +1,137 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+var (
+	errInvalidRaw   = errors.New("must be none, detected, or all")
+	errUnknownBlock = errors.New("unknown raw block")
+)
+
+// Raw selects which raw analyzer blocks are kept in serialized output. Issues and the summary are always kept.
+type Raw int
+
+const (
+	RawAll      Raw = iota // every raw block (default)
+	RawDetected            // only blocks backing at least one detected issue
+	RawNone                // no raw blocks
+)
+
+// ParseRaw parses a raw selection name. Empty means RawAll.
+func ParseRaw(name string) (Raw, error) {
+	switch name {
+	case "", "all":
+		return RawAll, nil
+	case "detected":
+		return RawDetected, nil
+	case "none":
+		return RawNone, nil
+	default:
+		return RawAll, fmt.Errorf("raw %q: %w", name, errInvalidRaw)
+	}
+}
+
+// rawChecks maps each raw block key of ResultToMap to the checks it backs.
+//
+//nolint:gochecknoglobals
+var rawChecks = map[string][]string{
+	"clipping":   {"clipping"},
+	"truncation": {"truncation", "trimmed-lead-in"},
+	"bit_depth":  {"fake-bit-depth"},
+	"dither":     {"undithered"},
+	"spectral": {
+		"fake-sample-rate", "lossy-transcode", "hum", "noise-floor", "de-essing", "bass-rolloff", "pitch-offset",
+		"spectral-tilt",
+	},
+	"dc_offset": {"dc-offset"},
+	"stereo": {
+		"fake-stereo", "phase-issues", "inverted-phase", "channel-imbalance", "vinyl-cut-safety", "width-changes",
+	},
+	"silence":   {"silence-padding", "trimmed-lead-in"},
+	"true_peak": {"inter-sample-peaks"},
+	"loudness":  {"loudness", "dynamic-range"},
+	"dropouts":  {"dropouts"},
+	"repeats":   {"repeats"},
+}
+
+// PruneRaw removes raw blocks from a ResultToMap map, in place, according to raw.
+// It only relies on the map itself, so it also applies to results read back from a report.
+func PruneRaw(meta map[string]any, raw Raw) {
+	if raw == RawAll {
+		return
+	}
+
+	detected := map[string]bool{}
+
+	if raw == RawDetected {
+		issues, _ := meta["issues"].([]any)
+		for _, entry := range issues {
+			issue, _ := entry.(map[string]any)
+			if fired, _ := issue["detected"].(bool); fired {
+				check, _ := issue["check"].(string)
+				detected[check] = true
+			}
+		}
+	}
+
+	for key, checks := range rawChecks {
+		keep := false
+
+		for _, check := range checks {
+			keep = keep || detected[check]
+		}
+
+		if !keep {
+			delete(meta, key)
+		}
+	}
+}
+
+// RawBlocks returns the raw block keys of ResultToMap, sorted.
+func RawBlocks() []string {
+	return slices.Sorted(maps.Keys(rawChecks))
+}
+
+// ParseInclude parses a comma-separated list of raw block keys (loudness,true_peak). Empty means every block, and
+// returns nil.
+func ParseInclude(list string) (map[string]bool, error) {
+	var include map[string]bool
+
+	for name := range strings.SplitSeq(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if _, ok := rawChecks[name]; !ok {
+			return nil, fmt.Errorf("%w %q (valid: %s)", errUnknownBlock, name, strings.Join(RawBlocks(), ", "))
+		}
+
+		if include == nil {
+			include = map[string]bool{}
+		}
+
+		include[name] = true
+	}
+
+	return include, nil
+}
+
+// IncludeRaw removes the raw blocks not in include from a ResultToMap map, in place. A nil include keeps them all.
+// It composes with PruneRaw: a block is kept when both keep it.
+func IncludeRaw(meta map[string]any, include map[string]bool) {
+	if include == nil {
+		return
+	}
+
+	for key := range rawChecks {
+		if !include[key] {
+			delete(meta, key)
+		}
+	}
+}
@@ -11,26 +11,46 @@ import (
 // ResultToMap converts an analysis result into the canonical map structure
 // used for JSON and JSONL serialization.
 func ResultToMap(result *haustorium.Result) map[string]any {
+	summary := map[string]any{
+		"issue_count":    result.IssueCount,
+		"worst_severity": result.WorstSeverity.String(),
+		"severity_codes": result.SeverityCodes(),
+	}
+	if result.ByteSwapSuspected {
+		summary["byte_swap_suspected"] = true
+	}
+
+	if len(result.Warnings) > 0 {
+		summary["warnings"] = result.Warnings
+	}
+
 	meta := map[string]any{
-		"summary": map[string]any{
-			"issue_count":    result.IssueCount,
-			"worst_severity": result.WorstSeverity.String(),
-		},
+		"summary": summary,
 	}
 
 	// Issues.
 	issues := make([]any, 0, len(result.Issues))
 	for _, issue := range result.Issues {
-		issues = append(issues, map[string]any{
+		entry := map[string]any{
 			"check":      issue.Check.String(),
 			"detected":   issue.Detected,
 			"severity":   issue.Severity.String(),
 			"summary":    issue.Summary,
 			"confidence": issue.Confidence,
-		})
+		}
+		if issue.AffectedChannels != "" {
+			entry["affected_channels"] = issue.AffectedChannels
+		}
+
+		if issue.NotApplicable {
+			entry["not_applicable"] = true
+		}
+
+		issues = append(issues, entry)
 	}
 
 	meta["issues"] = issues
+	meta["config"] = OptionsToMap(result.EffectiveOptions)
 
 	// Raw analyzer results.
 	if r := result.Clipping; r != nil {
@@ -42,6 +62,9 @@ func ResultToMap(result *haustorium.Result) map[string]any {
 			"final_rms_db":    r.FinalRmsDb,
 			"final_peak_db":   r.FinalPeakDb,
 			"samples_in_tail": r.SamplesInTail,
+			"first_frame_db":  r.FirstFrameDb,
+			"lead_in_rms_db":  r.LeadInRmsDb,
+			"head_peak_db":    r.HeadPeakDb,
 		}
 	}
 
@@ -52,6 +75,31 @@ func ResultToMap(result *haustorium.Result) map[string]any {
 			"is_padded": r.IsPadded,
 			"samples":   r.Samples,
 		}
+
+		if r.Windows > 0 {
+			bitDepth, _ := meta["bit_depth"].(map[string]any)
+			bitDepth["consistent_across_track"] = r.ConsistentAcrossTrack
+			bitDepth["windows"] = r.Windows
+			bitDepth["padded_windows"] = r.PaddedWindows
+
+			if !r.ConsistentAcrossTrack {
+				bitDepth["padded_effective"] = int(r.PaddedEffective) //nolint:gosec // small constants
+				bitDepth["first_padded_sec"] = r.FirstPaddedSec
+			}
+		}
+	}
+
+	if r := result.Dither; r != nil {
+		meta["dither"] = map[string]any{
+			"undithered_likely":           r.UnditheredLikely,
+			"hold_ratio":                  r.HoldRatio,
+			"quiet_windows":               r.QuietWindows,
+			"quiet_sec":                   r.QuietSec,
+			"fadeout_quantization_likely": r.FadeoutQuantizationLikely,
+			"fadeout_hold_ratio":          r.FadeoutHoldRatio,
+			"fadeout_sec":                 r.FadeoutSec,
+			"frames":                      r.Frames,
+		}
 	}
 
 	if r := result.Spectral; r != nil {
@@ -69,15 +117,29 @@ func ResultToMap(result *haustorium.Result) map[string]any {
 
 	if reader := result.Stereo; reader != nil {
 		meta["stereo"] = map[string]any{
-			"correlation":     reader.Correlation,
-			"difference_db":   reader.DifferenceDb,
-			"mono_sum_db":     reader.MonoSumDb,
-			"stereo_rms_db":   reader.StereoRmsDb,
-			"cancellation_db": reader.CancellationDb,
-			"left_rms_db":     reader.LeftRmsDb,
-			"right_rms_db":    reader.RightRmsDb,
-			"imbalance_db":    reader.ImbalanceDb,
-			"frames":          reader.Frames,
+			"correlation":           reader.Correlation,
+			"difference_db":         reader.DifferenceDb,
+			"mono_sum_db":           reader.MonoSumDb,
+			"stereo_rms_db":         reader.StereoRmsDb,
+			"cancellation_db":       reader.CancellationDb,
+			"left_rms_db":           reader.LeftRmsDb,
+			"right_rms_db":          reader.RightRmsDb,
+			"imbalance_db":          reader.ImbalanceDb,
+			"imbalance_variance_db": reader.ImbalanceVarianceDb,
+			"mid_correlation":       reader.MidCorrelation,
+			"side_correlation":      reader.SideCorrelation,
+			"suspected_downmix":     reader.SuspectedDownmix,
+			"low_bands":             lowBands(reader.LowBands),
+			"subsonic_db":           reader.SubsonicDb,
+			"subsonic_excess":       reader.SubsonicExcess,
+			"vinyl_cut_unsafe":      reader.VinylCutUnsafe,
+			"comb_period_hz":        reader.CombPeriodHz,
+			"comb_strength":         reader.CombStrength,
+			"synthetic_width":       reader.SyntheticWidthLikely,
+			"spectral_mismatch_db":  reader.SpectralMismatchDb,
+			"decorrelated":          reader.DecorrelatedLikely,
+			"width_transitions":     widthTransitions(reader.WidthTransitions),
+			"frames":                reader.Frames,
 		}
 	}
 
@@ -98,27 +160,54 @@ func ResultToMap(result *haustorium.Result) map[string]any {
 			"isps_above_2db":     reader.ISPsAbove2dB,
 			"worst_density_sec":  reader.WorstDensitySec,
 			"frames":             reader.Frames,
+
+			"isp_region_hz":            reader.ISPRegionHz,
+			"isp_region_share":         reader.ISPRegionShare,
+			"overs_pervasive_fraction": reader.OversPervasiveFraction,
 		}
 	}
 
 	if reader := result.Loudness; reader != nil {
-		meta["loudness"] = map[string]any{
-			"integrated_lufs": reader.IntegratedLUFS,
-			"short_term_max":  reader.ShortTermMax,
-			"momentary_max":   reader.MomentaryMax,
-			"loudness_range":  reader.LoudnessRange,
-			"dr_score":        reader.DRScore,
-			"dr_value":        reader.DRValue,
-			"peak_db":         reader.PeakDb,
-			"rms_db":          reader.RmsDb,
-			"frames":          reader.Frames,
+		loudness := map[string]any{
+			"integrated_lufs":       reader.IntegratedLUFS,
+			"gated_coverage":        reader.GatedCoverage,
+			"short_term_max":        reader.ShortTermMax,
+			"momentary_max":         reader.MomentaryMax,
+			"loudness_range":        reader.LoudnessRange,
+			"dr_score":              reader.DRScore,
+			"dr_value":              reader.DRValue,
+			"peak_db":               reader.PeakDb,
+			"rms_db":                reader.RmsDb,
+			"transient_flattening":  reader.TransientFlatteningIndex,
+			"transients":            reader.Transients,
+			"transients_clipped":    reader.TransientsClippedFraction,
+			"limiter_ceiling_db":    reader.LimiterCeilingDb,
+			"quietest_passage_dbfs": reader.QuietestPassageDbFS,
+			"quietest_passage_sec":  reader.QuietestPassageSec,
+			"frames":                reader.Frames,
+		}
+
+		if reader.MonoMeasured {
+			loudness["mono_loudness_penalty_lu"] = reader.MonoLoudnessPenaltyLU
 		}
+
+		if reader.LFEMeasured {
+			loudness["lfe_level_db"] = reader.LFELevelDb
+			loudness["lfe_high_band_share"] = reader.LFEHighBandShare
+			loudness["lfe_full_range"] = reader.LFEFullRange
+		}
+
+		meta["loudness"] = loudness
 	}
 
 	if r := result.Dropout; r != nil {
 		meta["dropouts"] = DropoutToMap(r)
 	}
 
+	if r := result.Repeat; r != nil {
+		meta["repeats"] = RepeatToMap(r)
+	}
+
 	return meta
 }
 
@@ -131,30 +220,80 @@ func ClippingToMap(result *types.ClippingDetection) map[string]any {
 			"events":          ch.Events,
 			"clipped_samples": ch.ClippedSamples,
 			"longest_run":     ch.LongestRun,
+			"positive_events": ch.PositiveEvents,
+			"negative_events": ch.NegativeEvents,
 		})
 	}
 
-	return map[string]any{
-		"events":          result.Events,
-		"clipped_samples": result.ClippedSamples,
-		"longest_run":     result.LongestRun,
-		"samples":         result.Samples,
-		"channels":        channels,
+	meta := map[string]any{
+		"events":           result.Events,
+		"clipped_samples":  result.ClippedSamples,
+		"longest_run":      result.LongestRun,
+		"long_run_samples": result.LongRunSamples,
+		"samples":          result.Samples,
+		"channels":         channels,
+	}
+
+	if result.Recoverability != types.RecoverabilityNone {
+		meta["recoverability"] = result.Recoverability.String()
+	}
+
+	if result.Band != types.BandNone {
+		meta["band"] = result.Band.String()
+		meta["band_share"] = result.BandShare
+	}
+
+	if result.PlateauEvents > 0 {
+		meta["plateau_events"] = result.PlateauEvents
+		meta["plateau_samples"] = result.PlateauSamples
+		meta["plateau_level_db"] = result.PlateauLevelDb
+		meta["plateau_level_events"] = result.PlateauLevelEvents
 	}
+
+	return meta
 }
 
 // SpectralToMap converts spectral analysis results to a map.
 func SpectralToMap(result *types.SpectralResult) map[string]any {
 	meta := map[string]any{
-		"claimed_rate":      result.ClaimedRate,
-		"is_upsampled":      result.IsUpsampled,
-		"is_transcode":      result.IsTranscode,
-		"has_50hz_hum":      result.Has50HzHum,
-		"has_60hz_hum":      result.Has60HzHum,
-		"hum_level_db":      result.HumLevelDb,
-		"noise_floor_db":    result.NoiseFloorDb,
-		"spectral_centroid": result.SpectralCentroid,
-		"frames":            result.Frames,
+		"claimed_rate":       result.ClaimedRate,
+		"is_upsampled":       result.IsUpsampled,
+		"is_transcode":       result.IsTranscode,
+		"has_50hz_hum":       result.Has50HzHum,
+		"has_60hz_hum":       result.Has60HzHum,
+		"hum_level_db":       result.HumLevelDb,
+		"noise_floor_db":     result.NoiseFloorDb,
+		"spectral_centroid":  result.SpectralCentroid,
+		"deess_pumping":      result.DeEssPumpingIndex,
+		"sibilant_events":    result.SibilantEvents,
+		"has_bass_rolloff":   result.HasBassRolloff,
+		"bass_cutoff_hz":     result.BassCutoffHz,
+		"bass_rolloff_db":    result.BassRolloffDbPerOct,
+		"dominant_tone_hz":   result.DominantToneHz,
+		"tone_prominence_db": result.DominantToneProminenceDb,
+		"tone_dominated":     result.ToneDominated,
+		"odd_harmonics_db":   result.OddHarmonicsDb,
+		"pitch_offset_cents": result.PitchOffsetCents,
+		"pitch_coherence":    result.PitchCoherence,
+		"pitch_peaks":        result.PitchPeaks,
+		"pitch_reference":    result.HasPitchReference,
+		"hf_rolloff":         result.HfRolloffCharacter,
+		"frames":             result.Frames,
+	}
+
+	if result.SpectralTiltBands > 0 {
+		meta["spectral_tilt_db"] = result.SpectralTiltDbPerOct
+		meta["spectral_tilt_bands"] = result.SpectralTiltBands
+	}
+
+	if result.HfRolloffHz > 0 {
+		meta["hf_rolloff_hz"] = result.HfRolloffHz
+		meta["hf_rolloff_db"] = result.HfRolloffDbPerOct
+	}
+
+	if result.LeadInNoiseSec > 0 {
+		meta["lead_in_noise_floor_db"] = result.LeadInNoiseFloorDb
+		meta["lead_in_noise_sec"] = result.LeadInNoiseSec
 	}
 
 	if result.IsUpsampled {
@@ -170,6 +309,45 @@ func SpectralToMap(result *types.SpectralResult) map[string]any {
 		meta["transcode_confidence"] = result.TranscodeConfidence
 		meta["cutoff_consistency_hz"] = result.CutoffConsistency
 		meta["has_ultrasonic_content"] = result.HasUltrasonicContent
+		meta["cutoff_loudness_correlation"] = result.CutoffLoudnessCorrelation
+		meta["vbr_lossy_signature"] = result.VbrLossySignature
+
+		if result.TranscodeEvidence != nil {
+			meta["transcode_evidence"] = result.TranscodeEvidence
+		}
+	}
+
+	if result.BirdieWindowShare > 0 {
+		meta["birdie_window_share"] = result.BirdieWindowShare
+		meta["has_birdies"] = result.HasBirdies
+	}
+
+	if result.HfFlatness > 0 {
+		meta["hf_flatness"] = result.HfFlatness
+		meta["hf_envelope_correlation"] = result.HfEnvelopeCorrelation
+		meta["hf_regeneration_likely"] = result.HfRegenerationLikely
+	}
+
+	if len(result.ConstantTones) > 0 {
+		tones := make([]any, 0, len(result.ConstantTones))
+		for _, tone := range result.ConstantTones {
+			tones = append(tones, map[string]any{"hz": tone.Hz, "db": tone.Db})
+		}
+
+		meta["constant_tones"] = tones
+	}
+
+	if len(result.Notches) > 0 {
+		notches := make([]any, 0, len(result.Notches))
+		for _, notch := range result.Notches {
+			notches = append(notches, map[string]any{
+				"hz":       notch.Hz,
+				"depth_db": notch.DepthDb,
+				"width_hz": notch.WidthHz,
+			})
+		}
+
+		meta["notches"] = notches
 	}
 
 	if len(result.BandEnergy) > 0 {
@@ -191,15 +369,47 @@ func SpectralToMap(result *types.SpectralResult) map[string]any {
 	return meta
 }
 
+func lowBands(bands []types.LowBand) []any {
+	entries := make([]any, 0, len(bands))
+	for _, band := range bands {
+		entries = append(entries, map[string]any{
+			"low_hz":         band.LowHz,
+			"high_hz":        band.HighHz,
+			"correlation":    band.Correlation,
+			"side_to_mid_db": band.SideToMidDb,
+			"level_db":       band.LevelDb,
+			"unsafe":         band.Unsafe,
+		})
+	}
+
+	return entries
+}
+
+func widthTransitions(transitions []types.WidthTransition) []any {
+	entries := make([]any, 0, len(transitions))
+	for _, transition := range transitions {
+		entries = append(entries, map[string]any{
+			"frame":    transition.Frame,
+			"time_sec": transition.TimeSec,
+			"to_mono":  transition.ToMono,
+		})
+	}
+
+	return entries
+}
+
 // SilenceToMap converts silence detection results to a map.
 func SilenceToMap(result *types.SilenceResult) map[string]any {
 	segments := make([]any, 0, len(result.Segments))
 	for _, seg := range result.Segments {
 		segments = append(segments, map[string]any{
-			"start_sec":    seg.StartSec,
-			"end_sec":      seg.EndSec,
-			"duration_sec": seg.DurationSec,
-			"rms_db":       seg.RmsDb,
+			"start_sample":    seg.StartSample,
+			"end_sample":      seg.EndSample,
+			"start_sec":       seg.StartSec,
+			"end_sec":         seg.EndSec,
+			"duration_sec":    seg.DurationSec,
+			"rms_db":          seg.RmsDb,
+			"is_digital_zero": seg.IsDigitalZero,
 		})
 	}
 
@@ -218,6 +428,7 @@ func DropoutToMap(result *types.DropoutResult) map[string]any {
 	events := make([]any, 0, len(result.Events))
 	for _, entry := range result.Events {
 		event := map[string]any{
+			"frame":    entry.Frame,
 			"time_sec": entry.TimeSec,
 			"channel":  entry.Channel,
 			"type":     entry.Type.String(),
@@ -227,15 +438,51 @@ func DropoutToMap(result *types.DropoutResult) map[string]any {
 			event["duration_ms"] = entry.DurationMs
 		}
 
+		if entry.Type == types.EventStuck {
+			event["duration_ms"] = entry.DurationMs
+			event["value"] = fmt.Sprintf("%.4f", entry.Value)
+		}
+
+		if entry.Type == types.EventDCJump {
+			event["dc_before"] = fmt.Sprintf("%.4f", entry.DCBefore)
+			event["dc_after"] = fmt.Sprintf("%.4f", entry.DCAfter)
+		}
+
 		events = append(events, event)
 	}
 
 	return map[string]any{
-		"delta_count":    result.DeltaCount,
-		"zero_run_count": result.ZeroRunCount,
-		"dc_jump_count":  result.DCJumpCount,
-		"worst_db":       result.WorstDb,
-		"frames":         result.Frames,
-		"events":         events,
+		"delta_count":              result.DeltaCount,
+		"zero_run_count":           result.ZeroRunCount,
+		"dc_jump_count":            result.DCJumpCount,
+		"impulse_count":            result.ImpulseCount,
+		"stuck_count":              result.StuckCount,
+		"worst_db":                 result.WorstDb,
+		"periodic_samples":         result.PeriodicSamples,
+		"periodic_fraction":        result.PeriodicFraction,
+		"buffer_boundary_samples":  result.BufferBoundarySamples,
+		"buffer_boundary_fraction": result.BufferBoundaryFraction,
+		"frames":                   result.Frames,
+		"events":                   events,
+	}
+}
+
+// RepeatToMap converts repeated block detection results to a map.
+func RepeatToMap(result *types.RepeatResult) map[string]any {
+	events := make([]any, 0, len(result.Events))
+	for _, entry := range result.Events {
+		events = append(events, map[string]any{
+			"frame":       entry.Frame,
+			"time_sec":    entry.TimeSec,
+			"duration_ms": entry.DurationMs,
+			"distance_ms": entry.DistanceMs,
+		})
+	}
+
+	return map[string]any{
+		"count":      result.Count,
+		"longest_ms": result.LongestMs,
+		"events":     events,
+		"frames":     result.Frames,
 	}
 }
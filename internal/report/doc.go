@@ -0,0 +1,3 @@
+// Package report builds the per-file records of a quality report: probe, decode, analysis and the format
+// policies of one file, in the JSONL record hau-report writes and reads.
+package report
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+)
+
+const expectWildcard = "*"
+
+var errInvalidExpect = errors.New("must be <bits>/<rate>, e.g. 16/44100 (either side may be *)")
+
+// FormatExpectation is a library policy: every track should be this bit depth and sample rate.
+// Zero fields are wildcards.
+type FormatExpectation struct {
+	bitDepth   int
+	sampleRate int
+}
+
+// ParseExpect parses "<bits>/<rate>", e.g. "16/44100". Either side may be "*".
+func ParseExpect(value string) (*FormatExpectation, error) {
+	if value == "" {
+		return nil, nil //nolint:nilnil // no expectation is not an error
+	}
+
+	bits, rate, ok := strings.Cut(value, "/")
+	if !ok {
+		return nil, fmt.Errorf("%q: %w", value, errInvalidExpect)
+	}
+
+	expect := &FormatExpectation{}
+
+	if bits != expectWildcard {
+		bitDepth, err := strconv.Atoi(bits)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", value, errInvalidExpect)
+		}
+
+		if _, err := toBitDepth(bitDepth); err != nil {
+			return nil, fmt.Errorf("%q: %w", value, err)
+		}
+
+		expect.bitDepth = bitDepth
+	}
+
+	if rate != expectWildcard {
+		sampleRate, err := strconv.Atoi(rate)
+		if err != nil || sampleRate <= 0 {
+			return nil, fmt.Errorf("%q: %w", value, errInvalidExpect)
+		}
+
+		expect.sampleRate = sampleRate
+	}
+
+	return expect, nil
+}
+
+func (e *FormatExpectation) String() string {
+	bits, rate := expectWildcard, expectWildcard
+
+	if e.bitDepth > 0 {
+		bits = strconv.Itoa(e.bitDepth)
+	}
+
+	if e.sampleRate > 0 {
+		rate = strconv.Itoa(e.sampleRate)
+	}
+
+	return bits + "/" + rate
+}
+
+// check compares the probed stream format against the expectation.
+// It returns whether the format deviates, and a human-readable description of the probed format when it does.
+// Streams that do not report a bit depth (lossy codecs) deviate from any bit depth expectation.
+func (e *FormatExpectation) check(stream *ffprobe.Stream) (bool, string) {
+	bitDepth := ProbedBitDepth(stream)
+	sampleRate, _ := strconv.Atoi(stream.SampleRate)
+
+	unexpected := (e.bitDepth > 0 && bitDepth != e.bitDepth) ||
+		(e.sampleRate > 0 && sampleRate != e.sampleRate)
+	if !unexpected {
+		return false, ""
+	}
+
+	bits := "?"
+	if bitDepth > 0 {
+		bits = strconv.Itoa(bitDepth)
+	}
+
+	return true, fmt.Sprintf("%s/%d %s (expected %s)", bits, sampleRate, stream.CodecName, e)
+}
+
+// ProbedBitDepth returns the bit depth reported by ffprobe, or 0 if the stream does not carry one.
+func ProbedBitDepth(stream *ffprobe.Stream) int {
+	if stream.BitsPerRawSample != "" {
+		if bits, err := strconv.Atoi(stream.BitsPerRawSample); err == nil && bits > 0 {
+			return bits
+		}
+	}
+
+	return stream.BitsPerSample
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+)
+
+// primingCodecs are the lossy codecs whose encoders always prepend priming samples: a file of theirs with no
+// encoder delay lost the metadata a player needs to trim it, and plays a gap between tracks.
+//
+//nolint:gochecknoglobals
+var primingCodecs = map[string]bool{
+	"mp3":  true,
+	"aac":  true,
+	"opus": true,
+}
+
+// codecFrameSamples is the samples per frame of the fixed-frame lossy codecs, to count the encoder padding.
+//
+//nolint:gochecknoglobals
+var codecFrameSamples = map[string]int{
+	"mp2":  1152,
+	"mp3":  1152,
+	"aac":  1024,
+	"ac3":  1536,
+	"eac3": 1536,
+}
+
+// gaplessMaxPaddingFrames bounds the encoder padding: an encoder pads the last frame, and flushes one more.
+const gaplessMaxPaddingFrames = 2
+
+// gaplessInfo is the encoder delay and padding of a lossy stream, from the probe.
+type gaplessInfo struct {
+	delay   int  // priming samples
+	padding *int // samples after the end of the audio, to the last encoded frame; nil when the probe does not tell
+	ok      bool // the delay and padding allow gapless playback
+	detail  string
+}
+
+// checkGapless reads the encoder delay and padding of a lossy stream, and tells whether they allow gapless
+// playback. The delay is the probed initial padding, or else the offset of the first packet, in samples: the priming
+// a LAME header tells the demuxer to skip, or an MP4 edit list starts before zero. The padding is what remains of
+// the encoded frames after the delay and the probed length, for fixed-frame codecs with a delay. It returns nil
+// for lossless streams.
+func checkGapless(stream *ffprobe.Stream) *gaplessInfo {
+	if !haustorium.IsLossyCodec(stream.CodecName) {
+		return nil
+	}
+
+	rate, _ := strconv.Atoi(stream.SampleRate)
+	info := &gaplessInfo{delay: stream.InitialPadding, ok: true}
+
+	if info.delay == 0 && stream.StartPts != 0 {
+		offset := toSamples(stream.StartPts, stream.TimeBase, rate)
+		info.delay = max(offset, -offset)
+	}
+
+	var problems []string
+
+	missingDelay := info.delay == 0 && primingCodecs[stream.CodecName]
+	if missingDelay {
+		problems = append(problems, "no encoder delay: the priming samples will play as a gap")
+	}
+
+	frameSize := codecFrameSamples[stream.CodecName]
+
+	switch {
+	case stream.CodecName == "mp3" && rate > 0 && rate < 32000:
+		frameSize /= 2 // MPEG-2 and 2.5
+	case stream.CodecName == "aac" && strings.HasPrefix(stream.Profile, "HE-AAC"):
+		frameSize *= 2 // SBR doubles the output rate
+	default:
+	}
+
+	frames, _ := strconv.Atoi(stream.NbFrames)
+	length := toSamples(stream.DurationTS, stream.TimeBase, rate)
+
+	// With no delay, the priming cannot be told from the padding. A negative remainder means the probed length
+	// already counts the delay, or the padding: it does not tell either.
+	padding := frames*frameSize - info.delay - length
+	if !missingDelay && frameSize > 0 && frames > 0 && length > 0 && padding >= 0 {
+		info.padding = &padding
+
+		if padding >= gaplessMaxPaddingFrames*frameSize {
+			problems = append(problems, fmt.Sprintf(
+				"%d samples of padding, over %d frames: they will play as a gap", padding, gaplessMaxPaddingFrames,
+			))
+		}
+	}
+
+	if len(problems) > 0 {
+		info.ok = false
+		info.detail = stream.CodecName + ": " + strings.Join(problems, "; ")
+	}
+
+	return info
+}
+
+// toSamples converts a timestamp in a stream's time base ("1/14112000") to samples at the sample rate.
+func toSamples(timestamp int64, timeBase string, rate int) int {
+	num, den, ok := strings.Cut(timeBase, "/")
+	if !ok {
+		return 0
+	}
+
+	numerator, errNum := strconv.ParseInt(num, 10, 64)
+	denominator, errDen := strconv.ParseInt(den, 10, 64)
+
+	if errNum != nil || errDen != nil || denominator == 0 {
+		return 0
+	}
+
+	return int(timestamp * numerator * int64(rate) / denominator)
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// layoutChannels is the channel count of the ffmpeg channel layout names. Layouts left out are not checked.
+//
+//nolint:gochecknoglobals
+var layoutChannels = map[string]int{
+	"mono":           1,
+	"stereo":         2,
+	"2.1":            3,
+	"3.0":            3,
+	"3.0(back)":      3,
+	"4.0":            4,
+	"quad":           4,
+	"quad(side)":     4,
+	"3.1":            4,
+	"5.0":            5,
+	"5.0(side)":      5,
+	"4.1":            5,
+	"5.1":            6,
+	"5.1(side)":      6,
+	"6.0":            6,
+	"6.0(front)":     6,
+	"hexagonal":      6,
+	"6.1":            7,
+	"6.1(back)":      7,
+	"6.1(front)":     7,
+	"7.0":            7,
+	"7.0(front)":     7,
+	"7.1":            8,
+	"7.1(wide)":      8,
+	"7.1(wide-side)": 8,
+	"octagonal":      8,
+}
+
+// checkChannelLayout compares the probed channel layout with the stream's channel count.
+// It returns whether they disagree, and a description when they do.
+func checkChannelLayout(stream *ffprobe.Stream) (bool, string) {
+	expected, ok := layoutChannels[stream.ChannelLayout]
+	if !ok || expected == stream.Channels {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("layout %s has %d channels, the stream %d",
+		stream.ChannelLayout, expected, stream.Channels)
+}
+
+// checkLFE tells whether the decoded LFE channel is full-range, which a real LFE never is, with a description.
+func checkLFE(loudness *types.LoudnessResult) (bool, string) {
+	if loudness == nil || !loudness.LFEFullRange {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("LFE channel is full-range (%.0f%% of its energy above 200 Hz)",
+		100*loudness.LFEHighBandShare)
+}
@@ -0,0 +1,380 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/fingerprint"
+	"github.com/farcloser/haustorium/internal/integration/ffmpeg"
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/output"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+var (
+	errNoAudioStream     = errors.New("no audio streams found")
+	errAudioStreamIndex  = errors.New("audio stream index not found")
+	errInvalidSampleRate = errors.New("invalid sample rate")
+	errInvalidChannels   = errors.New("invalid channel count")
+	errInvalidBitDepth   = errors.New("must be 16, 24, or 32")
+)
+
+// ProbeSidecarSuffix names cached ffprobe output next to an audio file, e.g. track.flac.ffprobe.json.
+const ProbeSidecarSuffix = ".ffprobe.json"
+
+// Options shapes the record of each file. Zero values leave the policies out.
+type Options struct {
+	FFprobe      string             // ffprobe binary; empty: ffprobe from PATH
+	FFmpeg       string             // ffmpeg binary; empty: ffmpeg from PATH
+	Stream       int                // audio stream to analyze, counted among the audio streams of the file
+	ProbeSidecar bool               // use <file>.ffprobe.json, when present, instead of running ffprobe
+	TagBitDepth  bool               // take the original bit depth from the tags first
+	Expect       *FormatExpectation // nil: no format policy
+	MinDuration  time.Duration      // zero: no length policy
+	Fingerprint  bool               // add content fingerprints to the record
+
+	// Analysis returns the analysis options of a file, its SourceCodec aside (taken from the probe). Nil: the
+	// default options.
+	Analysis func(filePath string) (haustorium.Options, error)
+}
+
+// Process probes, decodes and analyzes a file, and returns its record. A non-nil probe (precomputed ffprobe
+// output) skips the probe. Failures are recorded, not returned: the probe-based policies are kept up to the step
+// that failed.
+func Process(ctx context.Context, filePath string, probe *ffprobe.Result, opts *Options) (record Record) {
+	fileStart := time.Now()
+	timing := &RecordTiming{}
+
+	analyzeOpts := haustorium.DefaultOptions()
+
+	if opts.Analysis != nil {
+		var err error
+
+		analyzeOpts, err = opts.Analysis(filePath)
+		if err != nil {
+			return Record{File: filePath, Error: err.Error()}
+		}
+	}
+
+	// Probe.
+	if probe == nil {
+		probeStart := time.Now()
+
+		var err error
+
+		probe, err = probeFile(ctx, opts.FFprobe, filePath, opts.ProbeSidecar)
+
+		timing.ProbeMs = durationMs(time.Since(probeStart))
+
+		if err != nil {
+			return Record{File: filePath, Error: fmt.Sprintf("probe failed: %v", err), Timing: timing}
+		}
+	}
+
+	stream, err := FindAudioStream(probe, opts.Stream)
+	if err != nil {
+		return Record{File: filePath, Error: fmt.Sprintf("no audio stream: %v", err), Timing: timing}
+	}
+
+	// Format policy relies on probe data only, so it is recorded even if decoding or analysis fails later.
+	if opts.Expect != nil {
+		unexpected, detail := opts.Expect.check(stream)
+
+		defer func() {
+			record.FormatUnexpected = unexpected
+			record.FormatDetail = detail
+		}()
+	}
+
+	// Channel layout, from the probe (recorded even if decoding fails later), then from the decoded LFE.
+	layoutSuspect, layoutDetail := checkChannelLayout(stream)
+
+	defer func() {
+		if layoutSuspect {
+			record.ChannelLayoutSuspect = true
+			record.ChannelLayoutDetail = layoutDetail
+		}
+	}()
+
+	// Gapless metadata of lossy sources, from the probe only.
+	if gapless := checkGapless(stream); gapless != nil {
+		defer func() {
+			record.EncoderDelay = &gapless.delay
+			record.EncoderPadding = gapless.padding
+			record.GaplessMetadataOK = &gapless.ok
+			record.GaplessDetail = gapless.detail
+		}()
+	}
+
+	// Length policy, from the probed duration when known (as the format policy), from the decoded frames otherwise.
+	duration, durationKnown := probeDuration(probe, stream)
+
+	if opts.MinDuration > 0 {
+		defer func() {
+			if durationKnown && duration < opts.MinDuration.Seconds() {
+				record.TooShort = true
+				record.DurationSec = duration
+			}
+		}()
+	}
+
+	// Build PCM format.
+	pcmFormat, err := BuildPCMFormat(stream, opts.TagBitDepth)
+	if err != nil {
+		return Record{File: filePath, Error: fmt.Sprintf("format error: %v", err), Timing: timing}
+	}
+
+	// Extract PCM.
+	decodeStart := time.Now()
+
+	file, err := os.Open(filePath) //nolint:gosec // CLI tool opens user-specified audio files
+	if err != nil {
+		return Record{File: filePath, Error: fmt.Sprintf("open failed: %v", err), Timing: timing}
+	}
+	defer file.Close()
+
+	pcmData, err := extractPCM(ctx, opts.FFmpeg, file, opts.Stream)
+
+	timing.DecodeMs = durationMs(time.Since(decodeStart))
+
+	if err != nil {
+		return Record{File: filePath, Error: fmt.Sprintf("extraction failed: %v", err), Timing: timing}
+	}
+
+	// Build reader factory.
+	frameSize := int(pcmFormat.BitDepth/8) * int(pcmFormat.Channels) //nolint:gosec // small constants
+
+	timing.PCMBytes = len(pcmData)
+	timing.AudioSec = float64(len(pcmData)/frameSize) / float64(pcmFormat.SampleRate)
+
+	if !durationKnown {
+		duration = timing.AudioSec
+		durationKnown = true
+	}
+
+	factory := func() (io.Reader, error) {
+		return bytes.NewReader(pcmData), nil
+	}
+
+	// Run analysis.
+	analyzeStart := time.Now()
+
+	analyzeOpts.SourceCodec = stream.CodecName
+
+	result, err := haustorium.Analyze(factory, pcmFormat, analyzeOpts)
+
+	timing.AnalyzeMs = durationMs(time.Since(analyzeStart))
+	timing.TotalMs = durationMs(time.Since(fileStart))
+
+	if err != nil {
+		return Record{
+			File:      filePath,
+			Error:     fmt.Sprintf("analysis failed: %v", err),
+			ErrorKind: errorKind(err),
+			Timing:    timing,
+		}
+	}
+
+	if !layoutSuspect {
+		layoutSuspect, layoutDetail = checkLFE(result.Loudness)
+	}
+
+	// Build record.
+	record = Record{
+		File:     filePath,
+		Analysis: output.ResultToMap(result),
+		Timing:   timing,
+	}
+
+	if opts.Fingerprint {
+		record.Fingerprint, err = fingerprint.Compute(bytes.NewReader(pcmData), pcmFormat)
+		if err != nil {
+			record.Error = fmt.Sprintf("fingerprint failed: %v", err)
+		}
+	}
+
+	// Serialize probe data (strips tags/disposition since Go structs don't include them).
+	probeJSON, err := json.Marshal(probe)
+	if err == nil {
+		record.Probe = probeJSON
+	} else {
+		record.ProbeError = "probe serialization failed"
+	}
+
+	return record
+}
+
+// Decode probes a file, unless probe is given, and extracts its audio stream opts.Stream to 32-bit PCM in memory,
+// in the format BuildPCMFormat returns. The stream is returned too, for its codec and properties. Only the binary,
+// stream, probe sidecar and bit depth options are used.
+func Decode(
+	ctx context.Context,
+	filePath string,
+	probe *ffprobe.Result,
+	opts *Options,
+) ([]byte, types.PCMFormat, *ffprobe.Stream, error) {
+	if probe == nil {
+		var err error
+
+		probe, err = probeFile(ctx, opts.FFprobe, filePath, opts.ProbeSidecar)
+		if err != nil {
+			return nil, types.PCMFormat{}, nil, fmt.Errorf("probing file: %w", err)
+		}
+	}
+
+	stream, err := FindAudioStream(probe, opts.Stream)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	pcmFormat, err := BuildPCMFormat(stream, opts.TagBitDepth)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, err
+	}
+
+	file, err := os.Open(filePath) //nolint:gosec // CLI tool opens user-specified audio files
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	pcmData, err := extractPCM(ctx, opts.FFmpeg, file, opts.Stream)
+	if err != nil {
+		return nil, types.PCMFormat{}, nil, fmt.Errorf("extracting PCM: %w", err)
+	}
+
+	return pcmData, pcmFormat, stream, nil
+}
+
+// extractPCM decodes the audio stream at index, counted among the audio streams of the file, to 32-bit PCM.
+func extractPCM(ctx context.Context, ffmpegBin string, file io.Reader, index int) ([]byte, error) {
+	var pcmBuf bytes.Buffer
+
+	extractFormat := &types.PCMFormat{BitDepth: types.Depth32}
+
+	if err := ffmpeg.ExtractStream(ctx, ffmpegBin, file, &pcmBuf, index, extractFormat); err != nil {
+		return nil, err
+	}
+
+	return pcmBuf.Bytes(), nil
+}
+
+// probeFile runs ffprobe, unless sidecar lookup is enabled and a cached probe exists for the file.
+func probeFile(ctx context.Context, ffprobeBin, filePath string, sidecar bool) (*ffprobe.Result, error) {
+	if sidecar {
+		sidecarPath := filePath + ProbeSidecarSuffix
+		if _, err := os.Stat(sidecarPath); err == nil {
+			return ffprobe.Load(sidecarPath)
+		}
+	}
+
+	return ffprobe.Probe(ctx, ffprobeBin, filePath)
+}
+
+// FindAudioStream returns the audio stream at index, counted among the audio streams of the probe.
+func FindAudioStream(result *ffprobe.Result, index int) (*ffprobe.Stream, error) {
+	audioCount := 0
+
+	for i := range result.Streams {
+		if result.Streams[i].CodecType == "audio" {
+			if audioCount == index {
+				return &result.Streams[i], nil
+			}
+
+			audioCount++
+		}
+	}
+
+	if audioCount == 0 {
+		return nil, errNoAudioStream
+	}
+
+	return nil, fmt.Errorf("%d (the file has %d audio streams): %w", index, audioCount, errAudioStreamIndex)
+}
+
+// probeDuration returns the stream's duration in seconds, from the stream or the container, when probed.
+func probeDuration(probeResult *ffprobe.Result, stream *ffprobe.Stream) (float64, bool) {
+	for _, raw := range []string{stream.Duration, probeResult.Format.Duration} {
+		if seconds, err := strconv.ParseFloat(raw, 64); err == nil && seconds > 0 {
+			return seconds, true
+		}
+	}
+
+	return 0, false
+}
+
+// BuildPCMFormat returns the format of the stream extracted to 32-bit PCM, its original bit depth resolved from the
+// probe (see resolveExpectedBitDepth).
+func BuildPCMFormat(stream *ffprobe.Stream, tagBitDepth bool) (types.PCMFormat, error) {
+	sampleRate, err := strconv.Atoi(stream.SampleRate)
+	if err != nil || sampleRate <= 0 {
+		return types.PCMFormat{}, fmt.Errorf("%q: %w", stream.SampleRate, errInvalidSampleRate)
+	}
+
+	if stream.Channels <= 0 {
+		return types.PCMFormat{}, fmt.Errorf("%d: %w", stream.Channels, errInvalidChannels)
+	}
+
+	format := types.PCMFormat{
+		SampleRate:       sampleRate,
+		BitDepth:         types.Depth32,
+		Channels:         uint(stream.Channels), //nolint:gosec // validated positive value
+		ExpectedBitDepth: resolveExpectedBitDepth(stream, tagBitDepth),
+	}
+
+	// Extraction is s32: 24-bit sources come out left-justified, over a padding byte.
+	if format.ExpectedBitDepth == types.Depth24 {
+		format.Layout = types.Layout24In32
+	}
+
+	return format, nil
+}
+
+// resolveExpectedBitDepth determines the original bit depth: from a bit depth tag first with fromTags, then from
+// the stream fields, as for haustorium process.
+func resolveExpectedBitDepth(stream *ffprobe.Stream, fromTags bool) types.BitDepth {
+	if fromTags {
+		if bits, ok := stream.TaggedBitDepth(); ok {
+			if bd, err := toBitDepth(bits); err == nil {
+				return bd
+			}
+		}
+	}
+
+	if stream.BitsPerRawSample != "" {
+		if bits, err := strconv.Atoi(stream.BitsPerRawSample); err == nil {
+			if bd, err := toBitDepth(bits); err == nil {
+				return bd
+			}
+		}
+	}
+
+	if stream.BitsPerSample > 0 {
+		if bd, err := toBitDepth(stream.BitsPerSample); err == nil {
+			return bd
+		}
+	}
+
+	return types.Depth32
+}
+
+func toBitDepth(bits int) (types.BitDepth, error) {
+	switch bits {
+	case 16:
+		return types.Depth16, nil
+	case 24:
+		return types.Depth24, nil
+	case 32:
+		return types.Depth32, nil
+	default:
+		return 0, fmt.Errorf("%d: %w", bits, errInvalidBitDepth)
+	}
+}
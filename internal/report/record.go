@@ -0,0 +1,111 @@
+//nolint:tagliatelle
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/farcloser/primordium/fault"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/fingerprint"
+)
+
+// Record is a single line in the JSONL report file: the record of one file, as hau-report report and haustorium
+// process --emit-jsonl write it, an album record, or the header of a report.
+type Record struct {
+	File       string          `json:"file,omitempty"`
+	Analysis   map[string]any  `json:"analysis,omitempty"`
+	Probe      json.RawMessage `json:"probe,omitempty"`
+	ProbeError string          `json:"probe_error,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	ErrorKind  string          `json:"error_kind,omitempty"`
+	Timing     *RecordTiming   `json:"timing,omitempty"`
+
+	// Set with --fingerprint, for hau-report dupes.
+	Fingerprint *fingerprint.Fingerprint `json:"fingerprint,omitempty"`
+
+	// Set when --expect is given and the probed format differs.
+	FormatUnexpected bool   `json:"format_unexpected,omitempty"`
+	FormatDetail     string `json:"format_detail,omitempty"`
+
+	// Set when --min-track-duration is given and the track is shorter.
+	TooShort    bool    `json:"too_short,omitempty"`
+	DurationSec float64 `json:"duration_sec,omitempty"`
+
+	// Set when the probed channel layout disagrees with the channel count, or the decoded LFE is full-range.
+	ChannelLayoutSuspect bool   `json:"channel_layout_suspect,omitempty"`
+	ChannelLayoutDetail  string `json:"channel_layout_detail,omitempty"`
+
+	// Set for lossy sources, from the probe: the encoder delay (priming samples) and padding (nil when the probe
+	// does not tell), and whether they allow gapless playback.
+	EncoderDelay      *int   `json:"encoder_delay,omitempty"`
+	EncoderPadding    *int   `json:"encoder_padding,omitempty"`
+	GaplessMetadataOK *bool  `json:"gapless_metadata_ok,omitempty"`
+	GaplessDetail     string `json:"gapless_detail,omitempty"`
+
+	// Set, alone, on album aggregate records (--album-records).
+	Album *AlbumRecord `json:"album,omitempty"`
+
+	// Set on the first line of a report written by hau-report report: its header.
+	Scan *ScanRecord `json:"scan,omitempty"`
+	// Set on the header of a sampled report (--sample, --sample-n).
+	Sample *SampleRecord `json:"sample,omitempty"`
+}
+
+// ScanRecord tells when the scan of a report started, for the next incremental run (--since-report).
+type ScanRecord struct {
+	Started  time.Time `json:"started"`
+	Since    time.Time `json:"since,omitzero"` // cutoff of an incremental run
+	Complete bool      `json:"complete"`       // false: the run was interrupted, the report lacks files
+}
+
+// SampleRecord holds the sampling parameters of a report, to reproduce it and extrapolate from it.
+type SampleRecord struct {
+	Fraction   float64 `json:"fraction,omitempty"` // --sample, 0-1
+	Count      int     `json:"count,omitempty"`    // --sample-n
+	Seed       uint64  `json:"seed"`
+	Population int     `json:"population"` // files eligible for the report
+	Selected   int     `json:"selected"`
+}
+
+// AlbumRecord aggregates the tracks of one directory.
+type AlbumRecord struct {
+	Dir              string   `json:"dir,omitempty"`
+	Tracks           int      `json:"tracks"`
+	Failed           int      `json:"failed"`
+	LoudnessSpreadLU float64  `json:"loudness_spread_lu"` // max - min integrated loudness across tracks
+	Formats          []string `json:"formats"`            // distinct <bits>/<rate> of the tracks
+	FormatConsistent bool     `json:"format_consistent"`
+}
+
+// RecordTiming captures per-file processing durations in milliseconds, and the amount of audio they were spent on.
+type RecordTiming struct {
+	ProbeMs   float64 `json:"probe_ms"`
+	DecodeMs  float64 `json:"decode_ms"`
+	AnalyzeMs float64 `json:"analyze_ms"`
+	TotalMs   float64 `json:"total_ms"`
+	PCMBytes  int     `json:"pcm_bytes,omitempty"` // decoded PCM handed to the analysis
+	AudioSec  float64 `json:"audio_sec,omitempty"` // duration of that PCM
+}
+
+// errorKind categorizes analysis errors, so that a digest can tell unsupported files from broken ones.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, haustorium.ErrUnsupportedBitDepth):
+		return "unsupported_bit_depth"
+	case errors.Is(err, haustorium.ErrInsufficientData):
+		return "insufficient_data"
+	case errors.Is(err, haustorium.ErrFormatMismatch):
+		return "format_mismatch"
+	case errors.Is(err, fault.ErrReadFailure):
+		return "read_failure"
+	default:
+		return ""
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
+}
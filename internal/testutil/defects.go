@@ -0,0 +1,204 @@
+package testutil
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// DefectKind is the kind of defect injected into a synthetic signal.
+type DefectKind int
+
+const (
+	DefectZeroRun       DefectKind = iota // digital zero, for 5 ms by default
+	DefectDiscontinuity                   // a step from Level (0.8) to zero, held 0.5 ms (under a zero run)
+	DefectDCJump                          // Level (0.2) added to the signal, to its end unless a duration is given
+	DefectClick                           // a single sample at Level (1.0), away from the signal, which resumes
+	DefectClip                            // the signal amplified by Level (4) and clipped at full scale, for 100 ms
+	DefectStuck                           // the signal held at Level (0.3), for 20 ms: a dead converter
+)
+
+func (k DefectKind) String() string {
+	switch k {
+	case DefectZeroRun:
+		return "zero-run"
+	case DefectDiscontinuity:
+		return "discontinuity"
+	case DefectDCJump:
+		return "dc-jump"
+	case DefectClick:
+		return "click"
+	case DefectClip:
+		return "clip"
+	case DefectStuck:
+		return "stuck"
+	}
+
+	return "unknown"
+}
+
+// AllChannels injects a defect on every channel, instead of a single one.
+const AllChannels = -1
+
+const (
+	zeroRunMs           = 5.0
+	discontinuityMs     = 0.5
+	discontinuityLevel  = 0.8
+	dcJumpLevel         = 0.2
+	clickLevel          = 1.0
+	clipMs              = 100.0
+	clipGain            = 4.0
+	stuckMs             = 20.0
+	stuckLevel          = 0.3
+	defaultFixtureSec   = 2.0
+	defaultFixtureDepth = types.Depth16
+)
+
+// Defect is a defect injected at a given time, on a channel (or AllChannels). Zero duration and level stand for
+// the kind's defaults.
+type Defect struct {
+	Kind       DefectKind
+	AtSec      float64
+	DurationMs float64
+	Channel    int
+	Level      float64
+}
+
+// Spec describes a synthetic fixture: a signal, its format (16-bit stereo at 44.1 kHz by default) and duration
+// (2 seconds by default), and the defects injected into it, in order.
+type Spec struct {
+	Signal  Signal
+	Format  types.PCMFormat
+	Seconds float64
+	Defects []Defect
+}
+
+// Fixture renders a spec, and returns a factory of readers over it (usable as a haustorium.ReaderFactory) with
+// its format, so that analyzers can be tested on known defects without external tools.
+func Fixture(spec Spec) (func() (io.Reader, error), types.PCMFormat) {
+	format := spec.format()
+	data := Render(spec)
+
+	return func() (io.Reader, error) {
+		return bytes.NewReader(data), nil
+	}, format
+}
+
+// Render returns the little-endian interleaved PCM of a spec, defects injected.
+func Render(spec Spec) []byte {
+	format := spec.format()
+
+	seconds := spec.Seconds
+	if seconds == 0 {
+		seconds = defaultFixtureSec
+	}
+
+	values := samples(spec.Signal, format, seconds)
+
+	for _, defect := range spec.Defects {
+		inject(values, format, defect)
+	}
+
+	return encode(values, format.BitDepth)
+}
+
+// format returns the spec's format, defaults applied.
+func (s Spec) format() types.PCMFormat {
+	format := s.Format
+
+	if format.SampleRate == 0 {
+		format.SampleRate = 44100
+	}
+
+	if format.BitDepth == 0 {
+		format.BitDepth = defaultFixtureDepth
+	}
+
+	if format.Channels == 0 {
+		format.Channels = 2
+	}
+
+	if format.ExpectedBitDepth == 0 {
+		format.ExpectedBitDepth = format.BitDepth
+	}
+
+	return format
+}
+
+// inject applies a defect to the normalized interleaved samples, in place. Out of range times are clamped to the
+// signal, and samples to full scale.
+func inject(values []float64, format types.PCMFormat, defect Defect) {
+	channels := int(format.Channels)
+	frames := len(values) / channels
+	rate := float64(format.SampleRate)
+
+	start := min(max(int(defect.AtSec*rate), 0), frames)
+	length := func(defaultMs float64) int {
+		durationMs := defect.DurationMs
+		if durationMs == 0 {
+			durationMs = defaultMs
+		}
+
+		return min(int(math.Ceil(durationMs*rate/1000)), frames-start)
+	}
+
+	level := func(defaultLevel float64) float64 {
+		if defect.Level == 0 {
+			return defaultLevel
+		}
+
+		return defect.Level
+	}
+
+	for channel := range channels {
+		if defect.Channel != AllChannels && defect.Channel != channel {
+			continue
+		}
+
+		at := func(frame int) *float64 { return &values[frame*channels+channel] }
+
+		switch defect.Kind {
+		case DefectZeroRun:
+			for frame := start; frame < start+length(zeroRunMs); frame++ {
+				*at(frame) = 0
+			}
+		case DefectDiscontinuity:
+			if start > 0 {
+				*at(start - 1) = math.Copysign(level(discontinuityLevel), *at(start - 1))
+			}
+
+			for frame := start; frame < start+length(discontinuityMs); frame++ {
+				*at(frame) = 0
+			}
+		case DefectDCJump:
+			end := frames
+			if defect.DurationMs != 0 {
+				end = start + length(0)
+			}
+
+			for frame := start; frame < end; frame++ {
+				*at(frame) = clamp(*at(frame) + level(dcJumpLevel))
+			}
+		case DefectClick:
+			if start < frames {
+				// Away from the signal: the spike stands out of both neighbors.
+				*at(start) = -math.Copysign(level(clickLevel), *at(start))
+			}
+		case DefectClip:
+			for frame := start; frame < start+length(clipMs); frame++ {
+				*at(frame) = clamp(*at(frame) * level(clipGain))
+			}
+		case DefectStuck:
+			for frame := start; frame < start+length(stuckMs); frame++ {
+				*at(frame) = level(stuckLevel)
+			}
+		default:
+		}
+	}
+}
+
+func clamp(value float64) float64 {
+	return min(max(value, -1), 1)
+}
@@ -0,0 +1,175 @@
+// Package testutil provides synthetic PCM buffers for analyzer tests and benchmarks.
+package testutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// Signal is the kind of synthetic content to generate.
+type Signal int
+
+const (
+	SignalSine    Signal = iota // 1 kHz sine at -6 dBFS, identical on all channels
+	SignalNoise                 // uniform white noise at -12 dBFS peak, independent per channel
+	SignalSilence               // digital zero
+)
+
+func (s Signal) String() string {
+	switch s {
+	case SignalSine:
+		return "sine"
+	case SignalNoise:
+		return "noise"
+	case SignalSilence:
+		return "silence"
+	}
+
+	return "unknown"
+}
+
+const (
+	sineFreq      = 1000.0
+	sineAmplitude = 0.5  // -6 dBFS
+	noiseAmp      = 0.25 // -12 dBFS
+	benchSeconds  = 2.0
+)
+
+// Generate returns little-endian interleaved PCM for the given signal, format and duration.
+// Noise is seeded, so buffers are reproducible across runs.
+func Generate(signal Signal, format types.PCMFormat, seconds float64) []byte {
+	return encode(samples(signal, format, seconds), format.BitDepth)
+}
+
+// Synthesize returns little-endian interleaved PCM of an arbitrary signal, for content the fixture signals cannot
+// carry: value returns the normalized sample of each frame and channel, and is called in that order.
+func Synthesize(format types.PCMFormat, seconds float64, value func(frame, channel int) float64) []byte {
+	channels := int(format.Channels)
+	frames := int(seconds * float64(format.SampleRate))
+	values := make([]float64, frames*channels)
+
+	for frame := range frames {
+		for channel := range channels {
+			values[frame*channels+channel] = clamp(value(frame, channel))
+		}
+	}
+
+	return encode(values, format.BitDepth)
+}
+
+// samples returns the normalized interleaved samples of the given signal.
+func samples(signal Signal, format types.PCMFormat, seconds float64) []float64 {
+	channels := int(format.Channels)
+	frames := int(seconds * float64(format.SampleRate))
+	values := make([]float64, frames*channels)
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for frame := range frames {
+		for channel := range channels {
+			var value float64
+
+			switch signal {
+			case SignalSine:
+				value = sineAmplitude * math.Sin(2*math.Pi*sineFreq*float64(frame)/float64(format.SampleRate))
+			case SignalNoise:
+				value = noiseAmp * (2*rng.Float64() - 1)
+			case SignalSilence:
+			}
+
+			values[frame*channels+channel] = value
+		}
+	}
+
+	return values
+}
+
+// encode returns normalized samples as little-endian PCM at the given bit depth.
+func encode(values []float64, depth types.BitDepth) []byte {
+	bytesPerSample := int(depth / 8)
+	data := make([]byte, len(values)*bytesPerSample)
+
+	for i, value := range values {
+		putSample(data[i*bytesPerSample:], value, depth)
+	}
+
+	return data
+}
+
+// putSample encodes a normalized value (-1.0 to 1.0) at the given bit depth. -1.0 is the most negative code,
+// so that a clipped signal is clipped on both sides.
+func putSample(dst []byte, value float64, depth types.BitDepth) {
+	switch depth {
+	case types.Depth16:
+		sample := int16(value * math.MaxInt16)
+		if value <= -1 {
+			sample = math.MinInt16
+		}
+
+		binary.LittleEndian.PutUint16(dst, uint16(sample))
+	case types.Depth24:
+		sample := int32(value * (1<<23 - 1))
+		if value <= -1 {
+			sample = -1 << 23
+		}
+
+		dst[0] = byte(sample)
+		dst[1] = byte(sample >> 8)
+		dst[2] = byte(sample >> 16)
+	case types.Depth32:
+		sample := int32(value * math.MaxInt32)
+		if value <= -1 {
+			sample = math.MinInt32
+		}
+
+		binary.LittleEndian.PutUint32(dst, uint32(sample))
+	}
+}
+
+// BenchFormats returns the stereo formats covered by analyzer benchmarks: 44.1 and 96 kHz, at 16, 24 and 32-bit.
+func BenchFormats() []types.PCMFormat {
+	var formats []types.PCMFormat
+
+	for _, rate := range []int{44100, 96000} {
+		for _, depth := range []types.BitDepth{types.Depth16, types.Depth24, types.Depth32} {
+			formats = append(formats, types.PCMFormat{
+				SampleRate:       rate,
+				BitDepth:         depth,
+				Channels:         2,
+				ExpectedBitDepth: depth,
+			})
+		}
+	}
+
+	return formats
+}
+
+// Bench runs analyze over every benchmark signal and format, reporting MB/s of PCM processed.
+// The reader is a *bytes.Reader, so it also satisfies io.ReadSeeker.
+func Bench(b *testing.B, analyze func(reader io.ReadSeeker, format types.PCMFormat) error) {
+	b.Helper()
+
+	for _, signal := range []Signal{SignalSine, SignalNoise, SignalSilence} {
+		for _, format := range BenchFormats() {
+			data := Generate(signal, format, benchSeconds)
+			name := fmt.Sprintf("%s/%dHz/%dbit", signal, format.SampleRate, format.BitDepth)
+
+			b.Run(name, func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportAllocs()
+
+				for b.Loop() {
+					if err := analyze(bytes.NewReader(data), format); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
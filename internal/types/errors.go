@@ -0,0 +1,67 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnsupportedBitDepth is returned when the PCM bit depth is not 16, 24 or 32.
+	ErrUnsupportedBitDepth = errors.New("unsupported bit depth")
+	// ErrInsufficientData is returned when the input does not hold a single complete frame.
+	ErrInsufficientData = errors.New("insufficient data")
+	// ErrFormatMismatch is returned when format parameters are inconsistent (no channels, no sample rate,
+	// an expected bit depth higher than the PCM bit depth, or a 24-in-32 layout outside 32-bit PCM).
+	ErrFormatMismatch = errors.New("format mismatch")
+)
+
+// Validate checks that the format can be analyzed.
+func (f PCMFormat) Validate() error {
+	if !f.BitDepth.supported() {
+		return fmt.Errorf("%w: %d", ErrUnsupportedBitDepth, f.BitDepth)
+	}
+
+	if f.ExpectedBitDepth != 0 && !f.ExpectedBitDepth.supported() {
+		return fmt.Errorf("%w: expected %d", ErrUnsupportedBitDepth, f.ExpectedBitDepth)
+	}
+
+	if f.Channels == 0 {
+		return fmt.Errorf("%w: zero channels", ErrFormatMismatch)
+	}
+
+	if f.SampleRate <= 0 {
+		return fmt.Errorf("%w: sample rate %d", ErrFormatMismatch, f.SampleRate)
+	}
+
+	if f.Layout == Layout24In32 && f.BitDepth != Depth32 {
+		return fmt.Errorf("%w: 24-in-32 layout with PCM bit depth %d", ErrFormatMismatch, f.BitDepth)
+	}
+
+	if f.Layout > Layout24In32 {
+		return fmt.Errorf("%w: layout %d", ErrFormatMismatch, f.Layout)
+	}
+
+	if f.ByteOrder > BigEndian {
+		return fmt.Errorf("%w: byte order %d", ErrFormatMismatch, f.ByteOrder)
+	}
+
+	if f.ExpectedBitDepth > f.BitDepth {
+		return fmt.Errorf(
+			"%w: expected bit depth %d exceeds PCM bit depth %d",
+			ErrFormatMismatch,
+			f.ExpectedBitDepth,
+			f.BitDepth,
+		)
+	}
+
+	return nil
+}
+
+func (b BitDepth) supported() bool {
+	switch b {
+	case Depth16, Depth24, Depth32:
+		return true
+	default:
+		return false
+	}
+}
@@ -9,6 +9,28 @@ const (
 	Depth32 BitDepth = 32
 )
 
+// Layout is how samples sit in their container.
+type Layout uint8
+
+const (
+	// LayoutNative samples use the whole container.
+	LayoutNative Layout = iota
+	// Layout24In32 samples are 24-bit, left-justified in 32-bit containers (some WAVs, ffmpeg s32 output from
+	// 24-bit sources): the low byte is padding, not a sign of fake bit depth. Analyze reads the samples without
+	// it, and warns when it holds data.
+	Layout24In32
+)
+
+// ByteOrder is the byte order of the samples.
+type ByteOrder uint8
+
+const (
+	// LittleEndian samples, as every analyzer reads them.
+	LittleEndian ByteOrder = iota
+	// BigEndian samples (AIFF, some raw captures) are byte-swapped once, before the analyzers.
+	BigEndian
+)
+
 // PCMFormat of the original input before PCM extraction (except BitDepth, from the PCM, vs. ExpectedBitDepth,
 // from the original media).
 type PCMFormat struct {
@@ -16,6 +38,17 @@ type PCMFormat struct {
 	BitDepth         BitDepth
 	Channels         uint
 	ExpectedBitDepth BitDepth
+	Layout           Layout
+	ByteOrder        ByteOrder
+}
+
+// WithLayout returns the format with the expected bit depth its layout implies: at most 24 for Layout24In32.
+func (f PCMFormat) WithLayout() PCMFormat {
+	if f.Layout == Layout24In32 && (f.ExpectedBitDepth == 0 || f.ExpectedBitDepth > Depth24) {
+		f.ExpectedBitDepth = Depth24
+	}
+
+	return f
 }
 
 // BitDepthAuthenticity contains results returned by the bitdepth analyzer.
@@ -24,6 +57,46 @@ type BitDepthAuthenticity struct {
 	Effective BitDepth // what it actually is
 	IsPadded  bool     // Effective < Claimed
 	Samples   uint64   // total samples analyzed
+
+	// Sections, measured in the windowed mode only (Windows is 0 otherwise).
+	ConsistentAcrossTrack bool     // false when some sections are zero-padded and others are not
+	Windows               uint64   // 1 s windows measured, digital silence excluded
+	PaddedWindows         uint64   // windows with fewer bits than claimed
+	PaddedEffective       BitDepth // lowest effective depth of the padded windows, when inconsistent
+	FirstPaddedSec        float64  // start of the first padded window, when inconsistent
+}
+
+/*
+Dither Interpretation
+
+Only quiet, non-silent windows (RMS between 0.5 and 16 LSBs of the expected bit depth) are considered.
+
+| HoldRatio | Interpretation                                             |
+|-----------|------------------------------------------------------------|
+| < 0.6     | Dithered (or analog noise above the LSB). OK.              |
+| 0.6-0.75  | Ambiguous. Sparse quiet material, or very low-level bass.  |
+| > 0.75    | Staircase quantization. Likely truncated without dither.   |
+
+Few QuietWindows (under ~1s) means the track is too loud for the check to say anything.
+
+The same ratio over the fade-out's quiet tail alone (FadeoutHoldRatio) catches a fade truncated without dither,
+where zipper noise is most audible, even when the rest of the quiet material is dithered or too short to judge.
+*/
+
+// DitherResult contains results returned by the dither analyzer.
+type DitherResult struct {
+	UnditheredLikely bool    // HoldRatio above threshold over enough quiet material
+	HoldRatio        float64 // fraction of consecutive samples repeating the same level in quiet windows
+	QuietWindows     uint64  // number of quiet windows analyzed
+	QuietSec         float64 // duration of quiet material analyzed
+
+	// The fade-out on its own: the quiet windows the track's last signal fades through, trailing silence left out,
+	// after louder music. FadeoutSec is 0 when the track ends loud (a hard ending, a cut) or is quiet throughout.
+	FadeoutQuantizationLikely bool    // FadeoutHoldRatio above threshold over at least 0.5 s of fade-out
+	FadeoutHoldRatio          float64 // hold ratio of the fade-out's quiet windows
+	FadeoutSec                float64 // duration of the fade-out's quiet windows
+
+	Frames uint64
 }
 
 // ChannelClipping contains per channel clipping detection results.
@@ -31,6 +104,8 @@ type ChannelClipping struct {
 	Events         uint64
 	ClippedSamples uint64
 	LongestRun     uint64
+	PositiveEvents uint64 // events at positive full scale; one polarity only points at DC offset, not gain
+	NegativeEvents uint64 // events at negative full scale
 }
 
 // ClippingDetection contains overall clipping detection results.
@@ -38,8 +113,130 @@ type ClippingDetection struct {
 	Events         uint64
 	ClippedSamples uint64
 	LongestRun     uint64
+	LongRunSamples uint64 // clipped samples in runs over 1 ms, which declipping cannot interpolate across
 	Samples        uint64
 	Channels       []ChannelClipping
+	Recoverability Recoverability // how far declipping can repair the full-scale clipping
+
+	// Plateaus: flat runs below full scale, left behind when a clipped master is gain-reduced.
+	// Only populated when plateau detection is enabled.
+	PlateauEvents      uint64  // all sub-full-scale plateaus
+	PlateauSamples     uint64  // samples in those plateaus
+	PlateauLevelDb     float64 // dominant plateau level (dBFS)
+	PlateauLevelEvents uint64  // plateaus at the dominant level; high counts here mean clip-then-normalize
+
+	// Where the clipping concentrates, from short spectra around the clipping events.
+	Band      FrequencyBand // band holding most of the energy around them; BandNone = too few events to tell
+	BandShare float64       // share of their energy in that band (0-1)
+}
+
+/*
+Frequency Bands
+
+Coarse bands, to tell what drives a defect: the kick and bass, the voice and guitars, or the cymbals.
+
+| Band       | Range          |
+|------------|----------------|
+| Bass       | < 250 Hz       |
+| Mids       | 250 Hz - 2 kHz |
+| Upper mids | 2 - 6 kHz      |
+| Treble     | > 6 kHz        |
+| Broadband  | no band holds half of the energy |
+*/
+
+// FrequencyBand is a coarse frequency band.
+type FrequencyBand uint8
+
+const (
+	// BandNone: too little to tell.
+	BandNone FrequencyBand = iota
+	BandBass
+	BandMids
+	BandUpperMids
+	BandTreble
+	// BandBroadband: spread across the bands.
+	BandBroadband
+)
+
+const (
+	bassMaxHz      = 250
+	midsMaxHz      = 2000
+	upperMidsMaxHz = 6000
+)
+
+// BandOf returns the band of a frequency (Hz).
+func BandOf(hz float64) FrequencyBand {
+	switch {
+	case hz < bassMaxHz:
+		return BandBass
+	case hz < midsMaxHz:
+		return BandMids
+	case hz < upperMidsMaxHz:
+		return BandUpperMids
+	default:
+		return BandTreble
+	}
+}
+
+func (b FrequencyBand) String() string {
+	switch b {
+	case BandNone:
+		return "none"
+	case BandBass:
+		return "bass"
+	case BandMids:
+		return "mids"
+	case BandUpperMids:
+		return "upper mids"
+	case BandTreble:
+		return "treble"
+	case BandBroadband:
+		return "broadband"
+	}
+
+	return "unknown"
+}
+
+/*
+Clipping Recoverability
+
+Declipping reconstructs the clipped tops from the waveform around them. It works on short, isolated runs, and has
+nothing to go on across long saturated stretches.
+
+| Recoverability | Clipped samples | Samples in runs over 1 ms |
+|----------------|-----------------|---------------------------|
+| Restorable     | < 0.1%          | < 5% of the clipped ones  |
+| Unrecoverable  | > 2%            | > 50% of the clipped ones |
+| Partial        | in between      | in between                |
+*/
+
+// Recoverability classifies how far clipping can be repaired by declipping.
+type Recoverability uint8
+
+const (
+	// RecoverabilityNone: no full-scale clipping.
+	RecoverabilityNone Recoverability = iota
+	// Restorable clipping is sparse and short: declipping can rebuild it.
+	Restorable
+	// PartiallyRestorable clipping can be improved, not undone.
+	PartiallyRestorable
+	// Unrecoverable clipping is pervasive or long-saturated: the original waveform is gone.
+	Unrecoverable
+)
+
+func (r Recoverability) String() string {
+	switch r {
+	case RecoverabilityNone:
+		return "none"
+	case Restorable:
+		return "restorable"
+	case PartiallyRestorable:
+		return "partial"
+	case Unrecoverable:
+		return "unrecoverable"
+	}
+
+	return "unknown"
 }
 
 /*
@@ -94,6 +291,11 @@ type TruncationDetection struct {
 	FinalRmsDb    float64 // RMS of final window in dB
 	FinalPeakDb   float64 // Peak of final window in dB
 	SamplesInTail uint64
+
+	// The head window, for a lead-in trimmed into the first note.
+	FirstFrameDb float64 // level of the first frame, loudest channel
+	LeadInRmsDb  float64 // RMS of the first 5 ms
+	HeadPeakDb   float64 // peak of the head window
 }
 
 /*
@@ -170,6 +372,21 @@ Stereo Analysis Interpretation
 
 Sign: positive = left louder, negative = right louder.
 
+| ImbalanceVarianceDb | Interpretation                                     |
+|---------------------|----------------------------------------------------|
+| < 0.75 dB           | Steady offset: a gain error, fixable in the mix.   |
+| > 2 dB              | Erratic: intermittent cable or connector fault.    |
+
+## Suspected Downmix
+
+| MidCorrelation | SideCorrelation | Interpretation                              |
+|----------------|-----------------|---------------------------------------------|
+| > 0.8          | < -0.2          | Center buildup + anti-phase surrounds: 5.1  |
+|                |                 | (or matrixed surround) folded to stereo     |
+| > 0.8          | > 0             | Centered vocals. Normal studio mix.         |
+
+Niche: mostly relevant for concert films and TV audio ripped to stereo.
+
 ## Decision Tree
 
     if Correlation > 0.98 && DifferenceDb < -60 {
@@ -195,7 +412,55 @@ type StereoResult struct {
 	LeftRmsDb      float64 // RMS of left channel
 	RightRmsDb     float64 // RMS of right channel
 	ImbalanceDb    float64 // LeftRmsDb - RightRmsDb; positive = left louder
-	Frames         uint64
+
+	// Spread (standard deviation, in dB) of the imbalance across 500 ms windows: low with a steady offset (a gain
+	// error), high when a channel cuts in and out (a cable or connector fault). 0 with too little loud material.
+	ImbalanceVarianceDb float64
+
+	// Multichannel downmix heuristics
+	MidCorrelation   float64 // L/R correlation in 300 Hz-3 kHz (dialog/vocals)
+	SideCorrelation  float64 // L/R correlation in 4-12 kHz (ambience, surrounds)
+	SuspectedDownmix bool    // midrange near-mono + anti-phase upper band: surround source folded to stereo
+
+	// Vinyl cutting safety: bass must be mono-compatible, and free of subsonic content
+	LowBands       []LowBand // 20-40, 40-80 and 80-150 Hz
+	SubsonicDb     float64   // 5-20 Hz power relative to the full band
+	SubsonicExcess bool
+	VinylCutUnsafe bool // any unsafe low band, or subsonic excess
+
+	// Synthetic width: a comb-like pattern across frequency, in the L/R correlation or balance, left by
+	// pseudo-stereo processing of a mono source (delay or complementary comb filters)
+	CombPeriodHz         float64 // spacing of the pattern (1 / delay); 0 when none was found
+	CombStrength         float64 // normalized autocorrelation of the pattern at its period (0-1)
+	SyntheticWidthLikely bool
+
+	// Artificial decorrelation: a broadband correlation at a flat zero, with the two channels' spectra alike
+	// (SpectralMismatchDb, the spread of the per-octave L/R level differences, under 0.5 dB): decorrelated copies
+	// of one source (all-pass or Haas widener), rather than genuine wide stereo
+	SpectralMismatchDb float64
+	DecorrelatedLikely bool
+
+	// Sustained switches between mono and stereo sections, in track order, found over 1 s windows
+	WidthTransitions []WidthTransition
+
+	Frames uint64
+}
+
+// A WidthTransition is where a track switches between mono and stereo, for at least five seconds.
+type WidthTransition struct {
+	Frame   uint64
+	TimeSec float64
+	ToMono  bool // stereo to mono; false for mono to stereo
+}
+
+// A LowBand holds the stereo measurements of one bass band.
+type LowBand struct {
+	LowHz       float64
+	HighHz      float64
+	Correlation float64 // L/R correlation within the band
+	SideToMidDb float64 // side (L-R) power relative to mid (L+R); 0 dB = as wide as it is centered
+	LevelDb     float64 // band power relative to the full band
+	Unsafe      bool    // loud enough to matter, and too wide or out of phase (correlation < 0.5)
 }
 
 /*
@@ -248,6 +513,9 @@ type SilenceSegment struct {
 	EndSec      float64
 	DurationSec float64
 	RmsDb       float64 // actual level during this segment
+	// IsDigitalZero is set when every sample of the segment is exactly 0: digital black, as opposed to low-level
+	// noise (tape hiss, a transfer chain) under the threshold.
+	IsDigitalZero bool
 }
 
 // SilenceResult aggregates all silence segments and provide high level result.
@@ -260,6 +528,16 @@ type SilenceResult struct {
 	Frames        uint64
 }
 
+// A TrackSplit is a proposed track boundary in a continuous transfer, in CUE sheet terms:
+// the pregap (INDEX 00) starts where the preceding silence starts, the track (INDEX 01) where it ends.
+type TrackSplit struct {
+	Number       int
+	PregapSample uint64 // equal to StartSample when the track has no pregap
+	StartSample  uint64
+	PregapSec    float64
+	StartSec     float64
+}
+
 /*
 Spectral Analysis Interpretation
 
@@ -272,6 +550,7 @@ Spectral Analysis Interpretation
 | true        | 48000         | 48k upsampled to 96/192k             |
 | true        | 88200         | 88.2k upsampled to 176.4k            |
 | true        | 96000         | 96k upsampled to 192k                |
+| true        | 8000-22050    | Voice/game audio upsampled to 44.1k+ |
 
 | Sharpness (dB/oct) | Interpretation                       |
 |--------------------|--------------------------------------|
@@ -358,6 +637,19 @@ TranscodeSharpness > 50 dB/octave = obvious brick wall
     }
 */
 
+// ConstantTone is a steady narrow line in the spectrum, away from the mains frequencies.
+type ConstantTone struct {
+	Hz float64
+	Db float64 // level relative to the reference band (1-10kHz by default)
+}
+
+// SpectralNotch is a narrow hole in the average spectrum: a notch filter, or the crossover of a chain.
+type SpectralNotch struct {
+	Hz      float64 // deepest bin
+	DepthDb float64 // under the spectrum around it
+	WidthHz float64 // of the bins 6 dB or more under the spectrum around it
+}
+
 // SpectralResult contains the result of spectral analysis.
 type SpectralResult struct {
 	// Sample rate authenticity
@@ -375,17 +667,83 @@ type SpectralResult struct {
 	TranscodeConfidence  float64 // 0.0-1.0; reduced when cutoff looks like mastering LPF
 	CutoffConsistency    float64 // stddev of cutoff frequency across windows; low = mastering filter
 	HasUltrasonicContent bool    // true if any content exists above the detected cutoff
+	// Correlation (-1 to 1) of the per-window cutoff with the window level. A cutoff that moves (stddev >= 50 Hz)
+	// with loudness (correlation >= 0.5) is a VBR encoder's: VbrLossySignature, raising TranscodeConfidence.
+	CutoffLoudnessCorrelation float64
+	VbrLossySignature         bool
+	// Codec "birdies": brief, unstable tones in the 10-20 kHz band, islands in a spectrum the encoder emptied.
+	// BirdieWindowShare is the share of the windows holding at least three (0-1); HasBirdies, from a quarter of
+	// them, raises TranscodeConfidence.
+	BirdieWindowShare float64
+	HasBirdies        bool
+	// High band refill (spectral band replication, noise filling) over a lossy cutoff: HfFlatness is the mean
+	// Wiener entropy of the 16-20 kHz band (1 = white noise), HfEnvelopeCorrelation its level against the 8-16 kHz
+	// band's, across the windows. HfRegenerationLikely, for a loud, flat band unrelated to the music under it,
+	// raises TranscodeConfidence.
+	HfFlatness            float64
+	HfEnvelopeCorrelation float64
+	HfRegenerationLikely  bool
+	// Confidence adjustments of the V2 detector ("base", then "consistency", "ultrasonic", "frequency_penalty",
+	// "sharpness", "vbr", "birdies", "hf_regeneration"): they sum to TranscodeConfidence, before clamping. Nil
+	// when no candidate cutoff was found.
+	TranscodeEvidence map[string]float64
+
+	// High-end character: how the spectrum ends, whether or not it is a defect
+	HfRolloffCharacter string  // "full-bandwidth", "brick-wall" (filter, codec), "gentle" (bandwidth-limited source)
+	HfRolloffHz        float64 // where the level falls 30 dB under the reference band; 0 = full bandwidth
+	HfRolloffDbPerOct  float64 // average slope over the octave around the roll-off point
 
 	// Hum detection
 	Has50HzHum bool
 	Has60HzHum bool
 	HumLevelDb float64 // level of worst hum relative to signal
 
+	// Constant tones at other frequencies (CRT whine, ventilation resonance, a whistle in the chain): narrow lines
+	// steady across the windows, strongest first. Nil when none.
+	ConstantTones []ConstantTone
+
+	// Narrow holes in the average spectrum (200 Hz-16 kHz), by frequency: a notch filter, the crossover of a
+	// playback chain. Recurring across tracks, the same notches fingerprint the chain they went through
+	// (hau-report notches). Nil when none.
+	Notches []SpectralNotch
+
 	// Noise floor
-	NoiseFloorDb float64 // HF noise level relative to 1-10kHz
+	NoiseFloorDb float64 // HF noise level relative to the reference band (1-10kHz by default)
+
+	// Noise floor of the leading and trailing silence alone, on the NoiseFloorDb scale: the cleanest read on the
+	// transfer chain's noise. Only measured with Options.EdgeNoiseFloor; LeadInNoiseSec is 0 when the silent
+	// edges were too short (or absent).
+	LeadInNoiseFloorDb float64
+	LeadInNoiseSec     float64
+
+	// Low-end brick wall (aggressive high-pass filter)
+	HasBassRolloff      bool
+	BassCutoffHz        float64 // where the level falls 6 dB under the 80-250 Hz band; 0 = not found
+	BassRolloffDbPerOct float64 // steepness over the octave below the cutoff
+
+	// Dominant tone (strongest peak, 20 Hz-5 kHz), to corroborate clipping: a clipped tone grows odd harmonics
+	DominantToneHz           float64
+	DominantToneProminenceDb float64 // peak over the median spectrum
+	ToneDominated            bool    // prominence of 40 dB or more: a single tone (test signal, sustained note)
+	OddHarmonicsDb           float64 // strongest of the 3rd and 5th harmonics relative to the tone, if ToneDominated
+
+	// Tuning: offset of the tonal peaks from equal temperament. On a transfer, a speed error.
+	PitchOffsetCents  float64 // -50 to +50: beyond, the nearest note is the next one
+	PitchCoherence    float64 // agreement of the peaks on the offset (0-1)
+	PitchPeaks        int     // spectral peaks measured
+	HasPitchReference bool    // enough coherent tonal content for PitchOffsetCents to mean something
 
 	// Tonal character
 	SpectralCentroid float64 // Hz; higher = brighter
+	// Slope of the average spectrum over the 125 Hz-8 kHz octave bands, in dB/octave relative to pink noise:
+	// 0 = equal energy per octave, positive = brighter. SpectralTiltBands is the number of bands fitted; 0 when
+	// not measured (too few bands with music under Nyquist).
+	SpectralTiltDbPerOct float64
+	SpectralTiltBands    int
+
+	// De-essing (5-9 kHz ducked while the air band rises on sibilants)
+	DeEssPumpingIndex float64 // fraction of sibilant sub-frames showing a 5-9 kHz duck (0-1)
+	SibilantEvents    int     // sibilant sub-frames found (air band onsets)
 
 	// Raw data for debugging/display
 	BandEnergy []float64
@@ -470,6 +828,13 @@ type TruePeakResult struct {
 	ISPsAbove1dB    uint64  // count of ISPs with >1.0dB overshoot
 	ISPsAbove2dB    uint64  // count of ISPs with >2.0dB overshoot
 	WorstDensitySec float64 // timestamp (seconds) of peak density window
+
+	// Where ISPs concentrate, from short spectra (above 1 kHz) around them: a narrow EQ boost shows as one region
+	ISPRegionHz    float64 // center of the strongest region; 0 = too few ISPs to tell
+	ISPRegionShare float64 // share of the ISP spectra's energy in that region, ~345 Hz wide at 44.1 kHz (0-1)
+
+	// Limiting pervasiveness, independent of absolute level
+	OversPervasiveFraction float64 // share of non-silent 50 ms blocks with true peak >0.3 dB above their sample peak
 }
 
 /*
@@ -539,6 +904,7 @@ A track can be:
 type LoudnessResult struct {
 	// EBU R128 LUFS
 	IntegratedLUFS float64 // overall loudness (gated)
+	GatedCoverage  float64 // fraction of 400ms blocks that passed both gates (0-1)
 	ShortTermMax   float64 // max 3s window
 	MomentaryMax   float64 // max 400ms window
 	LoudnessRange  float64 // LRA in LU
@@ -549,6 +915,41 @@ type LoudnessResult struct {
 	PeakDb  float64 // peak level used
 	RmsDb   float64 // RMS level used
 
+	// Limiting, independent of DR: fraction of transients (0-1) that hit the track's ceiling with a squared-off
+	// attack. Near 0 for a dynamic track, even with a few limited peaks; near 1 for a uniformly limited master.
+	// 0 when fewer than 20 transients were found.
+	TransientFlatteningIndex float64
+	Transients               int // onsets found
+
+	// Clipping at the sample level: fraction of transients (0-1) whose attack peaks on a flat top (4 identical
+	// consecutive samples, at any level). A limiter rounds the peaks it shaves, a clipper cuts them flat: near 1
+	// for a master pushed into a clipper, every drum hit squared. 0 when fewer than 20 transients were found.
+	TransientsClippedFraction float64
+
+	// Apparent ceiling of a previous hard limiter (dBFS): the level where samples pile up under full scale, with
+	// next to nothing above. 0 when the amplitude histogram shows no such spike.
+	LimiterCeilingDb float64
+
+	// Quietest 400 ms passage: its unweighted RMS level (dBFS) and start, digital silence left out. How quiet the
+	// track gets, in the time domain: the noise floor where the music stops, a fade's tail otherwise.
+	// -120 with no signal.
+	QuietestPassageDbFS float64
+	QuietestPassageSec  float64
+
+	// Stereo: how much quieter (LU) the integrated loudness of the mono sum, (L+R)/2 on both speakers, is than
+	// the stereo's. What a phone speaker or a mono platform plays: 0 for a mono-compatible center, about 3 for
+	// uncorrelated channels, more with out-of-phase content. Stereo only.
+	MonoMeasured          bool
+	MonoLoudnessPenaltyLU float64
+
+	// Surround (5.1 and 7.1, LFE assumed on the 4th channel): the LFE channel's RMS level (dBFS), and the share of
+	// its energy above 200 Hz (0-1). A real LFE is low-passed around 120 Hz; a full-range channel in its place
+	// means a wrong channel layout, and a loudness that left out a real channel.
+	LFEMeasured      bool
+	LFELevelDb       float64
+	LFEHighBandShare float64
+	LFEFullRange     bool
+
 	Frames uint64
 }
 
@@ -562,6 +963,8 @@ Dropout/Glitch Detection Interpretation
 | delta     | Sudden sample jump (buffer underrun, bad edit) |
 | zero_run  | Digital silence (DAT dropout, USB glitch) |
 | dc_jump   | Sudden offset shift (bad splice, hardware) |
+| impulse   | Isolated full-scale sample (bit error, bad sector) |
+| stuck     | Constant non-zero sample value (dead converter, DC flatline) |
 
 ## Delta Severity
 
@@ -603,7 +1006,9 @@ Dropout/Glitch Detection Interpretation
 | Single delta, one channel    | Bad edit point             |
 | Zero runs, both channels     | Buffer underrun, USB glitch|
 | Zero runs, one channel       | DAT/tape dropout           |
+| Stuck runs                   | Failing ADC, dead converter|
 | DC jumps throughout          | Hardware issue, bad ADC    |
+| Scattered impulses           | Bit errors, bad sectors    |
 | Deltas at regular intervals  | Clock sync issue           |
 
 ## Relationship to Other Analyses
@@ -620,7 +1025,10 @@ type Event struct {
 	Channel    int
 	Type       EventType
 	Severity   float64 // magnitude of discontinuity (0-1 normalized)
-	DurationMs float64 // for zero runs
+	DurationMs float64 // for zero and stuck runs
+	Value      float64 // for stuck runs: the stuck sample value (normalized)
+	DCBefore   float64 // for DC jumps: DC level over the window before the jump
+	DCAfter    float64 // for DC jumps: DC level over the window after the jump
 }
 
 // An EventType qualifies a dropout event.
@@ -630,6 +1038,8 @@ const (
 	EventDelta   EventType = iota // sudden large jump
 	EventZeroRun                  // run of zeros (digital dropout)
 	EventDCJump                   // sudden DC offset change
+	EventImpulse                  // isolated single-sample spike near full scale (bit error, bad sector)
+	EventStuck                    // run of an identical non-zero value (dead converter, DC flatline)
 )
 
 func (e EventType) String() string {
@@ -640,6 +1050,10 @@ func (e EventType) String() string {
 		return "zero_run"
 	case EventDCJump:
 		return "dc_jump"
+	case EventImpulse:
+		return "impulse"
+	case EventStuck:
+		return "stuck"
 	}
 
 	return "unknown"
@@ -651,6 +1065,34 @@ type DropoutResult struct {
 	DeltaCount   int     // sudden jumps
 	ZeroRunCount int     // zero runs
 	DCJumpCount  int     // DC offset jumps
+	ImpulseCount int     // isolated single-sample spikes
+	StuckCount   int     // runs of an identical non-zero value
 	WorstDb      float64 // severity of worst event in dB
-	Frames       uint64
+
+	// Events recurring on a fixed sample grid (codec frame size): a systematic encoding artifact.
+	PeriodicSamples  uint64  // period in samples; 0 = no periodicity
+	PeriodicFraction float64 // fraction of events on the best grid found (0-1)
+
+	// Events on absolute multiples of a common buffer size (256-4096): a software buffer bug, not media damage.
+	BufferBoundarySamples  uint64  // buffer size in samples; 0 = no alignment
+	BufferBoundaryFraction float64 // fraction of events on its boundaries (0-1)
+
+	Frames uint64
+}
+
+// A RepeatEvent is a stretch of audio sample-exactly equal to the audio just before it.
+type RepeatEvent struct {
+	Frame      uint64  // first frame of the repeated copy
+	TimeSec    float64 // Frame in seconds
+	DurationMs float64 // length of the repeated copy
+	DistanceMs float64 // how far back the original is
+}
+
+// RepeatResult aggregates repeated blocks (drive re-read stutters).
+type RepeatResult struct {
+	Events    []RepeatEvent
+	Count     int
+	LongestMs float64
+
+	Frames uint64
 }
@@ -0,0 +1,98 @@
+package haustorium
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// PCMHeaderSize is the size in bytes of a haustorium-pcm header.
+//
+// A haustorium-pcm stream is raw PCM preceded by a header describing it, so that a producer can pipe
+// PCM and its format in a single stream. Layout (multi-byte fields little-endian):
+//
+//	0-5    magic "HAUPCM"
+//	6      version (1)
+//	7      sample format (signed integer, little-endian: 0 = native, 1 = 24-bit left-justified in 32-bit)
+//	8-11   sample rate (uint32)
+//	12     bit depth (16, 24 or 32)
+//	13     expected bit depth (0 = same as bit depth)
+//	14-15  channels (uint16)
+const PCMHeaderSize = 16
+
+const (
+	pcmHeaderMagic        = "HAUPCM"
+	pcmHeaderVersion      = 1
+	pcmSampleFormatSigned = 0
+	pcmSampleFormat24In32 = 1
+)
+
+// WritePCMHeader writes the haustorium-pcm header for format.
+func WritePCMHeader(writer io.Writer, format types.PCMFormat) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+
+	header := make([]byte, PCMHeaderSize)
+	copy(header, pcmHeaderMagic)
+	header[6] = pcmHeaderVersion
+	header[7] = pcmSampleFormatSigned
+	if format.Layout == types.Layout24In32 {
+		header[7] = pcmSampleFormat24In32
+	}
+
+	binary.LittleEndian.PutUint32(header[8:], uint32(format.SampleRate)) //nolint:gosec // validated positive value
+	header[12] = byte(format.BitDepth)
+	header[13] = byte(format.ExpectedBitDepth)
+	binary.LittleEndian.PutUint16(header[14:], uint16(format.Channels)) //nolint:gosec // channel counts are small
+
+	_, err := writer.Write(header)
+
+	return err
+}
+
+// ParsePCMHeader decodes the haustorium-pcm header at the start of data.
+// ok is false when data does not start with the header magic (plain PCM): the format must then come from elsewhere.
+func ParsePCMHeader(data []byte) (format types.PCMFormat, ok bool, err error) {
+	if len(data) < len(pcmHeaderMagic) || string(data[:len(pcmHeaderMagic)]) != pcmHeaderMagic {
+		return types.PCMFormat{}, false, nil
+	}
+
+	if len(data) < PCMHeaderSize {
+		return types.PCMFormat{}, true, fmt.Errorf("%w: truncated header", ErrInvalidPCMHeader)
+	}
+
+	if data[6] != pcmHeaderVersion {
+		return types.PCMFormat{}, true, fmt.Errorf("%w: version %d", ErrInvalidPCMHeader, data[6])
+	}
+
+	layout := types.LayoutNative
+
+	switch data[7] {
+	case pcmSampleFormatSigned:
+	case pcmSampleFormat24In32:
+		layout = types.Layout24In32
+	default:
+		return types.PCMFormat{}, true, fmt.Errorf("%w: sample format %d", ErrInvalidPCMHeader, data[7])
+	}
+
+	format = types.PCMFormat{
+		SampleRate:       int(binary.LittleEndian.Uint32(data[8:])),
+		BitDepth:         types.BitDepth(data[12]),
+		ExpectedBitDepth: types.BitDepth(data[13]),
+		Channels:         uint(binary.LittleEndian.Uint16(data[14:])),
+		Layout:           layout,
+	}
+
+	if format.ExpectedBitDepth == 0 {
+		format.ExpectedBitDepth = format.BitDepth
+	}
+
+	if err := format.Validate(); err != nil {
+		return types.PCMFormat{}, true, fmt.Errorf("%w: %w", ErrInvalidPCMHeader, err)
+	}
+
+	return format, true, nil
+}
@@ -0,0 +1,115 @@
+package haustorium
+
+import "fmt"
+
+// Profile is a workflow: the checks, bands and thresholds an analysis for that task needs, bundled.
+// Options made from a profile can still be adjusted field by field.
+type Profile int
+
+const (
+	ProfileNone          Profile = iota // No profile: options come from the source type.
+	ProfileArchival                     // Verifying masters and rips for storage. Every check, full coverage.
+	ProfileBroadcast                    // Broadcast delivery. Loudness graded against EBU R128 (-23 LUFS).
+	ProfileStreaming                    // Streaming delivery. Loudness graded against -14 LUFS.
+	ProfileVinylTransfer                // Checking a vinyl transfer. Vinyl tolerances, edges trimmed.
+)
+
+func (p Profile) String() string {
+	switch p {
+	case ProfileNone:
+		return ""
+	case ProfileArchival:
+		return "archival"
+	case ProfileBroadcast:
+		return "broadcast"
+	case ProfileStreaming:
+		return "streaming"
+	case ProfileVinylTransfer:
+		return "vinyl-transfer"
+	}
+
+	return "unknown"
+}
+
+// ParseProfile converts a string to a Profile value.
+func ParseProfile(profile string) (Profile, error) {
+	switch profile {
+	case "":
+		return ProfileNone, nil
+	case "archival":
+		return ProfileArchival, nil
+	case "broadcast":
+		return ProfileBroadcast, nil
+	case "streaming":
+		return ProfileStreaming, nil
+	case "vinyl-transfer":
+		return ProfileVinylTransfer, nil
+	default:
+		return 0, fmt.Errorf("unknown profile %q (valid: archival, broadcast, streaming, vinyl-transfer)", profile)
+	}
+}
+
+// OptionsForProfile returns the Options for the given profile. ProfileNone returns the digital defaults.
+func OptionsForProfile(profile Profile) Options {
+	switch profile {
+	case ProfileArchival:
+		return ArchivalOptions()
+	case ProfileBroadcast:
+		return BroadcastOptions()
+	case ProfileStreaming:
+		return StreamingOptions()
+	case ProfileVinylTransfer:
+		return VinylTransferOptions()
+	default:
+		return DefaultDigitalOptions()
+	}
+}
+
+// ArchivalOptions returns options for verifying files before they are stored for good: every check, the spectral
+// checks on every window, and the bit depth of every section, so that nothing short or partial slips through.
+func ArchivalOptions() Options {
+	opts := DefaultDigitalOptions()
+	opts.Profile = ProfileArchival
+	opts.SpectralThorough = true
+	opts.BitDepthSections = true
+
+	return opts
+}
+
+// BroadcastOptions returns options for broadcast delivery: the defects, and loudness graded against EBU R128
+// (-23 LUFS, -1 dBTP), with its tolerance of 0.5 LU. The mix checks are left out: they are the mastering's call.
+func BroadcastOptions() Options {
+	opts := DefaultDigitalOptions()
+	opts.Profile = ProfileBroadcast
+	opts.Checks = ChecksDefects | ChecksLoudness
+	opts.LoudnessTargetLUFS = -23
+	opts.TruePeakCeilingDb = -1
+	opts.Loudness = Bands{Mild: 0.5, Moderate: 1, Severe: 2}
+
+	return opts
+}
+
+// StreamingOptions returns options for streaming delivery: the defects, and loudness graded against -14 LUFS and
+// -1 dBTP, the reference of the major platforms. Their normalization only turns loud masters down: the default,
+// wider loudness bands apply.
+func StreamingOptions() Options {
+	opts := DefaultDigitalOptions()
+	opts.Profile = ProfileStreaming
+	opts.Checks = ChecksDefects | ChecksLoudness
+	opts.LoudnessTargetLUFS = -14
+	opts.TruePeakCeilingDb = -1
+
+	return opts
+}
+
+// VinylTransferOptions returns options for checking a vinyl transfer: the vinyl tolerances, with the first and
+// last 2 seconds (needle drop, lead-out groove) left out of the defect checks. Vinyl cut safety, about mastering
+// for a cut, does not apply to a transfer.
+func VinylTransferOptions() Options {
+	opts := DefaultVinylOptions()
+	opts.Profile = ProfileVinylTransfer
+	opts.Checks = ChecksAll &^ CheckVinylCutSafety
+	opts.TrimEdgesSec = 2
+
+	return opts
+}
@@ -0,0 +1,158 @@
+package haustorium
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/farcloser/primordium/fault"
+
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// streamBlockSec is the length of the blocks AnalyzeStream reports on as they are read.
+const streamBlockSec = 10
+
+// defaultStreamMaxBytes is the Options.StreamMaxBytes default.
+const defaultStreamMaxBytes = 2 << 30
+
+// streamChecks are the checks judged block by block: local events, found without the rest of the stream.
+const streamChecks = CheckClipping | CheckDropouts
+
+// StreamEvent is one update from AnalyzeStream: a finding, the final result, or a failure. A single channel of
+// events, rather than one of issues, keeps them in order: the final Result and the failure come after the
+// findings, on the same channel.
+type StreamEvent struct {
+	// Issue is a detected issue in one block of the stream, sent as soon as the block is read. It only judges
+	// that block: the final Result judges the whole stream.
+	Issue *Issue
+	// Result is the complete analysis, identical to Analyze's, on the last event.
+	Result *Result
+	// Err is set on the last event when the analysis failed.
+	Err error
+}
+
+// AnalyzeStream analyzes a stream as it is read, for long or live captures. Clipping and dropouts are judged
+// every 10 seconds, and their findings sent right away, positioned in the whole stream. At EOF, every check runs
+// on the whole stream, as Analyze does, for the final Result: checks that need the whole track (truncation and
+// silence padding look at its end, loudness at its integrated level) only report there.
+// Options.TrimEdgesSec only applies to the final Result. The stream is kept in memory for the final analysis, up
+// to Options.StreamMaxBytes: past it, the final event fails with ErrStreamTooLong.
+// The channel is closed after the last event, or without a last event once ctx is canceled.
+func AnalyzeStream(ctx context.Context, reader io.Reader, format types.PCMFormat, opts Options) (
+	<-chan StreamEvent, error,
+) {
+	opts = withDefaultChecks(opts)
+
+	if err := format.Validate(); err != nil {
+		return nil, err
+	}
+
+	events := make(chan StreamEvent)
+
+	go func() {
+		defer close(events)
+
+		send := func(event StreamEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		result, err := streamBlocks(ctx, reader, format, opts, send)
+		if ctx.Err() != nil {
+			return
+		}
+
+		send(StreamEvent{Result: result, Err: err})
+	}()
+
+	return events, nil
+}
+
+// streamBlocks reads the stream block by block, sending the findings of the streamable checks, then analyzes
+// the whole stream.
+func streamBlocks(
+	ctx context.Context,
+	reader io.Reader,
+	format types.PCMFormat,
+	opts Options,
+	send func(StreamEvent) bool,
+) (*Result, error) {
+	frameSize := int(format.BitDepth/8) * int(format.Channels) //nolint:gosec // validated small values
+	block := make([]byte, format.SampleRate*streamBlockSec*frameSize)
+
+	// Blocks are not trimmed: the edges of the stream are only known at EOF. The caller's post-processing is for
+	// the final result only.
+	blockOpts := opts
+	blockOpts.Checks = opts.Checks & streamChecks
+	blockOpts.TrimEdgesSec = 0
+	blockOpts.PostProcess = nil
+
+	maxBytes := opts.StreamMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultStreamMaxBytes
+	}
+
+	var (
+		data    []byte
+		frames  uint64
+		tooLong bool
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		n, err := io.ReadFull(reader, block)
+		n = n / frameSize * frameSize
+
+		if n > 0 && blockOpts.Checks != 0 {
+			chunk := block[:n]
+			blockOpts.StartOffsetFrames = opts.StartOffsetFrames + frames
+
+			partial, analyzeErr := Analyze(func() (io.Reader, error) { return bytes.NewReader(chunk), nil }, format,
+				blockOpts)
+			if analyzeErr != nil {
+				return nil, analyzeErr
+			}
+
+			for i := range partial.Issues {
+				if partial.Issues[i].Detected && !send(StreamEvent{Issue: &partial.Issues[i]}) {
+					return nil, ctx.Err()
+				}
+			}
+		}
+
+		// Past the cap, the blocks are still judged, but no longer kept.
+		if int64(len(data)+n) > maxBytes {
+			tooLong, data = true, nil
+		}
+
+		if !tooLong {
+			data = append(data, block[:n]...)
+		}
+
+		frames += uint64(n / frameSize) //nolint:gosec // non-negative
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", fault.ErrReadFailure, err)
+		}
+	}
+
+	if tooLong {
+		return nil, fmt.Errorf("%w: over %d bytes", ErrStreamTooLong, maxBytes)
+	}
+
+	return AnalyzeContext(ctx, func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+}
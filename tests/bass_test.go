@@ -0,0 +1,52 @@
+package tests_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// bassTrack returns a dense comb of low tones (3 Hz apart, random phases: a flat low end) from lowHz to 250 Hz,
+// over a 1 kHz tone and a faint noise floor. A high lowHz is a brick-wall high-pass.
+func bassTrack(lowHz float64) func(frame, channel int) float64 {
+	rng := rand.New(rand.NewPCG(7, 8))
+
+	var freqs, phases []float64
+
+	for freq := lowHz; freq <= 250; freq += 3 {
+		freqs = append(freqs, freq)
+		phases = append(phases, 2*math.Pi*rng.Float64())
+	}
+
+	return func(frame, _ int) float64 {
+		at := float64(frame) / 44100
+		value := 0.2*math.Sin(2*math.Pi*1000*at) + 0.0005*(2*rng.Float64()-1)
+
+		for i, freq := range freqs {
+			value += 0.01 * math.Sin(2*math.Pi*freq*at+phases[i])
+		}
+
+		return value
+	}
+}
+
+func TestBassRolloffFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	cut := findIssue(t, analyzeSynthesized(t, haustorium.CheckBassRolloff, format, 5, bassTrack(100)),
+		haustorium.CheckBassRolloff)
+	if !cut.Detected {
+		t.Fatalf("expected a bass rolloff at 100 Hz, got: %s", cut.Summary)
+	}
+
+	full := findIssue(t, analyzeSynthesized(t, haustorium.CheckBassRolloff, format, 5, bassTrack(15)),
+		haustorium.CheckBassRolloff)
+	if full.Detected {
+		t.Fatalf("expected no bass rolloff with bass down to 15 Hz, got: %s", full.Summary)
+	}
+}
@@ -1,6 +1,11 @@
 package tests_test
 
 import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand/v2"
+	"strings"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +13,9 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -49,3 +57,100 @@ func TestFakeBitDepth(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// fadeTrack returns 2 s of a loud 200 Hz tone, its tail 0.8 s of it 6 LSBs high at 16-bit, then 0.5 s of silence:
+// the tail reduced to 16-bit plainly, a staircase, or over TPDF dither.
+func fadeTrack(dithered bool) func(frame, channel int) float64 {
+	rng := rand.New(rand.NewPCG(19, 20))
+
+	return func(frame, _ int) float64 {
+		at := float64(frame) / 44100
+		tone := math.Sin(2 * math.Pi * 200 * at)
+
+		switch {
+		case at < 2:
+			return 0.3 * tone
+		case at >= 2.8:
+			return 0
+		case dithered:
+			return (6*tone + rng.Float64() - rng.Float64()) / math.MaxInt16
+		default:
+			return 6 * tone / math.MaxInt16
+		}
+	}
+}
+
+func TestFadeoutDitherFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	plain := analyzeSynthesized(t, haustorium.CheckUndithered, format, 3.3, fadeTrack(false))
+	if issue := findIssue(t, plain, haustorium.CheckUndithered); !issue.Detected ||
+		!strings.HasPrefix(issue.Summary, "Undithered fade-out: quantization tracks the signal over the last 0.8s") {
+		t.Fatalf("expected the undithered fade-out, got: %s", issue.Summary)
+	}
+
+	// Under a second of quiet material: the whole-track ratio cannot tell.
+	if plain.Dither.UnditheredLikely {
+		t.Fatalf("expected the fade-out alone to be judged, got a hold ratio of %.2f", plain.Dither.HoldRatio)
+	}
+
+	dithered := findIssue(t, analyzeSynthesized(t, haustorium.CheckUndithered, format, 3.3, fadeTrack(true)),
+		haustorium.CheckUndithered)
+	if dithered.Detected || dithered.Summary != "Quiet passages properly dithered" {
+		t.Fatalf("expected a dithered fade-out, got: %s", dithered.Summary)
+	}
+}
+
+// TestLayout24In32 tells the 24-in-32 layout from an expected depth of 24 on the same 32-bit tone: the expected
+// depth judges all 32 bits, the layout only the 24 above the padding byte, and warns that the padding holds data.
+// Once the padding is cleared, as a 24-bit source extracted to s32 has it, the layout does not warn.
+func TestLayout24In32(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth32, Channels: 2, ExpectedBitDepth: types.Depth24}
+	layout := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth32, Channels: 2, Layout: types.Layout24In32}
+
+	data := testutil.Synthesize(format, 1, func(frame, _ int) float64 {
+		return 0.5 * math.Sin(2*math.Pi*440*float64(frame)/44100)
+	})
+
+	analyze := func(format types.PCMFormat, data []byte) *haustorium.Result {
+		opts := haustorium.DefaultDigitalOptions()
+		opts.Checks = haustorium.CheckFakeBitDepth
+
+		result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		return result
+	}
+
+	if expected := analyze(format, data); expected.BitDepth.Effective != types.Depth32 || len(expected.Warnings) != 0 {
+		t.Fatalf("expected the expected depth to see 32 bits, got %d bits, warnings %v", expected.BitDepth.Effective,
+			expected.Warnings)
+	}
+
+	packed := analyze(layout, data)
+	if packed.BitDepth.Claimed != types.Depth24 || packed.BitDepth.Effective != types.Depth24 {
+		t.Fatalf("expected the layout to read 24 bits, got %d claimed, %d effective", packed.BitDepth.Claimed,
+			packed.BitDepth.Effective)
+	}
+
+	if len(packed.Warnings) != 1 ||
+		!strings.HasPrefix(packed.Warnings[0], "24-in-32 layout: the padding byte holds data") {
+		t.Fatalf("expected a warning on the padding in use, got %v", packed.Warnings)
+	}
+
+	padded := bytes.Clone(data)
+	for i := 0; i < len(padded); i += 4 {
+		padded[i] = 0
+	}
+
+	if clean := analyze(layout, padded); clean.BitDepth.Effective != types.Depth24 || len(clean.Warnings) != 0 {
+		t.Fatalf("expected genuine 24-in-32 samples, got %d bits, warnings %v", clean.BitDepth.Effective,
+			clean.Warnings)
+	}
+}
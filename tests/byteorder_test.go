@@ -0,0 +1,52 @@
+package tests_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+// TestByteOrderFixture runs the analysis on synthetic 16-bit PCM, as is and with the two bytes of every sample
+// swapped.
+func TestByteOrderFixture(t *testing.T) {
+	t.Parallel()
+
+	suspected := func(t *testing.T, signal testutil.Signal, swap bool) bool {
+		t.Helper()
+
+		spec := testutil.Spec{Signal: signal}
+		_, format := testutil.Fixture(spec)
+
+		data := testutil.Render(spec)
+		if swap {
+			for i := 0; i+1 < len(data); i += 2 {
+				data[i], data[i+1] = data[i+1], data[i]
+			}
+		}
+
+		opts := haustorium.DefaultDigitalOptions()
+		opts.Checks = haustorium.CheckClipping
+
+		result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		return result.ByteSwapSuspected
+	}
+
+	if !suspected(t, testutil.SignalSine, true) {
+		t.Fatal("expected a byte-swapped sine to be suspected")
+	}
+
+	if suspected(t, testutil.SignalSine, false) {
+		t.Fatal("expected a sine in its own byte order not to be suspected")
+	}
+
+	if suspected(t, testutil.SignalNoise, true) {
+		t.Fatal("expected noise, uncorrelated both ways, not to be suspected")
+	}
+}
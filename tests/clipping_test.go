@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -64,3 +67,26 @@ func TestClipping(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// TestClippingFixture runs the analysis on synthetic PCM clipped on both sides, but for part of it only, which
+// ffmpeg's synthesis sources cannot produce.
+func TestClippingFixture(t *testing.T) {
+	t.Parallel()
+
+	issue := analyzeFixture(t, haustorium.CheckClipping, testutil.Spec{
+		Defects: []testutil.Defect{{Kind: testutil.DefectClip, AtSec: 1, Channel: testutil.AllChannels}},
+	})
+
+	if !issue.Detected {
+		t.Fatal("expected clipping to be detected")
+	}
+
+	if strings.Contains(issue.Summary, "one-sided") {
+		t.Fatalf("expected clipping on both sides, got: %s", issue.Summary)
+	}
+
+	// The fixture is a 1 kHz sine: the clipping concentrates there.
+	if !strings.Contains(issue.Summary, "concentrated in the mids") {
+		t.Fatalf("expected the clipping in the mids, got: %s", issue.Summary)
+	}
+}
@@ -0,0 +1,54 @@
+package tests_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+// TestMinConfidence puts a dropout (detected at 90% confidence) and a DC offset (100%) in one track: a 95% floor
+// turns the dropout alone into a non-detection, and the summary counts the offset alone.
+func TestMinConfidence(t *testing.T) {
+	t.Parallel()
+
+	factory, format := testutil.Fixture(testutil.Spec{Defects: []testutil.Defect{
+		{Kind: testutil.DefectDCJump, AtSec: 0, Channel: testutil.AllChannels},
+		{Kind: testutil.DefectZeroRun, AtSec: 1},
+	}})
+
+	analyze := func(floor float64) *haustorium.Result {
+		opts := haustorium.DefaultDigitalOptions()
+		opts.Checks = haustorium.CheckDropouts | haustorium.CheckDCOffset
+		opts.MinConfidence = floor
+
+		result, err := haustorium.Analyze(factory, format, opts)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		return result
+	}
+
+	// At the floor is not below it.
+	if atFloor := analyze(0.9); !atFloor.HasDropouts || atFloor.IssueCount != 2 {
+		t.Fatalf("expected both detections at a 90%% floor, got %d issues", atFloor.IssueCount)
+	}
+
+	result := analyze(0.95)
+
+	dropouts := findIssue(t, result, haustorium.CheckDropouts)
+	if dropouts.Detected || dropouts.Severity != haustorium.SeverityNone || result.HasDropouts ||
+		!strings.Contains(dropouts.Summary, "below confidence floor: 90% < 95%") {
+		t.Fatalf("expected the dropout below the floor to be not detected, got: %+v", dropouts)
+	}
+
+	if offset := findIssue(t, result, haustorium.CheckDCOffset); !offset.Detected || !result.HasDCOffset {
+		t.Fatalf("expected the DC offset above the floor to stay detected, got: %s", offset.Summary)
+	}
+
+	if result.IssueCount != 1 || result.WorstSeverity == haustorium.SeverityNone {
+		t.Fatalf("expected the summary to count the DC offset alone, got %d issues", result.IssueCount)
+	}
+}
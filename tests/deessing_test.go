@@ -0,0 +1,60 @@
+package tests_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// sibilantTrack returns a quiet noise bed with a burst every 8192 frames (~186 ms): white noise for a natural
+// sibilant, or air band (10-13 kHz) tones alone for one behind a de-esser, the 5-9 kHz band held down.
+func sibilantTrack(ducked bool) func(frame, channel int) float64 {
+	const (
+		period = 8192
+		burst  = 1536
+	)
+
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	return func(frame, _ int) float64 {
+		value := 0.02 * (2*rng.Float64() - 1)
+		if frame%period >= burst {
+			return value
+		}
+
+		if !ducked {
+			return value + 0.4*(2*rng.Float64()-1)
+		}
+
+		for _, freq := range []float64{10000, 11000, 12000, 13000} {
+			value += 0.1 * math.Sin(2*math.Pi*freq*float64(frame)/44100)
+		}
+
+		return value
+	}
+}
+
+func TestDeEssingFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	ducked := findIssue(t, analyzeSynthesized(t, haustorium.CheckDeEssing, format, 10, sibilantTrack(true)),
+		haustorium.CheckDeEssing)
+	if !ducked.Detected {
+		t.Fatalf("expected de-essing on sibilants missing their 5-9 kHz band, got: %s", ducked.Summary)
+	}
+
+	natural := findIssue(t, analyzeSynthesized(t, haustorium.CheckDeEssing, format, 10, sibilantTrack(false)),
+		haustorium.CheckDeEssing)
+	if natural.Detected {
+		t.Fatalf("expected no de-essing on broadband sibilants, got: %s", natural.Summary)
+	}
+
+	if natural.Summary != "No de-esser pumping" {
+		t.Fatalf("expected the sibilants to be assessed, got: %s", natural.Summary)
+	}
+}
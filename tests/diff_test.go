@@ -0,0 +1,119 @@
+package tests_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/farcloser/agar/pkg/agar"
+
+	"github.com/farcloser/haustorium/tests/testutils"
+)
+
+// diffColumn is a side-by-side record of diff --format json.
+type diffColumn struct {
+	A any `json:"a"`
+	B any `json:"b"`
+}
+
+func TestDiffCLI(t *testing.T) {
+	testCase := testutils.Setup()
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "diff takes exactly two files",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("file", agar.Genuine16bit44k(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("diff", data.Labels().Get("file"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, nil, nil),
+		},
+		{
+			Description: "diff lays a clean and a clipped version side by side",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("clean", agar.Genuine16bit44k(data, helpers))
+				data.Labels().Set("clipped", agar.ClippedHard(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("diff", "--checks", "clipping",
+					data.Labels().Get("clean"), data.Labels().Get("clipped"))
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: expect.All(
+						expectContains(data.Labels().Get("clean")+" vs "+data.Labels().Get("clipped")),
+						expectContains("clipping: [no issue] No clipping detected  |  ["),
+						expectContains("worst_severity: no issue  |  "),
+						expect.DoesNotContain("worst_severity: no issue  |  no issue"),
+					),
+				}
+			},
+		},
+		{
+			Description: "diff --format json reports both sides of what differs, and only the differing metrics",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("clean", agar.Genuine16bit44k(data, helpers))
+				data.Labels().Set("clipped", agar.ClippedHard(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command("diff", "--checks", "clipping", "--format", "json",
+					data.Labels().Get("clean"), data.Labels().Get("clipped"))
+			},
+			Expected: func(_ test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(stdout string, testing tig.T) {
+						testing.Helper()
+
+						var results []struct {
+							Meta struct {
+								Summary map[string]diffColumn `json:"summary"`
+								Issues  map[string]diffColumn `json:"issues"`
+								Metrics map[string]diffColumn `json:"metrics"`
+							} `json:"meta"`
+						}
+
+						if err := json.Unmarshal([]byte(stdout), &results); err != nil || len(results) != 1 {
+							testing.Log(fmt.Sprintf("expected one JSON result (%v):\n%s", err, stdout))
+							testing.FailNow()
+						}
+
+						meta := results[0].Meta
+
+						severity := meta.Summary["worst_severity"]
+						if severity.A != "no issue" || severity.B == "no issue" {
+							testing.Log(fmt.Sprintf("expected the clipped side alone to have issues, got %v", severity))
+							testing.Fail()
+						}
+
+						if _, ok := meta.Issues["clipping"]; !ok {
+							testing.Log(fmt.Sprintf("expected both clipping verdicts, got %v", meta.Issues))
+							testing.Fail()
+						}
+
+						if len(meta.Metrics) == 0 {
+							testing.Log("expected the clipping metrics to differ")
+							testing.Fail()
+						}
+
+						for key, metric := range meta.Metrics {
+							if metric.A == metric.B {
+								testing.Log(fmt.Sprintf("expected only differing metrics, got %s: %v", key, metric))
+								testing.Fail()
+							}
+						}
+					},
+				}
+			},
+		},
+	}
+
+	testCase.Run(t)
+}
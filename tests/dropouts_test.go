@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -35,17 +38,74 @@ func TestDropouts(t *testing.T) {
 	testCase.Run(t)
 }
 
-// TestDropoutsPositive is a placeholder for dropout positive-detection tests.
-//
-// Dropout detection looks for zero-sample runs (>= 1ms) and sample-level
-// discontinuities (delta jumps where one side is near zero). Generating audio
-// with actual glitches using ffmpeg is not possible: ffmpeg's lavfi synthesis
-// sources produce clean, continuous waveforms with no sample-level artifacts.
-//
-// A positive test requires either a pre-recorded file with known dropout events
-// or programmatic construction of raw PCM with injected zero runs and delta
-// discontinuities. This needs a dedicated agar fixture that writes raw samples
-// directly rather than using ffmpeg.
+// TestDropoutsPositive runs the analysis on synthetic PCM with injected glitches: ffmpeg's lavfi synthesis sources
+// produce clean, continuous waveforms, with no sample-level artifacts.
 func TestDropoutsPositive(t *testing.T) {
-	t.Skip("blocked: no agar fixture can generate audio with dropout glitches using ffmpeg")
+	t.Parallel()
+
+	for _, defect := range []testutil.Defect{
+		{Kind: testutil.DefectZeroRun, AtSec: 1},
+		{Kind: testutil.DefectDiscontinuity, AtSec: 1},
+		{Kind: testutil.DefectClick, AtSec: 1},
+		{Kind: testutil.DefectStuck, AtSec: 1},
+	} {
+		t.Run(defect.Kind.String(), func(t *testing.T) {
+			t.Parallel()
+
+			issue := analyzeFixture(t, haustorium.CheckDropouts, testutil.Spec{Defects: []testutil.Defect{defect}})
+			if !issue.Detected {
+				t.Fatalf("expected dropouts to be detected for a %s", defect.Kind)
+			}
+		})
+	}
+
+	t.Run("clean", func(t *testing.T) {
+		t.Parallel()
+
+		if issue := analyzeFixture(t, haustorium.CheckDropouts, testutil.Spec{}); issue.Detected {
+			t.Fatalf("expected no dropouts, got: %s", issue.Summary)
+		}
+	})
+}
+
+// clickTrain returns clicks at the given frames of the fixture sine, one per frame.
+func clickTrain(frames []int) []testutil.Defect {
+	defects := make([]testutil.Defect, 0, len(frames))
+
+	for _, frame := range frames {
+		defects = append(defects, testutil.Defect{
+			Kind:    testutil.DefectClick,
+			AtSec:   (float64(frame) + 0.5) / 44100,
+			Channel: testutil.AllChannels,
+		})
+	}
+
+	return defects
+}
+
+// TestDropoutsPeriodic injects clicks on a fixed 1000-sample grid, off any buffer boundary: a systematic codec
+// artifact, unlike clicks at scattered times.
+func TestDropoutsPeriodic(t *testing.T) {
+	t.Parallel()
+
+	var grid, scattered []int
+
+	for i := range 20 {
+		grid = append(grid, 333+1000*i)
+		scattered = append(scattered, 333+1000*i+(i*i*37)%700)
+	}
+
+	issue := analyzeFixture(t, haustorium.CheckDropouts, testutil.Spec{Defects: clickTrain(grid)})
+	if !issue.Detected || !strings.Contains(issue.Summary, "recurring every 1000 samples") {
+		t.Fatalf("expected clicks recurring every 1000 samples, got: %s", issue.Summary)
+	}
+
+	if strings.Contains(issue.Summary, "buffer-boundary") {
+		t.Fatalf("expected the grid off buffer boundaries, got: %s", issue.Summary)
+	}
+
+	issue = analyzeFixture(t, haustorium.CheckDropouts, testutil.Spec{Defects: clickTrain(scattered)})
+	if !issue.Detected || strings.Contains(issue.Summary, "recurring") {
+		t.Fatalf("expected scattered clicks without a period, got: %s", issue.Summary)
+	}
 }
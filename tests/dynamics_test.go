@@ -1,6 +1,11 @@
 package tests_test
 
 import (
+	"bytes"
+	"io"
+	"math"
+	"math/rand/v2"
+	"strings"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +13,9 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -79,3 +87,77 @@ func TestDynamicRange(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// hitTrack returns a hit every 250 ms over silence: a 100 Hz square at the ceiling for 60 ms when limited,
+// otherwise a sharp 3 kHz strike decaying in 1 ms, over a quiet 200 Hz body.
+func hitTrack(limited bool) func(frame, channel int) float64 {
+	const period = 44100 / 4
+
+	return func(frame, _ int) float64 {
+		at := float64(frame%period) / 44100
+		if at >= 0.06 {
+			return 0
+		}
+
+		if limited {
+			return math.Copysign(0.9, math.Sin(2*math.Pi*100*at))
+		}
+
+		return 0.9*math.Exp(-at/0.001)*math.Sin(2*math.Pi*3000*at+math.Pi/2) + 0.05*math.Sin(2*math.Pi*200*at)
+	}
+}
+
+func TestTransientFlatteningFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	limited := analyzeSynthesized(t, haustorium.CheckDynamicRange, format, 10, hitTrack(true)).Loudness
+	if limited.Transients < 20 || limited.TransientFlatteningIndex < 0.9 {
+		t.Fatalf("expected squared-off attacks to be flattened, got %.2f of %d transients",
+			limited.TransientFlatteningIndex, limited.Transients)
+	}
+
+	natural := analyzeSynthesized(t, haustorium.CheckDynamicRange, format, 10, hitTrack(false)).Loudness
+	if natural.Transients < 20 || natural.TransientFlatteningIndex > 0.1 {
+		t.Fatalf("expected sharp attacks not to be flattened, got %.2f of %d transients",
+			natural.TransientFlatteningIndex, natural.Transients)
+	}
+}
+
+// TestDynamicRangeGenre grades one DR7 track against the genres: brickwalled for classical, compressed for rock,
+// fine for pop.
+func TestDynamicRangeGenre(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	// The mean of 4 uniform draws: a peak to RMS ratio of DR7.
+	rng := rand.New(rand.NewPCG(1, 2))
+	data := testutil.Synthesize(format, 10, func(_, _ int) float64 {
+		return 0.05 * (4 - 2*(rng.Float64()+rng.Float64()+rng.Float64()+rng.Float64()))
+	})
+
+	for genre, severity := range map[haustorium.Genre]haustorium.Severity{
+		haustorium.GenreClassical: haustorium.SeveritySevere,
+		haustorium.GenreRock:      haustorium.SeverityMild,
+		haustorium.GenrePop:       haustorium.SeverityNone,
+	} {
+		opts := haustorium.DefaultDigitalOptions()
+		opts.Checks = haustorium.CheckDynamicRange
+		opts.Genre = genre
+
+		result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format,
+			opts)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		issue := findIssue(t, result, haustorium.CheckDynamicRange)
+		if result.Loudness.DRScore != 7 || issue.Severity != severity ||
+			!strings.HasSuffix(issue.Summary, " for "+genre.String()) {
+			t.Errorf("%s: expected DR7 graded %s, got DR%d: [%s] %s", genre, severity, result.Loudness.DRScore,
+				issue.Severity, issue.Summary)
+		}
+	}
+}
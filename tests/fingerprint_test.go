@@ -0,0 +1,72 @@
+package tests_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/fingerprint"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// music renders 12 seconds of 16-bit stereo at the sample rate: tones across the fingerprint's bands, each
+// swelling at its own pace, so that the band energies move over time as music does. seed changes the paces.
+func music(sampleRate int, seed float64) ([]byte, types.PCMFormat) {
+	format := types.PCMFormat{SampleRate: sampleRate, BitDepth: types.Depth16, Channels: 2}
+	tones := []float64{320, 390, 470, 560, 680, 820, 990, 1200, 1450, 1750}
+
+	var buf bytes.Buffer
+
+	for frame := range 12 * sampleRate {
+		t := float64(frame) / float64(sampleRate)
+
+		var value float64
+
+		for idx, hz := range tones {
+			pace := (0.3 + 0.17*float64(idx)) * (1 + 0.61*seed)
+			swell := 0.5 + 0.5*math.Sin(2*math.Pi*pace*t+float64(idx))
+			value += swell * math.Sin(2*math.Pi*hz*t) / float64(len(tones))
+		}
+
+		sample := int16(value * 0.5 * math.MaxInt16)
+		_ = binary.Write(&buf, binary.LittleEndian, [2]int16{sample, sample})
+	}
+
+	return buf.Bytes(), format
+}
+
+// musicFingerprint fingerprints music at the sample rate.
+func musicFingerprint(t *testing.T, sampleRate int, seed float64) *fingerprint.Fingerprint {
+	t.Helper()
+
+	data, format := music(sampleRate, seed)
+
+	print, err := fingerprint.Compute(bytes.NewReader(data), format)
+	if err != nil {
+		t.Fatalf("fingerprinting failed: %v", err)
+	}
+
+	return print
+}
+
+// TestFingerprintAcrossRates fingerprints the same signal at 44.1 and 48 kHz: the same recording in two formats.
+func TestFingerprintAcrossRates(t *testing.T) {
+	t.Parallel()
+
+	cd := musicFingerprint(t, 44100, 0)
+	hires := musicFingerprint(t, 48000, 0)
+	other := musicFingerprint(t, 48000, 1)
+
+	if cd.PCMHash == hires.PCMHash {
+		t.Fatal("expected different samples to hash differently")
+	}
+
+	if ber := fingerprint.BitErrorRate(cd.Acoustic, hires.Acoustic); ber >= fingerprint.MatchBitErrorRate {
+		t.Fatalf("expected the two rates to match, bit error rate %.3f", ber)
+	}
+
+	if ber := fingerprint.BitErrorRate(cd.Acoustic, other.Acoustic); ber < fingerprint.MatchBitErrorRate {
+		t.Fatalf("expected different material not to match, bit error rate %.3f", ber)
+	}
+}
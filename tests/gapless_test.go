@@ -0,0 +1,53 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+)
+
+// mp3Stream returns the probe of 10 s of 44.1 kHz MP3 (441000 samples), in frames of 1152 samples, with the
+// given encoder delay.
+func mp3Stream(delay int, frames string) ffprobe.Stream {
+	return ffprobe.Stream{
+		BaseStream:     ffprobe.BaseStream{CodecName: "mp3", SampleRate: "44100", Channels: 2},
+		TimeBase:       "1/44100",
+		DurationTS:     441000,
+		InitialPadding: delay,
+		NbFrames:       frames,
+	}
+}
+
+func TestGaplessMetadata(t *testing.T) {
+	t.Parallel()
+
+	// LAME's 1105 samples of priming, and the 263 samples that fill the last of 384 frames.
+	gapless := probeRecord(t, mp3Stream(1105, "384"))
+	if gapless.GaplessMetadataOK == nil || !*gapless.GaplessMetadataOK || *gapless.EncoderDelay != 1105 ||
+		gapless.EncoderPadding == nil || *gapless.EncoderPadding != 263 {
+		t.Fatalf("expected 1105 samples of delay and 263 of padding, gapless, got: %+v", gapless)
+	}
+
+	for _, tc := range []struct {
+		delay  int
+		frames string
+		detail string
+	}{
+		{0, "384", "mp3: no encoder delay: the priming samples will play as a gap"},
+		{1105, "387", "mp3: 3719 samples of padding, over 2 frames: they will play as a gap"},
+	} {
+		record := probeRecord(t, mp3Stream(tc.delay, tc.frames))
+		if record.GaplessMetadataOK == nil || *record.GaplessMetadataOK || record.GaplessDetail != tc.detail {
+			t.Fatalf("expected %q, got: %+v", tc.detail, record)
+		}
+	}
+
+	// Lossless streams have no priming.
+	if lossless := probeRecord(t, ffprobe.Stream{BaseStream: ffprobe.BaseStream{
+		CodecName:  "flac",
+		SampleRate: "44100",
+		Channels:   2,
+	}}); lossless.GaplessMetadataOK != nil || lossless.EncoderDelay != nil {
+		t.Fatalf("expected no gapless metadata for FLAC, got: %+v", lossless)
+	}
+}
@@ -1,13 +1,130 @@
 package tests_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"path/filepath"
 	"strings"
+	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/test"
 	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/report"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
 )
 
+// analyzeFixture runs the given check on a synthetic fixture, through the library rather than the binary, and
+// returns its issue.
+func analyzeFixture(t *testing.T, check haustorium.Check, spec testutil.Spec) haustorium.Issue {
+	t.Helper()
+
+	factory, format := testutil.Fixture(spec)
+	opts := haustorium.DefaultDigitalOptions()
+	opts.Checks = check
+
+	result, err := haustorium.Analyze(factory, format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	for _, issue := range result.Issues {
+		if issue.Check == check {
+			return issue
+		}
+	}
+
+	t.Fatalf("no %s issue in the result", check)
+
+	return haustorium.Issue{}
+}
+
+// analyzeRendered runs the given checks on a synthetic fixture whose PCM is first altered in place (nil: as is),
+// for defects that are not one of the fixture's, and returns the result.
+func analyzeRendered(
+	t *testing.T,
+	checks haustorium.Check,
+	spec testutil.Spec,
+	alter func(data []byte),
+) *haustorium.Result {
+	t.Helper()
+
+	_, format := testutil.Fixture(spec)
+
+	data := testutil.Render(spec)
+	if alter != nil {
+		alter(data)
+	}
+
+	opts := haustorium.DefaultDigitalOptions()
+	opts.Checks = checks
+
+	result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	return result
+}
+
+// analyzeSynthesized runs the given checks on PCM synthesized by value (see testutil.Synthesize), and returns the
+// result.
+func analyzeSynthesized(
+	t *testing.T,
+	checks haustorium.Check,
+	format types.PCMFormat,
+	seconds float64,
+	value func(frame, channel int) float64,
+) *haustorium.Result {
+	t.Helper()
+
+	if format.ExpectedBitDepth == 0 {
+		format.ExpectedBitDepth = format.BitDepth
+	}
+
+	data := testutil.Synthesize(format, seconds, value)
+	opts := haustorium.DefaultDigitalOptions()
+	opts.Checks = checks
+
+	result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	return result
+}
+
+// findIssue returns the issue of the given check in the result.
+func findIssue(t *testing.T, result *haustorium.Result, check haustorium.Check) haustorium.Issue {
+	t.Helper()
+
+	for _, issue := range result.Issues {
+		if issue.Check == check {
+			return issue
+		}
+	}
+
+	t.Fatalf("no %s issue in the result", check)
+
+	return haustorium.Issue{}
+}
+
+// probeRecord returns the report record of a file probed as the given audio stream, which cannot be decoded: the
+// probe-based policies alone.
+func probeRecord(t *testing.T, stream ffprobe.Stream) report.Record {
+	t.Helper()
+
+	stream.CodecType = "audio"
+
+	return report.Process(context.Background(), filepath.Join(t.TempDir(), "missing"),
+		&ffprobe.Result{Streams: []ffprobe.Stream{stream}}, &report.Options{})
+}
+
 // expectIssue returns a comparator verifying that the given check was detected with the given severity.
 // It looks for an issue block containing: check: <check>, detected: true, severity: <severity>.
 func expectIssue(check, severity string) test.Comparator {
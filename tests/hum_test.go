@@ -1,6 +1,8 @@
 package tests_test
 
 import (
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +10,8 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -49,3 +53,34 @@ func TestHum(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// whineTrack returns a noise bed with a 15734 Hz line, the flyback whine of a CRT nearby, or with a melody of
+// 2-3 kHz notes changing every half second.
+func whineTrack(constant bool) func(frame, channel int) float64 {
+	rng := rand.New(rand.NewPCG(13, 14))
+
+	return func(frame, _ int) float64 {
+		freq := 15734.0
+		if !constant {
+			freq = 2000 + 250*float64(frame/22050%5)
+		}
+
+		return 0.1*(2*rng.Float64()-1) + 0.05*math.Sin(2*math.Pi*freq*float64(frame)/44100)
+	}
+}
+
+func TestConstantTonesFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	whine := analyzeSynthesized(t, haustorium.CheckHum, format, 10, whineTrack(true)).Spectral
+	if len(whine.ConstantTones) != 1 || math.Abs(whine.ConstantTones[0].Hz-15734) > 44100.0/8192 {
+		t.Fatalf("expected one constant tone at 15734 Hz, got: %+v", whine.ConstantTones)
+	}
+
+	melody := analyzeSynthesized(t, haustorium.CheckHum, format, 10, whineTrack(false)).Spectral
+	if len(melody.ConstantTones) != 0 {
+		t.Fatalf("expected no constant tone in changing notes, got: %+v", melody.ConstantTones)
+	}
+}
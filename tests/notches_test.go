@@ -0,0 +1,63 @@
+package tests_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// notchedNoise returns seconds of white noise at 44.1 kHz, 30 dB down over 100 Hz around each of the notches.
+func notchedNoise(seconds float64, notches ...float64) []float64 {
+	frames := int(seconds * 44100)
+	rng := rand.New(rand.NewPCG(17, 18))
+
+	noise := make([]float64, frames)
+	for i := range noise {
+		noise[i] = 0.25 * (2*rng.Float64() - 1)
+	}
+
+	fft := fourier.NewFFT(frames)
+	coeffs := fft.Coefficients(nil, noise)
+
+	for i := range coeffs {
+		for _, notch := range notches {
+			if math.Abs(fft.Freq(i)*44100-notch) <= 50 {
+				coeffs[i] *= complex(math.Pow(10, -30.0/20), 0)
+			}
+		}
+	}
+
+	values := fft.Sequence(nil, coeffs)
+	for i := range values {
+		values[i] /= float64(frames)
+	}
+
+	return values
+}
+
+func TestNotchesFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	notched := notchedNoise(5, 3000)
+	found := analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 5, func(frame, _ int) float64 {
+		return notched[frame]
+	}).Spectral.Notches
+
+	if len(found) != 1 || math.Abs(found[0].Hz-3000) > 50 || found[0].DepthDb < 20 {
+		t.Fatalf("expected one deep notch at 3 kHz, got: %+v", found)
+	}
+
+	plain := notchedNoise(5)
+	if found = analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 5, func(frame, _ int) float64 {
+		return plain[frame]
+	}).Spectral.Notches; len(found) != 0 {
+		t.Fatalf("expected no notch in white noise, got: %+v", found)
+	}
+}
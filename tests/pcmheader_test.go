@@ -0,0 +1,65 @@
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+func TestPCMHeader(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{
+		SampleRate:       96000,
+		BitDepth:         types.Depth32,
+		Channels:         6,
+		ExpectedBitDepth: types.Depth24,
+		Layout:           types.Layout24In32,
+	}
+
+	var buf bytes.Buffer
+	if err := haustorium.WritePCMHeader(&buf, format); err != nil {
+		t.Fatalf("writing header: %v", err)
+	}
+
+	if buf.Len() != haustorium.PCMHeaderSize {
+		t.Fatalf("expected a %d-byte header, got %d bytes", haustorium.PCMHeaderSize, buf.Len())
+	}
+
+	parsed, ok, err := haustorium.ParsePCMHeader(buf.Bytes())
+	if err != nil || !ok {
+		t.Fatalf("expected the header to parse, got ok=%v, err=%v", ok, err)
+	}
+
+	if parsed != format {
+		t.Fatalf("expected %+v back, got %+v", format, parsed)
+	}
+
+	// Plain PCM has no header: the format comes from elsewhere.
+	if _, ok, err := haustorium.ParsePCMHeader(testutil.Render(testutil.Spec{})); ok || err != nil {
+		t.Fatalf("expected plain PCM not to carry a header, got ok=%v, err=%v", ok, err)
+	}
+
+	header := buf.Bytes()
+
+	for name, data := range map[string][]byte{
+		"truncated":     header[:10],
+		"version":       append([]byte("HAUPCM\x02"), header[7:]...),
+		"sample format": append([]byte("HAUPCM\x01\x07"), header[8:]...),
+		"bit depth":     append(append([]byte{}, header[:12]...), 20, 0, 6, 0),
+	} {
+		if _, ok, err := haustorium.ParsePCMHeader(data); !ok || !errors.Is(err, haustorium.ErrInvalidPCMHeader) {
+			t.Fatalf("%s: expected an invalid header, got ok=%v, err=%v", name, ok, err)
+		}
+	}
+
+	// An expected bit depth of 0 stands for the bit depth.
+	header[13] = 0
+	if parsed, _, _ := haustorium.ParsePCMHeader(header); parsed.ExpectedBitDepth != types.Depth32 {
+		t.Fatalf("expected the bit depth as expected bit depth, got %d", parsed.ExpectedBitDepth)
+	}
+}
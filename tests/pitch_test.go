@@ -0,0 +1,66 @@
+package tests_test
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// chordTrack returns an A major chord over three octaves (A, C#, E from A3), its tuning offset by cents from A=440.
+func chordTrack(cents float64) func(frame, channel int) float64 {
+	var freqs []float64
+
+	for _, semitones := range []float64{0, 4, 7, 12, 16, 19, 24, 28, 31} {
+		freqs = append(freqs, 220*math.Pow(2, (semitones*100+cents)/1200))
+	}
+
+	return func(frame, _ int) float64 {
+		var value float64
+		for _, freq := range freqs {
+			value += 0.08 * math.Sin(2*math.Pi*freq*float64(frame)/44100)
+		}
+
+		return value
+	}
+}
+
+// analyzePitch runs the pitch-offset check on a chord with the options of a vinyl transfer, where it is judged.
+func analyzePitch(t *testing.T, cents float64) *haustorium.Result {
+	t.Helper()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2, ExpectedBitDepth: types.Depth16}
+	data := testutil.Synthesize(format, 10, chordTrack(cents))
+
+	opts := haustorium.DefaultVinylOptions()
+	opts.Checks = haustorium.CheckPitchOffset
+
+	result, err := haustorium.Analyze(func() (io.Reader, error) { return bytes.NewReader(data), nil }, format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	return result
+}
+
+func TestPitchOffsetFixture(t *testing.T) {
+	t.Parallel()
+
+	fast := analyzePitch(t, 30)
+
+	issue := findIssue(t, fast, haustorium.CheckPitchOffset)
+	if !issue.Detected || math.Abs(fast.Spectral.PitchOffsetCents-30) > 3 {
+		t.Fatalf("expected a +30 cents offset, got %.1f cents: %s", fast.Spectral.PitchOffsetCents, issue.Summary)
+	}
+
+	inTune := analyzePitch(t, 0)
+
+	issue = findIssue(t, inTune, haustorium.CheckPitchOffset)
+	if issue.Detected || math.Abs(inTune.Spectral.PitchOffsetCents) > 3 {
+		t.Fatalf("expected an in-tune chord, got %.1f cents: %s", inTune.Spectral.PitchOffsetCents, issue.Summary)
+	}
+}
@@ -80,6 +80,34 @@ func TestProcessCLI(t *testing.T) {
 				}
 			},
 		},
+		{
+			Description: "process with --raw none keeps issues but drops raw blocks",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("file", agar.Genuine16bit44k(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Command(
+					"process",
+					"--debug",
+					"--raw",
+					"none",
+					"--format",
+					"json",
+					"--checks",
+					"clipping",
+					data.Labels().Get("file"),
+				)
+			},
+			Expected: func(_ test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: expect.All(
+						expect.Contains(`"issues"`),
+						expect.DoesNotContain(`"clipped_samples"`),
+					),
+				}
+			},
+		},
 	}
 
 	testCase.Run(t)
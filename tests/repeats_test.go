@@ -0,0 +1,47 @@
+package tests_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// TestRepeatsFixture plays 30 ms of noise twice at 1 s, as a drive re-reading a few sectors would: noise, unlike
+// the fixture sine, never repeats by itself.
+func TestRepeatsFixture(t *testing.T) {
+	t.Parallel()
+
+	const (
+		at     = 44100      // the stutter starts at 1 s
+		length = 44100 / 33 // ~30 ms, two CD sectors
+	)
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	noise := noiseSources(2, 3)
+
+	stutter := func(frame, channel int) float64 {
+		if frame >= at+length {
+			frame -= length
+		}
+
+		return noise[channel][frame]
+	}
+
+	issue := findIssue(t, analyzeSynthesized(t, haustorium.CheckRepeats, format, 2.5, stutter), haustorium.CheckRepeats)
+	if !issue.Detected || !strings.Contains(issue.Summary, "1 repeated blocks") {
+		t.Fatalf("expected one repeated block, got: %s", issue.Summary)
+	}
+
+	if !strings.Contains(issue.Summary, "first at 1.03s") {
+		t.Fatalf("expected the repeat right after the original 30 ms, got: %s", issue.Summary)
+	}
+
+	clean := func(frame, channel int) float64 { return noise[channel][frame] }
+
+	issue = findIssue(t, analyzeSynthesized(t, haustorium.CheckRepeats, format, 2.5, clean), haustorium.CheckRepeats)
+	if issue.Detected {
+		t.Fatalf("expected no repeats in plain noise, got: %s", issue.Summary)
+	}
+}
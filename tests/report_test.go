@@ -0,0 +1,576 @@
+package tests_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/containerd/nerdctl/mod/tigron/expect"
+	"github.com/containerd/nerdctl/mod/tigron/test"
+	"github.com/containerd/nerdctl/mod/tigron/tig"
+
+	"github.com/farcloser/agar/pkg/agar"
+
+	"github.com/farcloser/haustorium/internal/fingerprint"
+	"github.com/farcloser/haustorium/tests/testutils"
+)
+
+func TestReportCLI(t *testing.T) {
+	testCase := testutils.Setup()
+
+	prints := map[string]*fingerprint.Fingerprint{
+		"cd.flac":    musicFingerprint(t, 44100, 0),
+		"hires.flac": musicFingerprint(t, 48000, 0),
+		"other.flac": musicFingerprint(t, 48000, 1),
+	}
+
+	testCase.SubTests = []*test.Case{
+		{
+			Description: "an incremental report keeps the earlier records",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("first", agar.Genuine16bit44k(data, helpers))
+
+				first := helpers.Custom(testutils.ReportBinary(), "report", data.Temp().Dir())
+				first.WithCwd(data.Temp().Dir())
+				first.Run(&test.Expected{ExitCode: expect.ExitCodeSuccess})
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				data.Labels().Set("second", agar.Genuine24bit48k(data, helpers))
+
+				cmd := helpers.Custom(testutils.ReportBinary(),
+					"report", "--since-report", "haustorium-report.jsonl", data.Temp().Dir())
+				cmd.WithCwd(data.Temp().Dir())
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						report, err := os.ReadFile(filepath.Join(data.Temp().Dir(), "haustorium-report.jsonl"))
+						if err != nil {
+							testing.Log(err.Error())
+							testing.FailNow()
+						}
+
+						for _, label := range []string{"first", "second"} {
+							if !strings.Contains(string(report), filepath.Base(data.Labels().Get(label))) {
+								testing.Log("the report lost the " + label + " run's record:\n" + string(report))
+								testing.Fail()
+							}
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "an incremental report cuts at the scan start, whatever happened to the recorded files",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("first", agar.Genuine16bit44k(data, helpers))
+
+				first := helpers.Custom(testutils.ReportBinary(), "report", data.Temp().Dir())
+				first.WithCwd(data.Temp().Dir())
+				first.Run(&test.Expected{ExitCode: expect.ExitCodeSuccess})
+
+				// Touched after the run, to a time past the file added next.
+				later := time.Now().Add(time.Hour)
+				if err := os.Chtimes(data.Labels().Get("first"), later, later); err != nil {
+					helpers.T().Log(err.Error())
+					helpers.T().FailNow()
+				}
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				data.Labels().Set("second", agar.Genuine24bit48k(data, helpers))
+
+				cmd := helpers.Custom(testutils.ReportBinary(),
+					"report", "--since-report", "haustorium-report.jsonl", data.Temp().Dir())
+				cmd.WithCwd(data.Temp().Dir())
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						report, err := os.ReadFile(filepath.Join(data.Temp().Dir(), "haustorium-report.jsonl"))
+						if err != nil {
+							testing.Log(err.Error())
+							testing.FailNow()
+						}
+
+						lines := strings.Split(strings.TrimSpace(string(report)), "\n")
+
+						var header struct {
+							Scan *struct {
+								Complete bool `json:"complete"`
+							} `json:"scan"`
+						}
+
+						if err := json.Unmarshal([]byte(lines[0]), &header); err != nil || header.Scan == nil ||
+							!header.Scan.Complete {
+							testing.Log("the report does not start with a complete header:\n" + string(report))
+							testing.Fail()
+						}
+
+						recorded := map[string]int{}
+
+						for _, line := range lines[1:] {
+							var rec struct {
+								File string `json:"file"`
+							}
+
+							if err := json.Unmarshal([]byte(line), &rec); err == nil {
+								recorded[filepath.Base(rec.File)]++
+							}
+						}
+
+						for _, label := range []string{"first", "second"} {
+							if count := recorded[filepath.Base(data.Labels().Get(label))]; count != 1 {
+								testing.Log(fmt.Sprintf("the %s file has %d records:\n%s", label, count, report))
+								testing.Fail()
+							}
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "report --resume finishes an interrupted run, album by album, without losing or repeating any",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				// Two albums: A, two tracks, and B, one.
+				for label, fixture := range map[string]func(test.Data, test.Helpers) string{
+					"A/first": agar.Genuine16bit44k, "A/second": agar.Genuine24bit48k, "B/third": agar.Genuine24bit96k,
+				} {
+					source := fixture(data, helpers)
+
+					target := filepath.Join(data.Temp().Dir(), filepath.Dir(label), filepath.Base(source))
+					if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+						helpers.T().Log(err.Error())
+						helpers.T().FailNow()
+					}
+
+					if err := os.Rename(source, target); err != nil {
+						helpers.T().Log(err.Error())
+						helpers.T().FailNow()
+					}
+
+					data.Labels().Set(label, target)
+				}
+
+				first := helpers.Custom(testutils.ReportBinary(), "report", "--album-records", data.Temp().Dir())
+				first.WithCwd(data.Temp().Dir())
+				first.Run(&test.Expected{ExitCode: expect.ExitCodeSuccess})
+
+				// Interrupted while on album B: its records were never written, and the header is incomplete.
+				reportPath := data.Temp().Path("haustorium-report.jsonl")
+
+				lines := readReportLines(helpers.T(), reportPath)
+				kept := []string{strings.Replace(lines[0].raw, `"complete":true`, `"complete":false`, 1)}
+
+				for _, line := range lines[1:] {
+					if line.dir() == filepath.Join(data.Temp().Dir(), "A") {
+						kept = append(kept, line.raw)
+					}
+				}
+
+				if err := os.WriteFile(reportPath, []byte(strings.Join(kept, "\n")+"\n"), 0o600); err != nil {
+					helpers.T().Log(err.Error())
+					helpers.T().FailNow()
+				}
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Custom(testutils.ReportBinary(), "report", "--resume", "--album-records",
+					data.Temp().Dir())
+				cmd.WithCwd(data.Temp().Dir())
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						lines := readReportLines(testing, data.Temp().Path("haustorium-report.jsonl"))
+						if lines[0].Scan == nil || !lines[0].Scan.Complete {
+							testing.Log("expected a complete header, got: " + lines[0].raw)
+							testing.Fail()
+						}
+
+						files, albums := map[string]int{}, map[string]int{}
+
+						for _, line := range lines[1:] {
+							if line.Album != nil {
+								albums[filepath.Base(line.Album.Dir)]++
+							} else {
+								files[line.File]++
+							}
+						}
+
+						for _, label := range []string{"A/first", "A/second", "B/third"} {
+							if count := files[data.Labels().Get(label)]; count != 1 {
+								testing.Log(fmt.Sprintf("expected one record of %s, got %d", label, count))
+								testing.Fail()
+							}
+						}
+
+						if albums["A"] != 1 || albums["B"] != 1 || len(albums) != 2 {
+							testing.Log(fmt.Sprintf("expected one album record for A and B, got %v", albums))
+							testing.Fail()
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "report --sample-n records its sample in the header, and analyzes only the selected files",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				agar.Genuine16bit44k(data, helpers)
+				agar.Genuine24bit48k(data, helpers)
+				agar.Genuine24bit96k(data, helpers)
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Custom(testutils.ReportBinary(), "report", "--sample-n", "2", "--seed", "7",
+					data.Temp().Dir())
+				cmd.WithCwd(data.Temp().Dir())
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						lines := readReportLines(testing, data.Temp().Path("haustorium-report.jsonl"))
+
+						sample := lines[0].Sample
+						if sample == nil || sample.Count != 2 || sample.Seed != 7 || sample.Population != 3 ||
+							sample.Selected != 2 {
+							testing.Log("expected 2 of 3 files sampled with seed 7 in the header, got: " + lines[0].raw)
+							testing.Fail()
+						}
+
+						if len(lines) != 3 {
+							testing.Log(fmt.Sprintf("expected the header and 2 file records, got %d lines", len(lines)))
+							testing.Fail()
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "report --from-file processes the listed audio files, and skips the rest",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("listed", agar.Genuine16bit44k(data, helpers))
+				data.Labels().Set("unlisted", agar.Genuine24bit48k(data, helpers))
+
+				list := strings.Join([]string{
+					data.Labels().Get("listed"),
+					data.Temp().Path("missing.flac"),
+					data.Temp().Path("notes.txt"),
+					"",
+					data.Labels().Get("listed"),
+				}, "\n")
+
+				data.Temp().Save("notes", "notes.txt")
+				data.Temp().Save(list, "files.txt")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Custom(testutils.ReportBinary(), "report", "--from-file", data.Temp().Path("files.txt"))
+				cmd.WithCwd(data.Temp().Dir())
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						var files []string
+
+						for _, line := range readReportLines(testing, data.Temp().Path("haustorium-report.jsonl"))[1:] {
+							files = append(files, line.File)
+						}
+
+						if len(files) != 1 || files[0] != data.Labels().Get("listed") {
+							testing.Log(fmt.Sprintf("expected the listed audio file once, got %v", files))
+							testing.Fail()
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "report --from-stdin reads the list from standard input",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("listed", agar.Genuine16bit44k(data, helpers))
+				agar.Genuine24bit48k(data, helpers)
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				cmd := helpers.Custom(testutils.ReportBinary(), "report", "--from-stdin")
+				cmd.WithCwd(data.Temp().Dir())
+				cmd.Feed(strings.NewReader(data.Labels().Get("listed") + "\n"))
+
+				return cmd
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(_ string, testing tig.T) {
+						testing.Helper()
+
+						lines := readReportLines(testing, data.Temp().Path("haustorium-report.jsonl"))
+						if len(lines) != 2 || lines[1].File != data.Labels().Get("listed") {
+							testing.Log(fmt.Sprintf("expected the listed file alone, got %d lines", len(lines)))
+							testing.Fail()
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "report refuses --from-file with --from-stdin",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "report", "--from-file", data.Temp().Path("files.txt"),
+					"--from-stdin")
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail,
+				[]error{errors.New("--from-file and --from-stdin are mutually exclusive")}, nil),
+		},
+		{
+			Description: "report takes no folder with a file list",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "report", "--from-stdin", data.Temp().Dir())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail,
+				[]error{errors.New("expected no argument with --from-file or --from-stdin")}, nil),
+		},
+		{
+			Description: "watch reports a file landing in the folder once, across restarts",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				watch := func() test.TestableCommand {
+					cmd := helpers.Custom(testutils.ReportBinary(),
+						"watch", "--settle", "500ms", "--interval", "100ms", data.Temp().Dir())
+					cmd.WithCwd(data.Temp().Dir())
+					cmd.Background()
+
+					return cmd
+				}
+
+				first := watch()
+				time.Sleep(time.Second)
+
+				file := agar.Genuine16bit44k(data, helpers)
+				data.Labels().Set("file", file)
+
+				waitForReport(helpers, data.Temp().Path("haustorium-report.jsonl"), file)
+				_ = first.Signal(os.Interrupt)
+				first.Run(&test.Expected{ExitCode: expect.ExitCodeSuccess})
+
+				second := watch()
+				time.Sleep(2 * time.Second)
+				_ = second.Signal(os.Interrupt)
+				second.Run(&test.Expected{ExitCode: expect.ExitCodeSuccess})
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom("cat", data.Temp().Path("haustorium-report.jsonl"))
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return &test.Expected{
+					ExitCode: expect.ExitCodeSuccess,
+					Output: func(stdout string, testing tig.T) {
+						testing.Helper()
+
+						record, _ := json.Marshal(data.Labels().Get("file"))
+						if count := strings.Count(stdout, `"file":`+string(record)); count != 1 {
+							testing.Log(fmt.Sprintf("expected the file recorded once, got %d records:\n%s",
+								count, stdout))
+							testing.Fail()
+						}
+					},
+				}
+			},
+		},
+		{
+			Description: "watch refuses --redact-path, which leaves no paths to skip the reported files by",
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "watch", "--redact-path", data.Temp().Dir())
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail,
+				[]error{errors.New("--redact-path cannot be used with watch")}, nil),
+		},
+		{
+			Description: "dupes groups the same recording at two sample rates, and only it",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				var lines []byte
+
+				for file, print := range prints {
+					line, err := json.Marshal(map[string]any{"file": file, "fingerprint": print})
+					if err != nil {
+						helpers.T().Log(err.Error())
+						helpers.T().FailNow()
+					}
+
+					lines = append(append(lines, line...), '\n')
+				}
+
+				data.Temp().Save(string(lines), "report.jsonl")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "dupes", data.Temp().Path("report.jsonl"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+				expect.Contains("Cluster 1 (same recording", "cd.flac", "hires.flac"),
+				expect.DoesNotContain("other.flac", "Cluster 2"),
+			)),
+		},
+		{
+			Description: "notches groups the tracks sharing notches, and only them",
+			Setup: func(data test.Data, _ test.Helpers) {
+				record := func(file string, notches ...float64) string {
+					parts := make([]string, 0, len(notches))
+					for _, hz := range notches {
+						parts = append(parts, fmt.Sprintf(`{"hz":%g,"depth_db":18}`, hz))
+					}
+
+					return fmt.Sprintf(`{"file":%q,"analysis":{"spectral":{"notches":[%s]}}}`+"\n",
+						file, strings.Join(parts, ","))
+				}
+
+				data.Temp().Save(record("first.flac", 1000, 3000, 7000)+record("second.flac", 1005, 3010)+
+					record("other.flac", 3000, 5000), "report.jsonl")
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "notches", data.Temp().Path("report.jsonl"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil, expect.All(
+				expect.Contains("Cluster 1 (2 tracks, all notched at 1000, 3000 Hz)", "first.flac", "second.flac"),
+				expect.DoesNotContain("other.flac", "Cluster 2"),
+			)),
+		},
+		{
+			Description: "playlist passes a track within tolerance of itself",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("file", agar.Genuine16bit44k(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				// The ceiling is out of the way: the fixture's true peak is not under test.
+				return helpers.Custom(testutils.ReportBinary(), "playlist", "--ceiling", "6",
+					data.Labels().Get("file"), data.Labels().Get("file"))
+			},
+			Expected: test.Expects(expect.ExitCodeSuccess, nil,
+				expect.Contains("( +0.0 LU)", "2 files: 0 loudness outliers, 0 over ceiling, 0 errors")),
+		},
+		{
+			Description: "playlist flags the tracks away from the target, and fails",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("first", agar.Genuine16bit44k(data, helpers))
+				data.Labels().Set("second", agar.Genuine24bit48k(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "playlist", "--target", "-99", "--ceiling", "6",
+					data.Labels().Get("first"), data.Labels().Get("second"))
+			},
+			Expected: func(data test.Data, _ test.Helpers) *test.Expected {
+				return test.Expects(expect.ExitCodeGenericFail, []error{errors.New("playlist is not compliant")},
+					expect.All(
+						expect.Contains("Target: -99.0 LUFS", filepath.Base(data.Labels().Get("first")),
+							filepath.Base(data.Labels().Get("second")), "[loudness outlier]"),
+						expect.Contains("2 files: 2 loudness outliers, 0 over ceiling, 0 errors"),
+					))(data, nil)
+			},
+		},
+		{
+			Description: "playlist takes at least two audio files",
+			Setup: func(data test.Data, helpers test.Helpers) {
+				data.Labels().Set("file", agar.Genuine16bit44k(data, helpers))
+			},
+			Command: func(data test.Data, helpers test.Helpers) test.TestableCommand {
+				return helpers.Custom(testutils.ReportBinary(), "playlist", data.Labels().Get("file"))
+			},
+			Expected: test.Expects(expect.ExitCodeGenericFail, []error{errors.New("expected at least two files")}, nil),
+		},
+	}
+
+	testCase.Run(t)
+}
+
+// waitForReport waits, up to a minute, until the report records the file.
+func waitForReport(helpers test.Helpers, report, file string) {
+	helpers.T().Helper()
+
+	record, _ := json.Marshal(file)
+
+	for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if content, err := os.ReadFile(report); err == nil && strings.Contains(string(content), string(record)) {
+			return
+		}
+	}
+
+	helpers.T().Log("the watch did not report " + file)
+	helpers.T().FailNow()
+}
+
+// reportLine is a line of a report: a file record, an album record, or the header.
+type reportLine struct {
+	File  string `json:"file"`
+	Album *struct {
+		Dir string `json:"dir"`
+	} `json:"album"`
+	Scan *struct {
+		Complete bool `json:"complete"`
+	} `json:"scan"`
+	Sample *struct {
+		Count      int    `json:"count"`
+		Seed       uint64 `json:"seed"`
+		Population int    `json:"population"`
+		Selected   int    `json:"selected"`
+	} `json:"sample"`
+
+	raw string
+}
+
+// dir is the album of the line: its directory.
+func (line reportLine) dir() string {
+	if line.Album != nil {
+		return line.Album.Dir
+	}
+
+	return filepath.Dir(line.File)
+}
+
+// readReportLines parses the lines of a report.
+func readReportLines(testing tig.T, path string) []reportLine {
+	testing.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		testing.Log(err.Error())
+		testing.FailNow()
+	}
+
+	var lines []reportLine
+
+	for raw := range strings.SplitSeq(strings.TrimSpace(string(content)), "\n") {
+		line := reportLine{raw: raw}
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			testing.Log(err.Error())
+			testing.FailNow()
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
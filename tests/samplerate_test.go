@@ -1,13 +1,17 @@
 package tests_test
 
 import (
+	"math/rand/v2"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
 	"github.com/containerd/nerdctl/mod/tigron/test"
+	"gonum.org/v1/gonum/dsp/fourier"
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -49,3 +53,54 @@ func TestFakeSampleRate(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// bandLimitedNoise returns seconds of white noise (-12 dBFS peak) at the sample rate, with everything above cutoffHz
+// removed: the spectrum of a source at twice cutoffHz, upsampled without adding anything.
+func bandLimitedNoise(rate int, seconds, cutoffHz float64) []float64 {
+	frames := int(seconds * float64(rate))
+	rng := rand.New(rand.NewPCG(9, 10))
+
+	noise := make([]float64, frames)
+	for i := range noise {
+		noise[i] = 0.25 * (2*rng.Float64() - 1)
+	}
+
+	fft := fourier.NewFFT(frames)
+	coeffs := fft.Coefficients(nil, noise)
+
+	for i := range coeffs {
+		if fft.Freq(i)*float64(rate) > cutoffHz {
+			coeffs[i] = 0
+		}
+	}
+
+	values := fft.Sequence(nil, coeffs)
+	for i := range values {
+		values[i] /= float64(frames)
+	}
+
+	return values
+}
+
+// TestFakeSampleRateFixture checks CD rate files against a low rate source: 22.05 kHz voice or game audio, upsampled.
+func TestFakeSampleRateFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	for _, tc := range []struct {
+		cutoffHz float64
+		summary  string
+	}{
+		{11025, "Fake 44100 Hz: upsampled from 22050 Hz"},
+		{22050, "Genuine 44100 Hz"},
+	} {
+		noise := bandLimitedNoise(44100, 5, tc.cutoffHz)
+
+		issue := findIssue(t, analyzeSynthesized(t, haustorium.CheckFakeSampleRate, format, 5,
+			func(frame, _ int) float64 { return noise[frame] }), haustorium.CheckFakeSampleRate)
+		if issue.Summary != tc.summary {
+			t.Fatalf("noise up to %.0f Hz: expected %q, got %q", tc.cutoffHz, tc.summary, issue.Summary)
+		}
+	}
+}
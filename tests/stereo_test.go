@@ -1,6 +1,10 @@
 package tests_test
 
 import (
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"strings"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +12,9 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -127,3 +134,239 @@ func TestPhaseIssues(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// TestPseudoStereoFixture runs the stereo checks on a synthetic sine, with the right channel the left one inverted:
+// one pseudo-stereo verdict, the phase checks explained by it.
+func TestPseudoStereoFixture(t *testing.T) {
+	t.Parallel()
+
+	checks := haustorium.CheckFakeStereo | haustorium.CheckInvertedPhase | haustorium.CheckPhaseIssues
+
+	result := analyzeRendered(t, checks, testutil.Spec{}, func(data []byte) {
+		for i := 2; i+1 < len(data); i += 4 {
+			binary.LittleEndian.PutUint16(data[i:], uint16(-int16(binary.LittleEndian.Uint16(data[i:]))))
+		}
+	})
+
+	pseudo := findIssue(t, result, haustorium.CheckFakeStereo)
+	if !pseudo.Detected || !strings.Contains(pseudo.Summary, "Pseudo-stereo from mono") {
+		t.Fatalf("expected pseudo-stereo from mono, got: %s", pseudo.Summary)
+	}
+
+	if pseudo.Severity != haustorium.SeveritySevere {
+		t.Fatalf("expected the phase checks' severity on the pseudo-stereo verdict, got %s", pseudo.Severity)
+	}
+
+	for _, check := range []haustorium.Check{haustorium.CheckInvertedPhase, haustorium.CheckPhaseIssues} {
+		if issue := findIssue(t, result, check); issue.Detected {
+			t.Fatalf("expected %s to be explained by the pseudo-stereo, got: %s", check, issue.Summary)
+		}
+	}
+
+	// The same mono in both channels, not inverted, is plain fake stereo.
+	result = analyzeRendered(t, checks, testutil.Spec{}, nil)
+	if issue := findIssue(t, result, haustorium.CheckFakeStereo); strings.Contains(issue.Summary, "Pseudo-stereo") {
+		t.Fatalf("expected identical channels not to be pseudo-stereo, got: %s", issue.Summary)
+	}
+
+	if findIssue(t, result, haustorium.CheckInvertedPhase).Detected {
+		t.Fatal("expected identical channels not to be inverted")
+	}
+}
+
+// foldedTrack returns a stereo track of midrange tones identical in both channels (a center channel) over
+// independent noise, with upper band tones from a surround channel, folded in anti-phase when matrixed.
+func foldedTrack(matrixed bool) func(frame, channel int) float64 {
+	rng := rand.New(rand.NewPCG(5, 6))
+
+	return func(frame, channel int) float64 {
+		at := float64(frame) / 44100
+		value := 0.02 * (2*rng.Float64() - 1)
+
+		for _, freq := range []float64{500, 1000, 2000} {
+			value += 0.15 * math.Sin(2*math.Pi*freq*at)
+		}
+
+		surround := 0.1 * (math.Sin(2*math.Pi*6000*at) + math.Sin(2*math.Pi*8000*at))
+		if matrixed && channel == 1 {
+			surround = -surround
+		}
+
+		// Unmatrixed, the surround only reaches the left channel.
+		if matrixed || channel == 0 {
+			value += surround
+		}
+
+		return value
+	}
+}
+
+func TestDownmixFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	folded := analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5, foldedTrack(true))
+	if !folded.Stereo.SuspectedDownmix {
+		t.Fatalf("expected a suspected downmix (mid correlation %.2f, upper band %.2f)",
+			folded.Stereo.MidCorrelation, folded.Stereo.SideCorrelation)
+	}
+
+	plain := analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5, foldedTrack(false))
+	if plain.Stereo.SuspectedDownmix {
+		t.Fatalf("expected no downmix without anti-phase surrounds (upper band correlation %.2f)",
+			plain.Stereo.SideCorrelation)
+	}
+}
+
+// bassPhaseTrack returns a 60 Hz bass under a 1 kHz tone, both identical in the two channels unless the bass is
+// inverted in the right one.
+func bassPhaseTrack(inverted bool) func(frame, channel int) float64 {
+	return func(frame, channel int) float64 {
+		at := float64(frame) / 44100
+		bass := 0.3 * math.Sin(2*math.Pi*60*at)
+
+		if inverted && channel == 1 {
+			bass = -bass
+		}
+
+		return bass + 0.2*math.Sin(2*math.Pi*1000*at)
+	}
+}
+
+func TestVinylCutSafetyFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+
+	unsafe := findIssue(t, analyzeSynthesized(t, haustorium.CheckVinylCutSafety, format, 3, bassPhaseTrack(true)),
+		haustorium.CheckVinylCutSafety)
+	if !unsafe.Detected || unsafe.Severity != haustorium.SeveritySevere {
+		t.Fatalf("expected out-of-phase bass to be severe, got %s: %s", unsafe.Severity, unsafe.Summary)
+	}
+
+	if !strings.Contains(unsafe.Summary, "40-80 Hz out of phase") {
+		t.Fatalf("expected the 40-80 Hz band out of phase, got: %s", unsafe.Summary)
+	}
+
+	safe := findIssue(t, analyzeSynthesized(t, haustorium.CheckVinylCutSafety, format, 3, bassPhaseTrack(false)),
+		haustorium.CheckVinylCutSafety)
+	if safe.Detected {
+		t.Fatalf("expected mono bass to be safe for cutting, got: %s", safe.Summary)
+	}
+}
+
+// noiseSources returns count independent white noise sources (-12 dBFS peak) of seconds at 44.1 kHz.
+func noiseSources(count int, seconds float64) [][]float64 {
+	rng := rand.New(rand.NewPCG(11, 12))
+	sources := make([][]float64, count)
+
+	for idx := range sources {
+		sources[idx] = make([]float64, int(seconds*44100))
+		for i := range sources[idx] {
+			sources[idx][i] = 0.25 * (2*rng.Float64() - 1)
+		}
+	}
+
+	return sources
+}
+
+// pannedStereo returns two independent sources, one panned left and one panned right: genuine stereo.
+func pannedStereo(seconds float64) func(frame, channel int) float64 {
+	sources := noiseSources(2, seconds)
+
+	return func(frame, channel int) float64 {
+		if channel == 0 {
+			return 0.8*sources[0][frame] + 0.2*sources[1][frame]
+		}
+
+		return 0.2*sources[0][frame] + 0.8*sources[1][frame]
+	}
+}
+
+func TestSyntheticWidthFixture(t *testing.T) {
+	t.Parallel()
+
+	const delay = 220 // 5 ms: a comb every 200 Hz
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	mono := noiseSources(1, 5)[0]
+
+	delayed := func(frame, channel int) float64 {
+		if channel == 1 {
+			frame = max(frame-delay, 0)
+		}
+
+		return mono[frame]
+	}
+
+	widened := findIssue(t, analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5, delayed),
+		haustorium.CheckFakeStereo)
+	if !widened.Detected || !strings.Contains(widened.Summary, "comb pattern every 200 Hz") {
+		t.Fatalf("expected a comb every 200 Hz from the delayed channel, got: %s", widened.Summary)
+	}
+
+	genuine := findIssue(t, analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5, pannedStereo(5)),
+		haustorium.CheckFakeStereo)
+	if genuine.Detected {
+		t.Fatalf("expected panned sources to be real stereo, got: %s", genuine.Summary)
+	}
+}
+
+func TestWidthChangesFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	stereo := pannedStereo(16)
+
+	// A mono intro spliced onto a stereo take, at 8 s.
+	spliced := func(frame, channel int) float64 {
+		if frame < 8*44100 {
+			channel = 0
+		}
+
+		return stereo(frame, channel)
+	}
+
+	changed := findIssue(t, analyzeSynthesized(t, haustorium.CheckWidthChanges, format, 16, spliced),
+		haustorium.CheckWidthChanges)
+	if !strings.HasPrefix(changed.Summary, "Stereo width changes: to stereo at 8.0s (") {
+		t.Fatalf("expected a transition to stereo at 8 s, got: %s", changed.Summary)
+	}
+
+	steady := findIssue(t, analyzeSynthesized(t, haustorium.CheckWidthChanges, format, 16, stereo),
+		haustorium.CheckWidthChanges)
+	if steady.Summary != "No mono/stereo transitions" {
+		t.Fatalf("expected no transition on steady stereo, got: %s", steady.Summary)
+	}
+}
+
+func TestDecorrelatedFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 2}
+	sources := noiseSources(2, 5)
+	muffled := bandLimitedNoise(44100, 5, 4000)
+
+	// Two unrelated noises of one spectrum: what an all-pass widener makes of a mono source.
+	decorrelated := findIssue(t, analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5,
+		func(frame, channel int) float64 {
+			return sources[channel][frame]
+		}), haustorium.CheckFakeStereo)
+	if !decorrelated.Detected || !strings.HasPrefix(decorrelated.Summary, "Artificially decorrelated stereo") {
+		t.Fatalf("expected unrelated channels of one spectrum to be decorrelated, got: %s", decorrelated.Summary)
+	}
+
+	// As unrelated, but with a different source on each side, their spectra apart.
+	distinct := findIssue(t, analyzeSynthesized(t, haustorium.CheckFakeStereo, format, 5,
+		func(frame, channel int) float64 {
+			if channel == 1 {
+				return muffled[frame]
+			}
+
+			return sources[0][frame]
+		}), haustorium.CheckFakeStereo)
+	if distinct.Detected {
+		t.Fatalf("expected different sources on each side to be real stereo, got: %s", distinct.Summary)
+	}
+}
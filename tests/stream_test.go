@@ -0,0 +1,143 @@
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+// streamFixture streams a synthetic fixture through AnalyzeStream, and returns the block issues and final result.
+func streamFixture(t *testing.T, spec testutil.Spec, opts haustorium.Options) ([]haustorium.Issue, *haustorium.Result) {
+	t.Helper()
+
+	_, format := testutil.Fixture(spec)
+
+	events, err := haustorium.AnalyzeStream(context.Background(), bytes.NewReader(testutil.Render(spec)), format, opts)
+	if err != nil {
+		t.Fatalf("stream analysis failed: %v", err)
+	}
+
+	var issues []haustorium.Issue
+
+	for event := range events {
+		switch {
+		case event.Err != nil:
+			t.Fatalf("stream analysis failed: %v", event.Err)
+		case event.Issue != nil:
+			issues = append(issues, *event.Issue)
+		case event.Result != nil:
+			return issues, event.Result
+		default:
+		}
+	}
+
+	t.Fatal("the stream closed without a result")
+
+	return nil, nil
+}
+
+func TestAnalyzeStreamPostProcess(t *testing.T) {
+	t.Parallel()
+
+	var called bool
+
+	// No checks selected: the defaults run, and the hook with them.
+	_, result := streamFixture(t, testutil.Spec{}, haustorium.Options{
+		PostProcess: func(*haustorium.Result) { called = true },
+	})
+
+	if !called {
+		t.Fatal("expected the post-processing hook to run on the final result")
+	}
+
+	if len(result.Issues) == 0 {
+		t.Fatal("expected the default checks to run")
+	}
+}
+
+// TestAnalyzeStreamBlocks injects a click in the second 10 s block: it is reported as soon as the block is read,
+// while truncation, which needs the end of the stream, only reports in the final result, identical to Analyze's.
+func TestAnalyzeStreamBlocks(t *testing.T) {
+	t.Parallel()
+
+	spec := testutil.Spec{
+		Seconds: 25,
+		Defects: []testutil.Defect{{Kind: testutil.DefectClick, AtSec: 15, Channel: testutil.AllChannels}},
+	}
+
+	opts := haustorium.DefaultDigitalOptions()
+	opts.Checks = haustorium.CheckDropouts | haustorium.CheckClipping | haustorium.CheckTruncation
+
+	issues, result := streamFixture(t, spec, opts)
+	if len(issues) != 1 || issues[0].Check != haustorium.CheckDropouts {
+		t.Fatalf("expected the click streamed as one dropouts issue, got: %+v", issues)
+	}
+
+	factory, format := testutil.Fixture(spec)
+
+	whole, err := haustorium.Analyze(factory, format, opts)
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Issues, whole.Issues) {
+		t.Fatalf("expected the final result to be Analyze's:\n%+v\n%+v", result.Issues, whole.Issues)
+	}
+
+	if events := result.Dropout.Events; len(events) == 0 || events[0].TimeSec != 15 {
+		t.Fatalf("expected the click positioned at 15 s in the stream, got: %+v", events)
+	}
+
+	// A clean stream streams nothing.
+	if issues, _ := streamFixture(t, testutil.Spec{Seconds: 25}, opts); len(issues) != 0 {
+		t.Fatalf("expected no streamed issue on a clean stream, got: %+v", issues)
+	}
+}
+
+// TestAnalyzeStreamMaxBytes streams a click past the memory cap: it is still reported as its block is read, and
+// the final event fails instead of analyzing the stream whole.
+func TestAnalyzeStreamMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	spec := testutil.Spec{
+		Seconds: 25,
+		Defects: []testutil.Defect{{Kind: testutil.DefectClick, AtSec: 15, Channel: testutil.AllChannels}},
+	}
+
+	_, format := testutil.Fixture(spec)
+
+	opts := haustorium.DefaultDigitalOptions()
+	opts.Checks = haustorium.CheckDropouts
+	opts.StreamMaxBytes = 1 << 20 // under the first 10 s block
+
+	events, err := haustorium.AnalyzeStream(context.Background(), bytes.NewReader(testutil.Render(spec)), format, opts)
+	if err != nil {
+		t.Fatalf("stream analysis failed: %v", err)
+	}
+
+	var (
+		issues int
+		last   haustorium.StreamEvent
+	)
+
+	for event := range events {
+		if event.Issue != nil {
+			issues++
+		} else {
+			last = event
+		}
+	}
+
+	if issues != 1 {
+		t.Fatalf("expected the click streamed past the cap, got %d issues", issues)
+	}
+
+	if last.Result != nil || !errors.Is(last.Err, haustorium.ErrStreamTooLong) {
+		t.Fatalf("expected the final event to fail with ErrStreamTooLong, got: %+v", last)
+	}
+}
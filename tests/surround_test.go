@@ -0,0 +1,118 @@
+package tests_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/integration/ffprobe"
+	"github.com/farcloser/haustorium/internal/report"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// surroundTrack returns a 5.1 track of independent noise channels. Its 4th channel, the LFE in the ffmpeg/WAV order,
+// is a 50 Hz tone, or another full-range noise channel when the layout is wrong.
+func surroundTrack(misplaced bool) func(frame, channel int) float64 {
+	rng := rand.New(rand.NewPCG(5, 6))
+
+	return func(frame, channel int) float64 {
+		if channel == 3 && !misplaced {
+			return 0.3 * math.Sin(2*math.Pi*50*float64(frame)/44100)
+		}
+
+		return 0.2 * (2*rng.Float64() - 1)
+	}
+}
+
+func TestChannelLayoutFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 6}
+
+	misplaced := analyzeSynthesized(t, haustorium.CheckLoudness, format, 5, surroundTrack(true))
+	if !misplaced.Loudness.LFEFullRange {
+		t.Fatalf("expected a noise channel at the LFE position to be full-range, got %.0f%% above 200 Hz",
+			100*misplaced.Loudness.LFEHighBandShare)
+	}
+
+	if summary := findIssue(t, misplaced, haustorium.CheckLoudness).Summary; !strings.Contains(summary,
+		"the channel layout is likely wrong") {
+		t.Fatalf("expected the loudness summary to flag the layout, got: %s", summary)
+	}
+
+	mapped := analyzeSynthesized(t, haustorium.CheckLoudness, format, 5, surroundTrack(false)).Loudness
+	if !mapped.LFEMeasured || mapped.LFEFullRange {
+		t.Fatalf("expected a band-limited LFE, got %.0f%% above 200 Hz", 100*mapped.LFEHighBandShare)
+	}
+}
+
+// TestChannelLayoutProbe checks the probed layout against the channel count: the record keeps it when the file
+// cannot be decoded.
+func TestChannelLayoutProbe(t *testing.T) {
+	t.Parallel()
+
+	record := func(layout string, channels int) report.Record {
+		return probeRecord(t, ffprobe.Stream{BaseStream: ffprobe.BaseStream{
+			CodecName:     "flac",
+			SampleRate:    "48000",
+			Channels:      channels,
+			ChannelLayout: layout,
+		}})
+	}
+
+	mislabeled := record("stereo", 6)
+	if detail := mislabeled.ChannelLayoutDetail; !mislabeled.ChannelLayoutSuspect ||
+		detail != "layout stereo has 2 channels, the stream 6" {
+		t.Fatalf("expected a 6 channel stream labeled stereo to be suspect, got: %+v", mislabeled)
+	}
+
+	if labeled := record("5.1", 6); labeled.ChannelLayoutSuspect {
+		t.Fatalf("expected a 6 channel 5.1 stream not to be suspect, got: %s", labeled.ChannelLayoutDetail)
+	}
+}
+
+// TestGatedCoverageLFE plays 2 seconds of a 10 second 5.1 track, over a loud LFE tone from start to end: the LFE
+// is left out of the measurement, so the gates drop the 8 seconds it plays alone, and the loudness notes the
+// integrated figure stands on a fifth of the track.
+func TestGatedCoverageLFE(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth16, Channels: 6}
+
+	sparse := func(lfe float64) func(frame, channel int) float64 {
+		rng := rand.New(rand.NewPCG(5, 6))
+
+		return func(frame, channel int) float64 {
+			switch {
+			case channel == 3:
+				return lfe * math.Sin(2*math.Pi*50*float64(frame)/44100)
+			case frame < 2*44100:
+				return 0.2 * (2*rng.Float64() - 1)
+			}
+
+			return 0
+		}
+	}
+
+	result := analyzeSynthesized(t, haustorium.CheckLoudness, format, 10, sparse(0.8))
+	silentLFE := analyzeSynthesized(t, haustorium.CheckLoudness, format, 10, sparse(0)).Loudness
+
+	coverage := result.Loudness.GatedCoverage
+	if coverage < 0.15 || coverage > 0.25 {
+		t.Fatalf("expected the gates to keep the 2 seconds of the front and surround channels, got %.0f%%",
+			100*coverage)
+	}
+
+	if math.Abs(result.Loudness.IntegratedLUFS-silentLFE.IntegratedLUFS) > 0.1 {
+		t.Fatalf("expected the LFE to be left out, got %.1f LUFS with it, %.1f without", result.Loudness.IntegratedLUFS,
+			silentLFE.IntegratedLUFS)
+	}
+
+	note := fmt.Sprintf("(based on %.0f%% of the track)", 100*coverage)
+	if summary := findIssue(t, result, haustorium.CheckLoudness).Summary; !strings.Contains(summary, note) {
+		t.Fatalf("expected the loudness summary to note the coverage, got: %s", summary)
+	}
+}
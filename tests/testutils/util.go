@@ -18,3 +18,10 @@ func Setup() *test.Case {
 
 	return agar.Setup(binaryPath)
 }
+
+// ReportBinary returns the path of the hau-report binary, to run with helpers.Custom.
+func ReportBinary() string {
+	_, thisFile, _, _ := runtime.Caller(0) //nolint:dogsled // runtime.Caller returns 4 values, only file is needed
+
+	return filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(thisFile))), "bin", "hau-report")
+}
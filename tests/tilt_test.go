@@ -0,0 +1,82 @@
+package tests_test
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
+)
+
+// tiltedNoise returns seconds of noise at 44.1 kHz whose spectrum tilts by tilt dB/octave against pink noise.
+func tiltedNoise(seconds, tilt float64) []float64 {
+	frames := int(seconds * 44100)
+	rng := rand.New(rand.NewPCG(15, 16))
+
+	noise := make([]float64, frames)
+	for i := range noise {
+		noise[i] = 2*rng.Float64() - 1
+	}
+
+	fft := fourier.NewFFT(frames)
+	coeffs := fft.Coefficients(nil, noise)
+
+	// Pink noise falls 3 dB per octave on a per-bin spectrum, with its level at 1 kHz the white noise's.
+	for i := range coeffs {
+		hz := fft.Freq(i) * 44100
+		if hz == 0 {
+			coeffs[i] = 0
+
+			continue
+		}
+
+		coeffs[i] *= complex(math.Pow(10, (tilt-3)*math.Log2(hz/1000)/20), 0)
+	}
+
+	values := fft.Sequence(nil, coeffs)
+
+	var peak float64
+	for _, value := range values {
+		peak = max(peak, math.Abs(value))
+	}
+
+	for i := range values {
+		values[i] *= 0.5 / peak
+	}
+
+	return values
+}
+
+func TestSpectralTiltFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	for _, tc := range []struct {
+		tilt    float64
+		summary string
+	}{
+		{haustorium.SpectralTiltTypicalDbPerOct + 6, "Bright, harsh balance"},
+		{haustorium.SpectralTiltTypicalDbPerOct - 6, "Dull, muffled balance"},
+		{haustorium.SpectralTiltTypicalDbPerOct, "Balanced tonal tilt"},
+	} {
+		noise := tiltedNoise(5, tc.tilt)
+
+		result := analyzeSynthesized(t, haustorium.CheckSpectralTilt, format, 5, func(frame, _ int) float64 {
+			return noise[frame]
+		})
+		if measured := result.Spectral.SpectralTiltDbPerOct; math.Abs(measured-tc.tilt) > 0.5 {
+			t.Fatalf("expected a %+.1f dB/octave tilt, measured %+.1f", tc.tilt, measured)
+		}
+
+		issue := findIssue(t, result, haustorium.CheckSpectralTilt)
+		if issue.Detected != (tc.tilt != haustorium.SpectralTiltTypicalDbPerOct) ||
+			!strings.HasPrefix(issue.Summary, tc.summary) {
+			t.Fatalf("expected %q for a %+.1f dB/octave tilt, got: %s", tc.summary, tc.tilt, issue.Summary)
+		}
+	}
+}
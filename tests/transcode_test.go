@@ -1,6 +1,8 @@
 package tests_test
 
 import (
+	"math"
+	"math/rand/v2"
 	"testing"
 
 	"github.com/containerd/nerdctl/mod/tigron/expect"
@@ -8,6 +10,8 @@ import (
 
 	"github.com/farcloser/agar/pkg/agar"
 
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/types"
 	"github.com/farcloser/haustorium/tests/testutils"
 )
 
@@ -49,3 +53,90 @@ func TestLossyTranscode(t *testing.T) {
 
 	testCase.Run(t)
 }
+
+// birdieTrack returns music-like noise cut at 9 kHz, with four faint high band tones every 4096 frames (~93 ms):
+// at new frequencies each time, the birdies of a starved encoder, or at the same ones, a stable tone.
+func birdieTrack(stable bool) func(frame, channel int) float64 {
+	const segment = 4096
+
+	bed := bandLimitedNoise(44100, 10, 9000)
+	rng := rand.New(rand.NewPCG(7, 8))
+	freqs := make([][4]float64, len(bed)/segment+1)
+
+	for i := range freqs {
+		for j := range freqs[i] {
+			freqs[i][j] = 11000 + 2000*float64(j) + 2000*rng.Float64()
+			if stable {
+				freqs[i][j] = 11000 + 2000*float64(j) + 1000
+			}
+		}
+	}
+
+	return func(frame, _ int) float64 {
+		at := frame % segment
+		envelope := 0.01 * math.Sin(math.Pi*float64(at)/segment) * math.Sin(math.Pi*float64(at)/segment)
+		value := bed[frame]
+
+		for _, freq := range freqs[frame/segment] {
+			value += envelope * math.Sin(2*math.Pi*freq*float64(frame)/44100)
+		}
+
+		return value
+	}
+}
+
+func TestBirdiesFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	warbling := analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 10, birdieTrack(false)).Spectral
+	if !warbling.HasBirdies {
+		t.Fatalf("expected birdies from high band tones changing every 93 ms, got %.0f%% of the windows",
+			100*warbling.BirdieWindowShare)
+	}
+
+	steady := analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 10, birdieTrack(true)).Spectral
+	if steady.HasBirdies {
+		t.Fatalf("expected stable high band tones not to be birdies, got %.0f%% of the windows",
+			100*steady.BirdieWindowShare)
+	}
+}
+
+// refilledTrack returns noise cut at 16 kHz, its level swelling and fading as music does, over a 17 kHz high band
+// 20 dB down: steady noise laid by a noise fill when refilled, or following the level of the music when recorded.
+func refilledTrack(refilled bool) func(frame, channel int) float64 {
+	// The same noise, cut at different frequencies: the high band is what a 17 kHz cut removes.
+	full := bandLimitedNoise(44100, 10, 22050)
+	low := bandLimitedNoise(44100, 10, 16000)
+	below := bandLimitedNoise(44100, 10, 17000)
+
+	return func(frame, _ int) float64 {
+		level := 0.3 + 0.7*math.Abs(math.Sin(2*math.Pi*0.3*float64(frame)/44100))
+		high := 0.1 * (full[frame] - below[frame])
+
+		if refilled {
+			return level*low[frame] + high
+		}
+
+		return level * (low[frame] + high)
+	}
+}
+
+func TestHfRegenerationFixture(t *testing.T) {
+	t.Parallel()
+
+	format := types.PCMFormat{SampleRate: 44100, BitDepth: types.Depth24, Channels: 2}
+
+	refilled := analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 10, refilledTrack(true)).Spectral
+	if !refilled.HfRegenerationLikely {
+		t.Fatalf("expected a steady noise high band to be regenerated, got flatness %.2f, correlation %.2f",
+			refilled.HfFlatness, refilled.HfEnvelopeCorrelation)
+	}
+
+	recorded := analyzeSynthesized(t, haustorium.CheckLossyTranscode, format, 10, refilledTrack(false)).Spectral
+	if recorded.HfRegenerationLikely {
+		t.Fatalf("expected a high band following the music not to be regenerated, got flatness %.2f, "+
+			"correlation %.2f", recorded.HfFlatness, recorded.HfEnvelopeCorrelation)
+	}
+}
@@ -0,0 +1,47 @@
+package tests_test
+
+import (
+	"testing"
+
+	"github.com/farcloser/haustorium"
+	"github.com/farcloser/haustorium/internal/testutil"
+)
+
+// TestTrimEdgesLeadInThump puts a needle-drop thump (a clipped burst) in the first second of a transfer: trimming
+// the edges drops it from the defect checks, while loudness still measures the whole track, thump included.
+func TestTrimEdgesLeadInThump(t *testing.T) {
+	t.Parallel()
+
+	factory, format := testutil.Fixture(testutil.Spec{
+		Seconds: 10,
+		Defects: []testutil.Defect{{Kind: testutil.DefectClip, AtSec: 0.5, Channel: testutil.AllChannels}},
+	})
+
+	analyze := func(trimSec float64) *haustorium.Result {
+		opts := haustorium.DefaultDigitalOptions()
+		opts.Checks = haustorium.CheckClipping | haustorium.CheckLoudness
+		opts.TrimEdgesSec = trimSec
+
+		result, err := haustorium.Analyze(factory, format, opts)
+		if err != nil {
+			t.Fatalf("analysis failed: %v", err)
+		}
+
+		return result
+	}
+
+	whole, trimmed := analyze(0), analyze(2)
+
+	if clipping := findIssue(t, whole, haustorium.CheckClipping); !clipping.Detected {
+		t.Fatalf("expected the thump to clip untrimmed, got: %s", clipping.Summary)
+	}
+
+	if clipping := findIssue(t, trimmed, haustorium.CheckClipping); clipping.Detected {
+		t.Fatalf("expected the trimmed edges to drop the thump, got: %s", clipping.Summary)
+	}
+
+	if *trimmed.Loudness != *whole.Loudness {
+		t.Fatalf("expected the loudness of the whole track, thump included: momentary max %.2f LUFS trimmed, "+
+			"%.2f LUFS whole", trimmed.Loudness.MomentaryMax, whole.Loudness.MomentaryMax)
+	}
+}